@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/chain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma"
+	"github.com/joho/godotenv"
+)
+
+// go run ./cmd/chainverify --chain=audit
+//
+// Проверяет цепочки хешей истории статей и журнала аудита. Код выхода 1 —
+// цепочка нарушена, 2 — проверку не удалось выполнить.
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	name := flag.String("chain", "", "history или audit; по умолчанию обе")
+	flag.Parse()
+	if err := godotenv.Load(".env"); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load .env:", err)
+		return 2
+	}
+
+	db, err := prisma.New()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect DB:", err)
+		return 2
+	}
+	defer db.Disconnect()
+
+	chains := domain.Chains
+	if *name != "" {
+		chains = []domain.Chain{domain.Chain(*name)}
+	}
+	chainINT := chain.NewChainInteractor(db)
+	code := 0
+	for _, c := range chains {
+		report, err := chainINT.Walk(context.Background(), c)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", c, err)
+			code = 2
+			continue
+		}
+		if report.Valid {
+			fmt.Printf("%s: ok, %d records, head %d %s\n", c, report.Records, report.Head.Seq, report.Head.Hash)
+			continue
+		}
+		if report.Break != nil {
+			fmt.Printf("%s: broken at record %d (%s): %s; %d records verified before it\n",
+				c, report.Break.Seq, report.Break.RecordID, report.Break.Reason, report.Records)
+		}
+		for _, id := range report.Unchained {
+			fmt.Printf("%s: record %s was added without a chain link\n", c, id)
+		}
+		code = max(code, 1)
+	}
+	return code
+}
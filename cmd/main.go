@@ -1,18 +1,42 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/immxrtalbeast/TTK_backend/internal/config"
 	"github.com/immxrtalbeast/TTK_backend/internal/controller"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
 	"github.com/immxrtalbeast/TTK_backend/internal/middleware"
 	"github.com/immxrtalbeast/TTK_backend/internal/usecase/article"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/articlelink"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/audit"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/board"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/calendar"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/chain"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/checklist"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/collab"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/customfield"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/dependency"
 	"github.com/immxrtalbeast/TTK_backend/internal/usecase/history"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/label"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/lock"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/notification"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/project"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/recurrence"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/reminder"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/report"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/sprint"
 	"github.com/immxrtalbeast/TTK_backend/internal/usecase/task"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/tasktable"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/tasktemplate"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/timetrack"
 	"github.com/immxrtalbeast/TTK_backend/internal/usecase/user"
+	"github.com/immxrtalbeast/TTK_backend/internal/usecase/watch"
 	"github.com/immxrtalbeast/TTK_backend/storage/prisma"
 	"github.com/joho/godotenv"
 )
@@ -34,19 +58,73 @@ func main() {
 	}
 	defer db.Disconnect()
 	//TODO: validate data, implement more methods. think about history
-	userINT := user.NewUserInteractor(db, cfg.TokenTTL, cfg.AppSecret)
+	userINT := user.NewUserInteractor(db, cfg.TokenTTL, cfg.AppSecret, cfg.MaxReminderOffset)
 	userController := controller.NewUserController(userINT)
 	historyINT := history.NewHistoryInteractor(db)
 
 	historyController := controller.NewHistoryController(historyINT)
+	auditINT := audit.NewAuditInteractor(db, log)
+	chainINT := chain.NewChainInteractor(db)
+	auditController := controller.NewAuditController(auditINT, chainINT)
 
-	articleINT := article.NewArticleInteractor(db)
-	articleController := controller.NewArticleController(articleINT, historyINT, log)
+	articleINT := article.NewArticleInteractor(db, cfg.DefaultLanguage)
+	lockINT := lock.NewArticleLockInteractor(db, db, log, cfg.LockDefaultTTL, cfg.LockMaxTTL, cfg.LockReapInterval)
+	lockController := controller.NewArticleLockController(lockINT)
+	go lockINT.Run(context.Background())
+	articleController := controller.NewArticleController(articleINT, historyINT, lockINT, log)
 
-	taskINT := task.NewTaskInteractor(db)
-	taskController := controller.NewTaskController(taskINT, historyINT)
+	collabINT := collab.NewCollabInteractor(articleINT, lockINT, historyINT, auditINT, log, cfg.CollabPersistInterval)
+	collabController := controller.NewCollabController(collabINT, log)
+	go collabINT.Run(context.Background())
+
+	notificationINT := notification.NewNotificationInteractor(db, log, notification.NewLogNotifier(log))
+	notificationController := controller.NewNotificationController(notificationINT)
+	watchINT := watch.NewWatchInteractor(db, notificationINT, log)
+	watchController := controller.NewWatchController(watchINT)
+
+	transitions := domain.NewTransitionGraph(transitionRules(cfg.TaskTransitions))
+	transitions.CloseSubtasksFirst = cfg.CloseSubtasksFirst
+	taskINT := task.NewTaskInteractor(db, db, transitions, watchINT)
+	taskController := controller.NewTaskController(taskINT)
+	boardINT := board.NewBoardInteractor(db, db, transitions, wipLimits(cfg.BoardWIPLimits), cfg.BoardColumnSize, watchINT)
+	boardController := controller.NewBoardController(boardINT)
+	checklistINT := checklist.NewChecklistInteractor(db, db)
+	checklistController := controller.NewChecklistController(checklistINT)
+	dependencyINT := dependency.NewDependencyInteractor(db)
+	dependencyController := controller.NewDependencyController(dependencyINT)
+	recurrenceINT := recurrence.NewRecurrenceInteractor(db, db, log, cfg.RecurrenceInterval, cfg.RecurrenceLead)
+	recurrenceController := controller.NewRecurrenceController(recurrenceINT)
+	go recurrenceINT.Run(context.Background())
+	reminderINT := reminder.NewReminderInteractor(db, notificationINT, log, cfg.ReminderInterval, cfg.ReminderOffsets, cfg.MaxReminderOffset, cfg.EscalationDelay)
+	go reminderINT.Run(context.Background())
+	calendarINT := calendar.NewCalendarInteractor(db, cfg.CalendarCompletedWindow)
+	calendarController := controller.NewCalendarController(calendarINT)
+	timesheetLocation, err := time.LoadLocation(cfg.TimesheetTimezone)
+	if err != nil {
+		panic("invalid timesheet timezone: " + err.Error())
+	}
+	timeEntryINT := timetrack.NewTimeEntryInteractor(db, timesheetLocation)
+	timeEntryController := controller.NewTimeEntryController(timeEntryINT)
+	sprintINT := sprint.NewSprintInteractor(db, timesheetLocation)
+	sprintController := controller.NewSprintController(sprintINT)
+	labelINT := label.NewLabelInteractor(db)
+	labelController := controller.NewLabelController(labelINT)
+	customFieldINT := customfield.NewCustomFieldInteractor(db)
+	customFieldController := controller.NewCustomFieldController(customFieldINT)
+	projectINT := project.NewProjectInteractor(db)
+	projectController := controller.NewProjectController(projectINT)
+	articleLinkINT := articlelink.NewArticleLinkInteractor(db)
+	articleLinkController := controller.NewArticleLinkController(articleLinkINT)
+	taskTemplateINT := tasktemplate.NewTaskTemplateInteractor(db)
+	taskTemplateController := controller.NewTaskTemplateController(taskTemplateINT)
+	taskTableINT := tasktable.NewTaskTableInteractor(taskINT, db)
+	taskTableController := controller.NewTaskTableController(taskTableINT)
+	reportINT := report.NewReportInteractor(db)
+	reportController := controller.NewReportController(reportINT)
 
 	authMiddleware := middleware.AuthMiddleware(cfg.AppSecret)
+	auditUsers := middleware.Audit(auditINT, domain.AuditUser)
+	auditSettings := middleware.Audit(auditINT, domain.AuditSettings)
 	router := gin.Default()
 
 	config := cors.DefaultConfig()
@@ -57,24 +135,83 @@ func main() {
 		"Content-Type",
 		"Origin",
 		"Accept",
+		middleware.RequestIDHeader,
 	}
+	config.ExposeHeaders = []string{middleware.RequestIDHeader}
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
 	router.Use(cors.New(config))
+	router.Use(middleware.RequestID())
 	api := router.Group("/api/v1")
 	{
 		article := api.Group("/article")
-		article.Use(authMiddleware)
+		article.Use(authMiddleware, middleware.Audit(auditINT, domain.AuditArticle))
 		{
 			article.POST("/create", articleController.CreateArticle)
 			article.GET("/:id", articleController.Article)
+			article.GET("/:id/translations", articleController.Translations)
+			article.GET("/:id/history", historyController.ArticleHistory)
+			article.GET("/:id/tasks", articleLinkController.ArticleTasks)
+			article.POST("/:id/translation", articleController.CreateTranslation)
+			article.GET("/:id/ws", collabController.Edit)
+			article.POST("/update", articleController.UpdateArticle)
 			article.GET("/show", articleController.Articles)
 			article.DELETE("/:id", articleController.DeleteArticle)
 		}
+		// блокировки не меняют статью и в журнал аудита не попадают
+		articleLock := api.Group("/article")
+		articleLock.Use(authMiddleware)
+		{
+			articleLock.GET("/:id/lock", lockController.Lock)
+			articleLock.POST("/:id/lock", lockController.Acquire)
+			articleLock.POST("/:id/lock/heartbeat", lockController.Heartbeat)
+			articleLock.DELETE("/:id/lock", lockController.Release)
+		}
 		task := api.Group("/task")
-		task.Use(authMiddleware)
+		task.Use(authMiddleware, middleware.Audit(auditINT, domain.AuditTask))
 		{
 			task.POST("/create", taskController.CreateTask)
+			task.POST("/bulk", taskController.BulkUpdate)
+			task.GET("/export", taskTableController.Export)
+			task.POST("/import", taskTableController.Import)
+			task.GET("/mine", taskController.MyTasks)
+			task.GET("/assigned-by-me", taskController.AssignedByMe)
+			task.GET("/board", boardController.Board)
+			task.GET("/overdue", taskController.Overdue)
+			task.GET("/critical-path", dependencyController.CriticalPath)
+			task.GET("/key/:key", taskController.TaskByKey)
+			task.POST("/recurrences", recurrenceController.CreateRecurrence)
+			task.GET("/recurrences", recurrenceController.Recurrences)
+			task.GET("/recurrences/:id", recurrenceController.Recurrence)
+			task.DELETE("/recurrences/:id", recurrenceController.StopRecurrence)
 			task.GET("/:id", taskController.Task)
+			task.POST("/:id/assign", taskController.AssignTask)
+			task.POST("/:id/transition", taskController.TransitionTask)
+			task.POST("/:id/move", boardController.MoveTask)
+			task.GET("/:id/transitions", taskController.Transitions)
+			task.GET("/:id/assignments", taskController.Assignments)
+			task.GET("/:id/subtasks", taskController.Subtasks)
+			task.POST("/:id/parent", taskController.SetParent)
+			task.GET("/:id/checklist", checklistController.Checklist)
+			task.POST("/:id/checklist", checklistController.AddItem)
+			task.POST("/:id/checklist/order", checklistController.ReorderItems)
+			task.PUT("/:id/checklist/:itemID", checklistController.UpdateItem)
+			task.DELETE("/:id/checklist/:itemID", checklistController.DeleteItem)
+			task.POST("/:id/dependencies", dependencyController.AddDependency)
+			task.DELETE("/:id/dependencies/:blockerID", dependencyController.RemoveDependency)
+			task.POST("/:id/timer/start", timeEntryController.StartTimer)
+			task.POST("/:id/time", timeEntryController.AddEntry)
+			task.GET("/:id/time", timeEntryController.TaskEntries)
+			task.POST("/:id/sprint", sprintController.PlanTask)
+			task.PUT("/:id/labels", labelController.SetTaskLabels)
+			task.PUT("/:id/fields", customFieldController.SetTaskFields)
+			task.POST("/:id/watch", watchController.Watch)
+			task.DELETE("/:id/watch", watchController.Unwatch)
+			task.GET("/:id/watchers", watchController.Watchers)
+			task.POST("/:id/comments", watchController.AddComment)
+			task.GET("/:id/comments", watchController.Comments)
+			task.GET("/:id/articles", articleLinkController.TaskArticles)
+			task.PUT("/:id/articles/:articleID", articleLinkController.LinkArticle)
+			task.DELETE("/:id/articles/:articleID", articleLinkController.UnlinkArticle)
 			task.GET("/show", taskController.Tasks)
 			task.POST("/update", taskController.UpdateTask)
 			task.DELETE("/:id", taskController.DeleteTask)
@@ -84,13 +221,104 @@ func main() {
 		{
 			history.GET("/articles", historyController.HistoryArticles)
 		}
-		api.POST("/register", userController.CreateUser)
+		notifications := api.Group("/notifications")
+		notifications.Use(authMiddleware)
+		{
+			notifications.GET("", notificationController.Notifications)
+			notifications.POST("/:id/read", notificationController.MarkRead)
+			notifications.POST("/read-all", notificationController.MarkAllRead)
+		}
+		templates := api.Group("/templates")
+		templates.Use(authMiddleware, auditSettings)
+		{
+			templates.POST("", taskTemplateController.CreateTemplate)
+			templates.GET("", taskTemplateController.Templates)
+			templates.GET("/:id", taskTemplateController.Template)
+			templates.DELETE("/:id", taskTemplateController.DeleteTemplate)
+		}
+		projects := api.Group("/project")
+		projects.Use(authMiddleware, auditSettings)
+		{
+			projects.POST("", projectController.CreateProject)
+			projects.GET("", projectController.Projects)
+			projects.GET("/:id", projectController.Project)
+			projects.GET("/:id/tasks", projectController.Tasks)
+			projects.PUT("/:id/members/:userID", projectController.SetMember)
+			projects.DELETE("/:id/members/:userID", projectController.RemoveMember)
+		}
+		labels := api.Group("/labels")
+		labels.Use(authMiddleware, auditSettings)
+		{
+			labels.GET("", labelController.Labels)
+			labels.POST("", labelController.CreateLabel)
+			labels.PUT("/:id", labelController.UpdateLabel)
+			labels.DELETE("/:id", labelController.DeleteLabel)
+		}
+		fields := api.Group("/fields")
+		fields.Use(authMiddleware, auditSettings)
+		{
+			fields.GET("", customFieldController.Fields)
+			fields.POST("", customFieldController.CreateField)
+			fields.DELETE("/:id", customFieldController.DeleteField)
+		}
+		sprints := api.Group("/sprint")
+		sprints.Use(authMiddleware, auditSettings)
+		{
+			sprints.POST("", sprintController.CreateSprint)
+			sprints.GET("", sprintController.Sprints)
+			sprints.GET("/velocity", sprintController.Velocity)
+			sprints.GET("/:id", sprintController.Sprint)
+			sprints.PUT("/:id", sprintController.UpdateSprint)
+			sprints.DELETE("/:id", sprintController.DeleteSprint)
+			sprints.GET("/:id/burndown", sprintController.Burndown)
+		}
+		api.GET("/audit", authMiddleware, auditController.Entries)
+		api.GET("/audit/verify", authMiddleware, auditController.Verify)
+		reports := api.Group("/report")
+		reports.Use(authMiddleware)
+		{
+			reports.GET("/users", reportController.Users)
+			reports.GET("/users/:id", reportController.User)
+			reports.GET("/projects/:id", reportController.Project)
+		}
+		timeEntries := api.Group("/time")
+		timeEntries.Use(authMiddleware, middleware.Audit(auditINT, domain.AuditTask))
+		{
+			timeEntries.POST("/stop", timeEntryController.StopTimer)
+			timeEntries.GET("/running", timeEntryController.RunningTimer)
+			timeEntries.GET("/timesheet", timeEntryController.Timesheet)
+			timeEntries.DELETE("/:id", timeEntryController.DeleteEntry)
+		}
+		api.PUT("/user/reminders", authMiddleware, auditSettings, userController.SetReminders)
+		api.POST("/user/feed-token", authMiddleware, auditSettings, calendarController.RotateToken)
+		api.GET("/calendar/:token/tasks.ics", calendarController.Feed)
+		api.POST("/register", auditUsers, userController.CreateUser)
 		api.GET("/user/:id", userController.User)
 		api.POST("/login", userController.Login)
 	}
 	router.Run(":8080")
 
 }
+func transitionRules(transitions []config.TaskTransition) []domain.TransitionRule {
+	rules := make([]domain.TransitionRule, 0, len(transitions))
+	for _, t := range transitions {
+		rule := domain.TransitionRule{From: domain.Status(t.From), To: domain.Status(t.To)}
+		for _, role := range t.Roles {
+			rule.Roles = append(rule.Roles, domain.Role(role))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func wipLimits(limits map[string]int) map[domain.Status]int {
+	result := make(map[domain.Status]int, len(limits))
+	for status, limit := range limits {
+		result[domain.Status(status)] = limit
+	}
+	return result
+}
+
 func setupLogger() *slog.Logger {
 	var log *slog.Logger
 
@@ -13,6 +13,41 @@ type Config struct {
 	StoragePath string        `yaml:"storage_path" env-required:"true"`
 	TokenTTL    time.Duration `yaml:"token_ttl" env-default:"1h"`
 	AppSecret   string        `yaml:"app_secret" env-required:"true"`
+	// DefaultLanguage — язык статей по умолчанию и запасной вариант при выборе перевода.
+	DefaultLanguage string `yaml:"default_language" env-default:"ru"`
+	// CollabPersistInterval — как часто документ совместного редактирования сохраняется ревизией.
+	CollabPersistInterval time.Duration `yaml:"collab_persist_interval" env-default:"30s"`
+	// Аренда статьи на редактирование: срок по умолчанию, максимальный срок и период очистки истёкших.
+	LockDefaultTTL   time.Duration `yaml:"lock_default_ttl" env-default:"5m"`
+	LockMaxTTL       time.Duration `yaml:"lock_max_ttl" env-default:"60m"`
+	LockReapInterval time.Duration `yaml:"lock_reap_interval" env-default:"1m"`
+	// TaskTransitions — граф переходов статусов задач. Пустой — используется граф по умолчанию.
+	TaskTransitions []TaskTransition `yaml:"task_transitions"`
+	// Доска задач: WIP-лимиты колонок по статусу (0 или отсутствие — без ограничения;
+	// считается отдельно для доски каждого проекта) и сколько задач колонки отдаётся в одном ответе.
+	BoardWIPLimits  map[string]int `yaml:"board_wip_limits"`
+	BoardColumnSize int            `yaml:"board_column_size" env-default:"50"`
+	// CloseSubtasksFirst запрещает завершать задачу, пока открыты её подзадачи.
+	CloseSubtasksFirst bool `yaml:"close_subtasks_first" env-default:"true"`
+	// Повторяющиеся задачи: период проверки правил и за сколько до даты создаётся вхождение.
+	RecurrenceInterval time.Duration `yaml:"recurrence_interval" env-default:"1m"`
+	RecurrenceLead     time.Duration `yaml:"recurrence_lead" env-default:"24h"`
+	// Напоминания о сроках: период проверки, смещения в часах по умолчанию,
+	// наибольшее смещение, которое может задать пользователь, и задержка эскалации просрочки.
+	ReminderInterval  time.Duration `yaml:"reminder_interval" env-default:"1m"`
+	ReminderOffsets   []int         `yaml:"reminder_offsets" env-default:"24,1"`
+	MaxReminderOffset int           `yaml:"max_reminder_offset" env-default:"168"`
+	EscalationDelay   time.Duration `yaml:"escalation_delay" env-default:"1h"`
+	// CalendarCompletedWindow — сколько завершённые задачи остаются в ленте календаря.
+	CalendarCompletedWindow time.Duration `yaml:"calendar_completed_window" env-default:"720h"`
+	// TimesheetTimezone — часовой пояс, по которому табель и burndown спринта делятся на дни.
+	TimesheetTimezone string `yaml:"timesheet_timezone" env-default:"Europe/Moscow"`
+}
+
+type TaskTransition struct {
+	From  string   `yaml:"from"`
+	To    string   `yaml:"to"`
+	Roles []string `yaml:"roles"`
 }
 
 func MustLoad() *Config {
@@ -1,22 +1,25 @@
 package controller
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
 )
 
 type ArticleController struct {
 	interactor  domain.ArticleInteractor
 	hInteractor domain.HistoryInteractor
+	lInteractor domain.ArticleLockInteractor
 	log         *slog.Logger
 }
 
-func NewArticleController(interactor domain.ArticleInteractor, hinteractor domain.HistoryInteractor, log *slog.Logger) *ArticleController {
-	return &ArticleController{interactor: interactor, hInteractor: hinteractor, log: log}
+func NewArticleController(interactor domain.ArticleInteractor, hinteractor domain.HistoryInteractor, linteractor domain.ArticleLockInteractor, log *slog.Logger) *ArticleController {
+	return &ArticleController{interactor: interactor, hInteractor: hinteractor, lInteractor: linteractor, log: log}
 }
 
 func (c *ArticleController) Article(ctx *gin.Context) {
@@ -25,7 +28,13 @@ func (c *ArticleController) Article(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
 		return
 	}
-	article, err := c.interactor.Article(ctx, idStr)
+	var article *domain.Article
+	var err error
+	if languages := requestLanguages(ctx); len(languages) > 0 {
+		article, err = c.interactor.ArticleVariant(ctx, idStr, languages)
+	} else {
+		article, err = c.interactor.Article(ctx, idStr)
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to get article",
@@ -39,6 +48,77 @@ func (c *ArticleController) Article(ctx *gin.Context) {
 
 }
 
+func (c *ArticleController) Translations(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	if idStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	articles, err := c.interactor.Translations(ctx, idStr)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get translations",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"articles": articles,
+	})
+}
+
+func (c *ArticleController) CreateTranslation(ctx *gin.Context) {
+	type CreateTranslationRequest struct {
+		Language string `json:"language" binding:"required"`
+		Title    string `json:"title" binding:"required,min=3,max=50"`
+		Image    string `json:"image"`
+		Content  string `json:"content"`
+	}
+	idStr := ctx.Param("id")
+	if idStr == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	var req CreateTranslationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	article, err := c.interactor.CreateTranslation(ctx, idStr, req.Language, req.Title, req.Image, req.Content, userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidLanguage) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, domain.ErrTranslationExists) {
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{
+			"error":   "failed to create translation",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := c.hInteractor.InitHistory(ctx, article.ID, userID, req.Title); err != nil {
+		c.log.Error("failed to create history", slog.String("article", article.ID), slog.String("error", err.Error()))
+	}
+	auditEntry(ctx, &domain.AuditEntry{EntityID: article.ID, Action: domain.AuditCreate, After: article.AuditFields()})
+	ctx.JSON(http.StatusOK, gin.H{
+		"article": article,
+	})
+}
+
+// requestLanguages возвращает предпочитаемые языки из ?lang= или заголовка Accept-Language.
+func requestLanguages(ctx *gin.Context) []string {
+	if lang := lib.NormalizeLanguage(ctx.Query("lang")); lang != "" {
+		return []string{lang}
+	}
+	return lib.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+}
+
 func (c *ArticleController) Articles(ctx *gin.Context) {
 	pageStr := ctx.DefaultQuery("p", "1")
 	limitStr := ctx.DefaultQuery("limit", "6")
@@ -59,9 +139,10 @@ func (c *ArticleController) Articles(ctx *gin.Context) {
 
 func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 	type CreateArticleRequest struct {
-		Title   string `json:"title" binding:"required,min=3,max=50"`
-		Image   string `json:"image"`
-		Content string `json:"content"`
+		Title    string `json:"title" binding:"required,min=3,max=50"`
+		Image    string `json:"image"`
+		Content  string `json:"content"`
+		Language string `json:"language"`
 	}
 	var req CreateArticleRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -72,14 +153,19 @@ func (c *ArticleController) CreateArticle(ctx *gin.Context) {
 		return
 	}
 	userID, _ := ctx.Keys["userID"].(string)
-	article, err := c.interactor.CreateArticle(ctx, req.Title, req.Image, req.Content, userID)
+	article, err := c.interactor.CreateArticle(ctx, req.Title, req.Image, req.Content, userID, req.Language)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidLanguage) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
 			"error":   "failed to create article",
 			"details": err.Error(),
 		})
 		return
 	}
+	auditEntry(ctx, &domain.AuditEntry{EntityID: article.ID, After: article.AuditFields()})
 	err = c.hInteractor.InitHistory(ctx, article.ID, userID, req.Title)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -111,6 +197,9 @@ func (c *ArticleController) DeleteArticle(ctx *gin.Context) {
 		return
 	}
 	c.hInteractor.UpdateHistory(ctx, id, userID, domain.EventType("DELETE"), article.Title)
+	if article != nil {
+		auditEntry(ctx, &domain.AuditEntry{Before: article.AuditFields()})
+	}
 	ctx.JSON(http.StatusOK, gin.H{})
 }
 func (c *ArticleController) UpdateArticle(ctx *gin.Context) {
@@ -130,6 +219,21 @@ func (c *ArticleController) UpdateArticle(ctx *gin.Context) {
 	}
 	userName, _ := ctx.Keys["userName"].(string)
 	userID, _ := ctx.Keys["userID"].(string)
+	if err := c.lInteractor.CheckWrite(ctx, req.ID, userID); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrArticleLocked) {
+			status = http.StatusLocked
+		}
+		ctx.JSON(status, gin.H{
+			"error":   "failed to update article",
+			"details": err.Error(),
+		})
+		return
+	}
+	var before map[string]any
+	if current, err := c.interactor.Article(ctx, req.ID); err == nil {
+		before = current.AuditFields()
+	}
 	article, err := c.interactor.UpdateArticle(ctx, req.ID, req.Title, req.Image, req.Content, userName)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
@@ -138,6 +242,7 @@ func (c *ArticleController) UpdateArticle(ctx *gin.Context) {
 		})
 		return
 	}
+	auditEntry(ctx, &domain.AuditEntry{EntityID: article.ID, Action: domain.AuditUpdate, Before: before, After: article.AuditFields()})
 	c.hInteractor.UpdateHistory(ctx, article.ID, userID, domain.EventType("UPDATED"), article.Title)
 	ctx.JSON(http.StatusOK, gin.H{
 		"article": article,
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type ArticleLinkController struct {
+	interactor domain.ArticleLinkInteractor
+}
+
+func NewArticleLinkController(interactor domain.ArticleLinkInteractor) *ArticleLinkController {
+	return &ArticleLinkController{interactor: interactor}
+}
+
+// LinkArticle связывает задачу со статьёй: kind — instruction, result или reference.
+func (c *ArticleLinkController) LinkArticle(ctx *gin.Context) {
+	type LinkArticleRequest struct {
+		Kind string `json:"kind" binding:"required"`
+	}
+	var req LinkArticleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	link, err := c.interactor.LinkArticle(ctx, ctx.Param("id"), ctx.Param("articleID"), domain.ArticleLinkKind(req.Kind), actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to link article",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"link": link,
+	})
+}
+
+func (c *ArticleLinkController) UnlinkArticle(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.UnlinkArticle(ctx, ctx.Param("id"), ctx.Param("articleID"), actorID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to unlink article",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *ArticleLinkController) TaskArticles(ctx *gin.Context) {
+	links, err := c.interactor.TaskArticles(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get task articles",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"articles": links,
+	})
+}
+
+func (c *ArticleLinkController) ArticleTasks(ctx *gin.Context) {
+	links, err := c.interactor.ArticleTasks(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get article tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"tasks": links,
+	})
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type ArticleLockController struct {
+	interactor domain.ArticleLockInteractor
+}
+
+func NewArticleLockController(interactor domain.ArticleLockInteractor) *ArticleLockController {
+	return &ArticleLockController{interactor: interactor}
+}
+
+func (c *ArticleLockController) Lock(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	lock, err := c.interactor.Lock(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get lock",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"lock": lock,
+	})
+}
+
+func (c *ArticleLockController) Acquire(ctx *gin.Context) {
+	type AcquireLockRequest struct {
+		Minutes int `json:"minutes" binding:"omitempty,min=1"`
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	var req AcquireLockRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	userName, _ := ctx.Keys["userName"].(string)
+	lock, err := c.interactor.Acquire(ctx, id, userID, userName, time.Duration(req.Minutes)*time.Minute)
+	if err != nil {
+		if errors.Is(err, domain.ErrArticleLocked) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"error": "article is locked",
+				"lock":  lock,
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to lock article",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"lock": lock,
+	})
+}
+
+func (c *ArticleLockController) Heartbeat(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	lock, err := c.interactor.Heartbeat(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrLockNotHeld) {
+			ctx.JSON(http.StatusConflict, gin.H{
+				"error": "lock is not held",
+				"lock":  lock,
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to extend lock",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"lock": lock,
+	})
+}
+
+// Release снимает свою блокировку; с ?force=true администратор снимает любую.
+func (c *ArticleLockController) Release(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	var err error
+	if ctx.Query("force") == "true" {
+		err = c.interactor.Break(ctx, id, userID)
+	} else {
+		err = c.interactor.Release(ctx, id, userID)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrAdminRequired) {
+			status = http.StatusForbidden
+		} else if errors.Is(err, domain.ErrLockNotHeld) {
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{
+			"error":   "failed to release lock",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type BoardController struct {
+	interactor domain.BoardInteractor
+}
+
+func NewBoardController(interactor domain.BoardInteractor) *BoardController {
+	return &BoardController{interactor: interactor}
+}
+
+// Board возвращает колонки доски. Принимает те же фильтры, что и список задач;
+// status ограничивает набор колонок.
+func (c *BoardController) Board(ctx *gin.Context) {
+	filter, err := taskFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	columns, err := c.interactor.Board(ctx, userID, filter)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get board",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"columns": columns,
+	})
+}
+
+// MoveTask переносит задачу в колонку status между after_id (выше) и before_id (ниже).
+// Без соседей задача встаёт в конец колонки.
+func (c *BoardController) MoveTask(ctx *gin.Context) {
+	type MoveTaskRequest struct {
+		Status   domain.Status `json:"status" binding:"required"`
+		AfterID  string        `json:"after_id"`
+		BeforeID string        `json:"before_id"`
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	var req MoveTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	status := domain.Status(strings.ToUpper(string(req.Status)))
+	task, err := c.interactor.MoveTask(ctx, id, status, req.AfterID, req.BeforeID, actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to move task",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"task": task,
+	})
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type CalendarController struct {
+	interactor domain.CalendarInteractor
+}
+
+func NewCalendarController(interactor domain.CalendarInteractor) *CalendarController {
+	return &CalendarController{interactor: interactor}
+}
+
+// Feed отдаёт ленту задач в формате iCalendar. Доступ — по секретному токену в пути,
+// без JWT: календарные клиенты не умеют передавать заголовок авторизации.
+// kind=event выдаёт события вместо задач (VTODO).
+func (c *CalendarController) Feed(ctx *gin.Context) {
+	feed, err := c.interactor.Feed(ctx, ctx.Param("token"), domain.CalendarKind(ctx.Query("kind")))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, domain.ErrFeedNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, domain.ErrInvalidCalendarKind):
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"error":   "failed to build calendar",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// RotateToken выдаёт текущему пользователю новую ссылку на ленту, старая перестаёт работать.
+func (c *CalendarController) RotateToken(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	token, err := c.interactor.RotateToken(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to rotate feed token",
+			"details": err.Error(),
+		})
+		return
+	}
+	// сам токен в журнал не попадает
+	auditEntry(ctx, &domain.AuditEntry{EntityID: userID, Action: domain.AuditUpdate})
+	ctx.JSON(http.StatusOK, gin.H{
+		"token": token,
+		"url":   "/api/v1/calendar/" + token + "/tasks.ics",
+	})
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type ChecklistController struct {
+	interactor domain.ChecklistInteractor
+}
+
+func NewChecklistController(interactor domain.ChecklistInteractor) *ChecklistController {
+	return &ChecklistController{interactor: interactor}
+}
+
+func (c *ChecklistController) Checklist(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	items, err := c.interactor.Checklist(ctx, ctx.Param("id"), userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get checklist",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+func (c *ChecklistController) AddItem(ctx *gin.Context) {
+	type AddItemRequest struct {
+		Text string `json:"text" binding:"required,max=500"`
+	}
+	var req AddItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	item, err := c.interactor.AddItem(ctx, ctx.Param("id"), req.Text, actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to add checklist item",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"item": item,
+	})
+}
+
+// UpdateItem меняет только переданные поля: text и/или done.
+func (c *ChecklistController) UpdateItem(ctx *gin.Context) {
+	type UpdateItemRequest struct {
+		Text *string `json:"text" binding:"omitempty,min=1,max=500"`
+		Done *bool   `json:"done"`
+	}
+	var req UpdateItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	item, err := c.interactor.UpdateItem(ctx, ctx.Param("id"), ctx.Param("itemID"), req.Text, req.Done, actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to update checklist item",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"item": item,
+	})
+}
+
+// ReorderItems принимает id всех пунктов в новом порядке.
+func (c *ChecklistController) ReorderItems(ctx *gin.Context) {
+	type ReorderItemsRequest struct {
+		IDs []string `json:"ids" binding:"required"`
+	}
+	var req ReorderItemsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	items, err := c.interactor.ReorderItems(ctx, ctx.Param("id"), req.IDs, actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to reorder checklist",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"items": items,
+	})
+}
+
+func (c *ChecklistController) DeleteItem(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.DeleteItem(ctx, ctx.Param("id"), ctx.Param("itemID"), actorID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to delete checklist item",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
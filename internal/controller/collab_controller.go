@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+	wsSendBuffer = 64
+)
+
+var errSlowClient = errors.New("client send buffer is full")
+
+type CollabController struct {
+	interactor domain.CollabInteractor
+	log        *slog.Logger
+	upgrader   websocket.Upgrader
+}
+
+func NewCollabController(interactor domain.CollabInteractor, log *slog.Logger) *CollabController {
+	return &CollabController{
+		interactor: interactor,
+		log:        log,
+		upgrader: websocket.Upgrader{
+			// CORS разрешён для всех источников, WebSocket ведёт себя так же.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Edit открывает WebSocket для совместного редактирования статьи.
+func (c *CollabController) Edit(ctx *gin.Context) {
+	articleID := ctx.Param("id")
+	if articleID == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing article ID"})
+		return
+	}
+	conn, err := c.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		c.log.Warn("failed to upgrade connection", slog.String("error", err.Error()))
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	userName, _ := ctx.Keys["userName"].(string)
+	client := newWSClient(conn, userID, userName)
+	go client.writeLoop()
+	defer client.close()
+
+	if err := c.interactor.Join(ctx, articleID, client); err != nil {
+		client.Send(domain.CollabMessage{Type: domain.CollabError, Error: err.Error()})
+		return
+	}
+	defer c.interactor.Leave(ctx, articleID, client.ID())
+
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		var msg domain.CollabMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				c.log.Warn("collab connection closed", slog.String("article", articleID), slog.String("error", err.Error()))
+			}
+			return
+		}
+		switch msg.Type {
+		case domain.CollabOperation:
+			if msg.Operation == nil {
+				err = errors.New("operation is required")
+				break
+			}
+			err = c.interactor.Submit(ctx, articleID, client.ID(), msg.Revision, *msg.Operation)
+		case domain.CollabCursor:
+			if msg.Cursor == nil {
+				err = errors.New("cursor is required")
+				break
+			}
+			err = c.interactor.UpdateCursor(ctx, articleID, client.ID(), *msg.Cursor)
+		default:
+			err = errors.New("unknown message type: " + msg.Type)
+		}
+		if err != nil {
+			client.Send(domain.CollabMessage{Type: domain.CollabError, Error: err.Error()})
+		}
+	}
+}
+
+// wsClient — участник совместного редактирования поверх WebSocket.
+// Сообщения пишутся отдельной горутиной, чтобы медленный клиент не тормозил остальных.
+type wsClient struct {
+	id       string
+	userID   string
+	userName string
+	conn     *websocket.Conn
+
+	mu     sync.Mutex
+	send   chan domain.CollabMessage
+	closed bool
+}
+
+func newWSClient(conn *websocket.Conn, userID string, userName string) *wsClient {
+	return &wsClient{
+		id:       newClientID(),
+		userID:   userID,
+		userName: userName,
+		conn:     conn,
+		send:     make(chan domain.CollabMessage, wsSendBuffer),
+	}
+}
+
+func (c *wsClient) ID() string       { return c.id }
+func (c *wsClient) UserID() string   { return c.userID }
+func (c *wsClient) UserName() string { return c.userName }
+
+func (c *wsClient) Send(msg domain.CollabMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return websocket.ErrCloseSent
+	}
+	select {
+	case c.send <- msg:
+		return nil
+	default:
+		// Клиент не успевает читать — разрываем соединение, он переподключится и получит актуальный документ.
+		c.conn.Close()
+		return errSlowClient
+	}
+}
+
+func (c *wsClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+func (c *wsClient) writeLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+	for {
+		select {
+		case msg, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func newClientID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
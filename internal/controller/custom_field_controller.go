@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type CustomFieldController struct {
+	interactor domain.CustomFieldInteractor
+}
+
+func NewCustomFieldController(interactor domain.CustomFieldInteractor) *CustomFieldController {
+	return &CustomFieldController{interactor: interactor}
+}
+
+// Fields возвращает общие поля; project=<id> добавляет поля проекта.
+func (c *CustomFieldController) Fields(ctx *gin.Context) {
+	fields, err := c.interactor.Fields(ctx, ctx.Query("project"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get custom fields",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"fields": fields,
+	})
+}
+
+// CreateField создаёт поле типа TEXT, NUMBER, DATE, ENUM (с options) или USER.
+func (c *CustomFieldController) CreateField(ctx *gin.Context) {
+	type CreateFieldRequest struct {
+		Name      string           `json:"name" binding:"required,max=50"`
+		Type      domain.FieldType `json:"type" binding:"required"`
+		Options   []string         `json:"options"`
+		ProjectID string           `json:"project_id"`
+	}
+	var req CreateFieldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	field, err := c.interactor.CreateField(ctx, actorID, &domain.CustomField{
+		Name:      req.Name,
+		Type:      req.Type,
+		Options:   req.Options,
+		ProjectID: req.ProjectID,
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create custom field",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{
+		EntityID: field.ID,
+		After: map[string]any{
+			"kind":       "field",
+			"name":       field.Name,
+			"type":       string(field.Type),
+			"options":    field.Options,
+			"project_id": field.ProjectID,
+		},
+	})
+	ctx.JSON(http.StatusOK, gin.H{
+		"field": field,
+	})
+}
+
+func (c *CustomFieldController) DeleteField(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.DeleteField(ctx, actorID, ctx.Param("id")); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to delete custom field",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// SetTaskFields принимает значения по id поля; пустая строка удаляет значение.
+func (c *CustomFieldController) SetTaskFields(ctx *gin.Context) {
+	type SetTaskFieldsRequest struct {
+		Values map[string]string `json:"values" binding:"required"`
+	}
+	var req SetTaskFieldsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	fields, err := c.interactor.SetTaskFields(ctx, ctx.Param("id"), req.Values)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to set task fields",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"fields": fields,
+	})
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type DependencyController struct {
+	interactor domain.DependencyInteractor
+}
+
+func NewDependencyController(interactor domain.DependencyInteractor) *DependencyController {
+	return &DependencyController{interactor: interactor}
+}
+
+// AddDependency отмечает, что задача :id заблокирована задачей blocker_id.
+func (c *DependencyController) AddDependency(ctx *gin.Context) {
+	type AddDependencyRequest struct {
+		BlockerID string `json:"blocker_id" binding:"required"`
+	}
+	var req AddDependencyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	dependency, err := c.interactor.AddDependency(ctx, req.BlockerID, ctx.Param("id"), actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to add dependency",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"dependency": dependency,
+	})
+}
+
+func (c *DependencyController) RemoveDependency(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.RemoveDependency(ctx, ctx.Param("blockerID"), ctx.Param("id"), actorID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to remove dependency",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// CriticalPath считает критический путь по срокам для задач из ids (через запятую).
+func (c *DependencyController) CriticalPath(ctx *gin.Context) {
+	ids := splitQuery(ctx.Query("ids"))
+	if len(ids) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task IDs"})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	path, err := c.interactor.CriticalPath(ctx, ids, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to calculate critical path",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"path":   path.Steps,
+		"finish": path.Finish,
+	})
+}
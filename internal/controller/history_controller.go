@@ -3,6 +3,7 @@ package controller
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
@@ -16,14 +17,36 @@ func NewHistoryController(interactor domain.HistoryInteractor) *HistoryControlle
 	return &HistoryController{interactor: interactor}
 }
 
+// HistoryArticles возвращает события всех статей с фильтрами article, user,
+// event (CREATE, UPDATED, DELETE) и периодом from/to (RFC3339 или YYYY-MM-DD).
 func (c *HistoryController) HistoryArticles(ctx *gin.Context) {
-	pageStr := ctx.DefaultQuery("p", "1")
-	limitStr := ctx.DefaultQuery("limit", "6")
-	page, _ := strconv.Atoi(pageStr)
-	limit, _ := strconv.Atoi(limitStr)
-	histories, err := c.interactor.Histories(ctx, page, limit)
+	filter, ok := historyFilter(ctx)
+	if !ok {
+		return
+	}
+	filter.ArticleID = ctx.Query("article")
+	histories, err := c.interactor.Histories(ctx, filter)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get history",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"data": histories,
+	})
+}
+
+// ArticleHistory возвращает события одной статьи с теми же фильтрами.
+func (c *HistoryController) ArticleHistory(ctx *gin.Context) {
+	filter, ok := historyFilter(ctx)
+	if !ok {
+		return
+	}
+	histories, err := c.interactor.ArticleHistory(ctx, ctx.Param("id"), filter)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		ctx.JSON(taskErrorStatus(err), gin.H{
 			"error":   "failed to get history",
 			"details": err.Error(),
 		})
@@ -33,3 +56,32 @@ func (c *HistoryController) HistoryArticles(ctx *gin.Context) {
 		"data": histories,
 	})
 }
+
+// historyFilter читает общие параметры выборки; order=asc — старые события первыми.
+func historyFilter(ctx *gin.Context) (domain.HistoryFilter, bool) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("p", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "6"))
+	filter := domain.HistoryFilter{
+		UserID:    ctx.Query("user"),
+		EventType: domain.EventType(strings.ToUpper(ctx.Query("event"))),
+		Ascending: strings.EqualFold(ctx.Query("order"), "asc"),
+		Page:      page,
+		Limit:     limit,
+	}
+	var err error
+	if filter.From, err = parseQueryTime(ctx.Query("from"), false); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid from",
+			"details": err.Error(),
+		})
+		return filter, false
+	}
+	if filter.To, err = parseQueryTime(ctx.Query("to"), true); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid to",
+			"details": err.Error(),
+		})
+		return filter, false
+	}
+	return filter, true
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type LabelController struct {
+	interactor domain.LabelInteractor
+}
+
+func NewLabelController(interactor domain.LabelInteractor) *LabelController {
+	return &LabelController{interactor: interactor}
+}
+
+type labelRequest struct {
+	Name      string `json:"name" binding:"required,max=50"`
+	Color     string `json:"color" binding:"required"`
+	ProjectID string `json:"project_id"`
+}
+
+// Labels возвращает общие метки; project=<id> добавляет метки проекта.
+func (c *LabelController) Labels(ctx *gin.Context) {
+	labels, err := c.interactor.Labels(ctx, ctx.Query("project"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get labels",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"labels": labels,
+	})
+}
+
+func (c *LabelController) CreateLabel(ctx *gin.Context) {
+	var req labelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	label, err := c.interactor.CreateLabel(ctx, actorID, &domain.Label{
+		Name:      req.Name,
+		Color:     req.Color,
+		ProjectID: req.ProjectID,
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create label",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{
+		EntityID: label.ID,
+		After: map[string]any{
+			"kind":       "label",
+			"name":       label.Name,
+			"color":      label.Color,
+			"project_id": label.ProjectID,
+		},
+	})
+	ctx.JSON(http.StatusOK, gin.H{
+		"label": label,
+	})
+}
+
+func (c *LabelController) UpdateLabel(ctx *gin.Context) {
+	var req labelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	label, err := c.interactor.UpdateLabel(ctx, actorID, &domain.Label{ID: ctx.Param("id"), Name: req.Name, Color: req.Color})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to update label",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"label": label,
+	})
+}
+
+func (c *LabelController) DeleteLabel(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.DeleteLabel(ctx, actorID, ctx.Param("id")); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to delete label",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// SetTaskLabels заменяет метки задачи; пустой список снимает все метки.
+func (c *LabelController) SetTaskLabels(ctx *gin.Context) {
+	type SetTaskLabelsRequest struct {
+		LabelIDs []string `json:"label_ids"`
+	}
+	var req SetTaskLabelsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	labels, err := c.interactor.SetTaskLabels(ctx, ctx.Param("id"), req.LabelIDs)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to set task labels",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"labels": labels,
+	})
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type NotificationController struct {
+	interactor domain.NotificationInteractor
+}
+
+func NewNotificationController(interactor domain.NotificationInteractor) *NotificationController {
+	return &NotificationController{interactor: interactor}
+}
+
+// Notifications возвращает уведомления текущего пользователя, новые первыми;
+// unread=true — только непрочитанные.
+func (c *NotificationController) Notifications(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("p", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	unreadOnly := ctx.Query("unread") == "true"
+	userID, _ := ctx.Keys["userID"].(string)
+	notifications, err := c.interactor.Notifications(ctx, userID, unreadOnly, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get notifications",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+	})
+}
+
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.MarkRead(ctx, userID, ctx.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"error":   "failed to mark notification read",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *NotificationController) MarkAllRead(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.MarkAllRead(ctx, userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to mark notifications read",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type ProjectController struct {
+	interactor domain.ProjectInteractor
+}
+
+func NewProjectController(interactor domain.ProjectInteractor) *ProjectController {
+	return &ProjectController{interactor: interactor}
+}
+
+func (c *ProjectController) CreateProject(ctx *gin.Context) {
+	type CreateProjectRequest struct {
+		Key         string `json:"key" binding:"required"`
+		Name        string `json:"name" binding:"required,max=100"`
+		Description string `json:"description" binding:"max=1000"`
+	}
+	var req CreateProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	project, err := c.interactor.CreateProject(ctx, actorID, &domain.Project{
+		Key:         req.Key,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create project",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{
+		EntityID: project.ID,
+		After: map[string]any{
+			"kind": "project",
+			"key":  project.Key,
+			"name": project.Name,
+		},
+	})
+	ctx.JSON(http.StatusOK, gin.H{
+		"project": project,
+	})
+}
+
+// Projects возвращает проекты текущего пользователя.
+func (c *ProjectController) Projects(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	projects, err := c.interactor.Projects(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get projects",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"projects": projects,
+	})
+}
+
+func (c *ProjectController) Project(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	project, err := c.interactor.Project(ctx, ctx.Param("id"), actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get project",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"project": project,
+	})
+}
+
+// SetMember добавляет участника или меняет его роль: OWNER, MEMBER или VIEWER.
+func (c *ProjectController) SetMember(ctx *gin.Context) {
+	type SetMemberRequest struct {
+		Role domain.ProjectRole `json:"role" binding:"required"`
+	}
+	var req SetMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	member, err := c.interactor.SetMember(ctx, ctx.Param("id"), actorID, ctx.Param("userID"), req.Role)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to set project member",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"member": member,
+	})
+}
+
+func (c *ProjectController) RemoveMember(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.RemoveMember(ctx, ctx.Param("id"), actorID, ctx.Param("userID")); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to remove project member",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// Tasks возвращает задачи проекта с теми же фильтрами, что и общий список.
+func (c *ProjectController) Tasks(ctx *gin.Context) {
+	filter, err := taskFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	page, err := c.interactor.Tasks(ctx, ctx.Param("id"), actorID, filter)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get project tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"tasks": page.Tasks,
+		"total": page.Total,
+		"page":  page.Page,
+		"limit": page.Limit,
+		"pages": page.Pages,
+	})
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type RecurrenceController struct {
+	interactor domain.RecurrenceInteractor
+}
+
+func NewRecurrenceController(interactor domain.RecurrenceInteractor) *RecurrenceController {
+	return &RecurrenceController{interactor: interactor}
+}
+
+// CreateRecurrence создаёт повторяющуюся задачу. Правило задаётся полями
+// frequency/interval/until/count или строкой rrule ("FREQ=WEEKLY;INTERVAL=2;COUNT=10").
+func (c *RecurrenceController) CreateRecurrence(ctx *gin.Context) {
+	type CreateRecurrenceRequest struct {
+		Title     string                     `json:"title" binding:"required,min=3,max=50"`
+		Image     string                     `json:"image"`
+		Content   string                     `json:"content"`
+		UserID    string                     `json:"user_id"`
+		Priority  domain.Priority            `json:"priority" binding:"required"`
+		StartAt   time.Time                  `json:"start_at"`
+		Frequency domain.RecurrenceFrequency `json:"frequency"`
+		Interval  int                        `json:"interval"`
+		Until     *time.Time                 `json:"until"`
+		Count     int                        `json:"count"`
+		Mode      domain.RecurrenceMode      `json:"mode"`
+		RRule     string                     `json:"rrule"`
+		ProjectID string                     `json:"project_id"`
+	}
+	var req CreateRecurrenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	creatorID, _ := ctx.Keys["userID"].(string)
+	recurrence, err := c.interactor.CreateRecurrence(ctx, &domain.Recurrence{
+		Frequency: req.Frequency,
+		Interval:  req.Interval,
+		Until:     req.Until,
+		Count:     req.Count,
+		Mode:      req.Mode,
+		StartAt:   req.StartAt,
+		Title:     req.Title,
+		Content:   req.Content,
+		Image:     req.Image,
+		Priority:  req.Priority,
+		UserID:    req.UserID,
+		CreatorID: creatorID,
+		ProjectID: req.ProjectID,
+	}, req.RRule)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create recurrence",
+			"details": err.Error(),
+		})
+		return
+	}
+	// правило повторения — настройка, а не задача
+	auditEntry(ctx, &domain.AuditEntry{
+		EntityType: domain.AuditSettings,
+		EntityID:   recurrence.ID,
+		After: map[string]any{
+			"kind":      "recurrence",
+			"title":     recurrence.Title,
+			"frequency": string(recurrence.Frequency),
+			"interval":  recurrence.Interval,
+		},
+	})
+	ctx.JSON(http.StatusOK, gin.H{
+		"recurrence": recurrence,
+	})
+}
+
+func (c *RecurrenceController) Recurrence(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	recurrence, err := c.interactor.Recurrence(ctx, ctx.Param("id"), userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get recurrence",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"recurrence": recurrence,
+	})
+}
+
+func (c *RecurrenceController) Recurrences(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	recurrences, err := c.interactor.Recurrences(ctx, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get recurrences",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"recurrences": recurrences,
+	})
+}
+
+func (c *RecurrenceController) StopRecurrence(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.StopRecurrence(ctx, ctx.Param("id"), actorID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to stop recurrence",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{EntityType: domain.AuditSettings})
+	ctx.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type ReportController struct {
+	interactor domain.ReportInteractor
+}
+
+func NewReportController(interactor domain.ReportInteractor) *ReportController {
+	return &ReportController{interactor: interactor}
+}
+
+// Users — отчёт по всем пользователям (для администраторов).
+func (c *ReportController) Users(ctx *gin.Context) {
+	c.workload(ctx, domain.ReportFilter{})
+}
+
+// User — отчёт по одному пользователю; id=me — по себе.
+func (c *ReportController) User(ctx *gin.Context) {
+	userID := ctx.Param("id")
+	if userID == "me" {
+		userID, _ = ctx.Keys["userID"].(string)
+	}
+	c.workload(ctx, domain.ReportFilter{UserID: userID})
+}
+
+// Project — отчёт по команде проекта: итог и строки по участникам.
+func (c *ReportController) Project(ctx *gin.Context) {
+	c.workload(ctx, domain.ReportFilter{ProjectID: ctx.Param("id")})
+}
+
+// workload читает период from/to (RFC3339 или YYYY-MM-DD, to включительно)
+// и строит отчёт; по умолчанию — последние 30 дней.
+func (c *ReportController) workload(ctx *gin.Context, filter domain.ReportFilter) {
+	from, err := parseQueryTime(ctx.Query("from"), false)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid from",
+			"details": err.Error(),
+		})
+		return
+	}
+	to, err := parseQueryTime(ctx.Query("to"), true)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid to",
+			"details": err.Error(),
+		})
+		return
+	}
+	if from != nil {
+		filter.From = *from
+	}
+	if to != nil {
+		filter.To = *to
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	report, err := c.interactor.Workload(ctx, userID, filter)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to build report",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"report": report,
+	})
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type SprintController struct {
+	interactor domain.SprintInteractor
+}
+
+func NewSprintController(interactor domain.SprintInteractor) *SprintController {
+	return &SprintController{interactor: interactor}
+}
+
+type sprintRequest struct {
+	Name    string              `json:"name" binding:"required,max=100"`
+	Goal    string              `json:"goal" binding:"max=1000"`
+	StartAt time.Time           `json:"start_at" binding:"required"`
+	EndAt   time.Time           `json:"end_at" binding:"required"`
+	Unit    domain.EstimateUnit `json:"unit"`
+}
+
+func (c *SprintController) CreateSprint(ctx *gin.Context) {
+	var req sprintRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	sprint, err := c.interactor.CreateSprint(ctx, &domain.Sprint{
+		Name:        req.Name,
+		Goal:        req.Goal,
+		StartAt:     req.StartAt,
+		EndAt:       req.EndAt,
+		Unit:        req.Unit,
+		CreatedByID: userID,
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create sprint",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{
+		EntityID: sprint.ID,
+		After: map[string]any{
+			"kind":     "sprint",
+			"name":     sprint.Name,
+			"start_at": sprint.StartAt,
+			"end_at":   sprint.EndAt,
+		},
+	})
+	ctx.JSON(http.StatusOK, gin.H{
+		"sprint": sprint,
+	})
+}
+
+func (c *SprintController) UpdateSprint(ctx *gin.Context) {
+	var req sprintRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	sprint, err := c.interactor.UpdateSprint(ctx, &domain.Sprint{
+		ID:      ctx.Param("id"),
+		Name:    req.Name,
+		Goal:    req.Goal,
+		StartAt: req.StartAt,
+		EndAt:   req.EndAt,
+		Unit:    req.Unit,
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to update sprint",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"sprint": sprint,
+	})
+}
+
+func (c *SprintController) Sprint(ctx *gin.Context) {
+	sprint, err := c.interactor.Sprint(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get sprint",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"sprint": sprint,
+	})
+}
+
+func (c *SprintController) Sprints(ctx *gin.Context) {
+	sprints, err := c.interactor.Sprints(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get sprints",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"sprints": sprints,
+	})
+}
+
+func (c *SprintController) DeleteSprint(ctx *gin.Context) {
+	if err := c.interactor.DeleteSprint(ctx, ctx.Param("id")); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to delete sprint",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// PlanTask переносит задачу в спринт и задаёт оценку; пустой sprint_id убирает задачу из спринта,
+// отсутствующая estimate сбрасывает оценку.
+func (c *SprintController) PlanTask(ctx *gin.Context) {
+	type PlanTaskRequest struct {
+		SprintID string   `json:"sprint_id"`
+		Estimate *float64 `json:"estimate"`
+	}
+	var req PlanTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	task, err := c.interactor.PlanTask(ctx, ctx.Param("id"), req.SprintID, req.Estimate, actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to plan task",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"task": task,
+	})
+}
+
+// Burndown возвращает ряд по дням: scope/completed для burnup, remaining/ideal для burndown.
+func (c *SprintController) Burndown(ctx *gin.Context) {
+	burn, err := c.interactor.Burndown(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get burndown",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"burndown": burn,
+	})
+}
+
+func (c *SprintController) Velocity(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "5"))
+	velocity, err := c.interactor.Velocity(ctx, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get velocity",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"velocity": velocity,
+	})
+}
@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,12 +13,21 @@ import (
 )
 
 type TaskController struct {
-	interactor  domain.TaskInteractor
-	hInteractor domain.HistoryInteractor
+	interactor domain.TaskInteractor
 }
 
-func NewTaskController(interactor domain.TaskInteractor, hInteractor domain.HistoryInteractor) *TaskController {
-	return &TaskController{interactor: interactor, hInteractor: hInteractor}
+func NewTaskController(interactor domain.TaskInteractor) *TaskController {
+	return &TaskController{interactor: interactor}
+}
+
+// snapshot возвращает поля задачи для журнала аудита или nil, если её не удалось загрузить.
+func (c *TaskController) snapshot(ctx *gin.Context, id string) map[string]any {
+	userID, _ := ctx.Keys["userID"].(string)
+	task, err := c.interactor.Task(ctx, id, userID)
+	if err != nil {
+		return nil
+	}
+	return task.AuditFields()
 }
 
 func (c *TaskController) Task(ctx *gin.Context) {
@@ -24,9 +36,10 @@ func (c *TaskController) Task(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
 		return
 	}
-	Task, err := c.interactor.Task(ctx, idStr)
+	userID, _ := ctx.Keys["userID"].(string)
+	Task, err := c.interactor.Task(ctx, idStr, userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		ctx.JSON(taskErrorStatus(err), gin.H{
 			"error":   "failed to get task",
 			"details": err.Error(),
 		})
@@ -38,12 +51,115 @@ func (c *TaskController) Task(ctx *gin.Context) {
 
 }
 
+// TaskByKey возвращает задачу проекта по номеру вида OPS-42.
+func (c *TaskController) TaskByKey(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	task, err := c.interactor.TaskByKey(ctx, ctx.Param("key"), userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get task",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"Task": task,
+	})
+}
+
+// Tasks возвращает страницу задач с фильтрами:
+// status, priority (через запятую), assignee (id или "me"), planned_from, planned_to, q,
+// сортировкой sort=created_at|planned_at|priority|rank и order=asc|desc.
 func (c *TaskController) Tasks(ctx *gin.Context) {
+	filter, err := taskFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	page, err := c.interactor.Tasks(ctx, userID, filter)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"tasks": page.Tasks,
+		"total": page.Total,
+		"page":  page.Page,
+		"limit": page.Limit,
+		"pages": page.Pages,
+	})
+
+}
+
+// Overdue возвращает незавершённые задачи с прошедшим сроком. Принимает те же
+// фильтры, что и список задач; по умолчанию сначала самые давние.
+func (c *TaskController) Overdue(ctx *gin.Context) {
+	filter, err := taskFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	now := time.Now()
+	filter.OverdueAt = &now
+	if ctx.Query("sort") == "" {
+		filter.SortBy = domain.SortPlannedAt
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	page, err := c.interactor.Tasks(ctx, userID, filter)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get overdue tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"tasks": page.Tasks,
+		"total": page.Total,
+		"page":  page.Page,
+		"limit": page.Limit,
+		"pages": page.Pages,
+	})
+}
+
+// MyTasks возвращает задачи, назначенные текущему пользователю.
+func (c *TaskController) MyTasks(ctx *gin.Context) {
+	pageStr := ctx.DefaultQuery("p", "1")
+	limitStr := ctx.DefaultQuery("limit", "6")
+	page, _ := strconv.Atoi(pageStr)
+	limit, _ := strconv.Atoi(limitStr)
+	userID, _ := ctx.Keys["userID"].(string)
+	tasks, err := c.interactor.MyTasks(ctx, userID, page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"tasks": tasks,
+	})
+}
+
+// AssignedByMe возвращает задачи, которые текущий пользователь назначил другим.
+func (c *TaskController) AssignedByMe(ctx *gin.Context) {
 	pageStr := ctx.DefaultQuery("p", "1")
 	limitStr := ctx.DefaultQuery("limit", "6")
 	page, _ := strconv.Atoi(pageStr)
 	limit, _ := strconv.Atoi(limitStr)
-	tasks, err := c.interactor.Tasks(ctx, page, limit)
+	userID, _ := ctx.Keys["userID"].(string)
+	tasks, err := c.interactor.AssignedByMe(ctx, userID, page, limit)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to get tasks",
@@ -54,7 +170,160 @@ func (c *TaskController) Tasks(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{
 		"tasks": tasks,
 	})
+}
+
+func (c *TaskController) AssignTask(ctx *gin.Context) {
+	type AssignTaskRequest struct {
+		UserID string `json:"user_id" binding:"required"`
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	var req AssignTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	before := c.snapshot(ctx, id)
+	if err := c.interactor.AssignTask(ctx, id, req.UserID, actorID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to assign task",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{Action: domain.AuditAssign, Before: before, After: c.snapshot(ctx, id)})
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *TaskController) Assignments(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	assignments, err := c.interactor.Assignments(ctx, id, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get assignments",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"assignments": assignments,
+	})
+}
 
+func (c *TaskController) TransitionTask(ctx *gin.Context) {
+	type TransitionTaskRequest struct {
+		Status domain.Status `json:"status" binding:"required"`
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	var req TransitionTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	before := c.snapshot(ctx, id)
+	task, err := c.interactor.TransitionTask(ctx, id, req.Status, actorID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to change task status",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{Action: domain.AuditTransition, Before: before, After: task.AuditFields()})
+	ctx.JSON(http.StatusOK, gin.H{
+		"task": task,
+	})
+}
+
+func (c *TaskController) Transitions(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	transitions, err := c.interactor.Transitions(ctx, id, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get transitions",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"transitions": transitions,
+	})
+}
+
+// SetParent вкладывает задачу в parent_id; пустой parent_id выносит её на верхний уровень.
+func (c *TaskController) SetParent(ctx *gin.Context) {
+	type SetParentRequest struct {
+		ParentID string `json:"parent_id"`
+	}
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	var req SetParentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	before := c.snapshot(ctx, id)
+	if err := c.interactor.SetParent(ctx, id, req.ParentID, actorID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to set parent task",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{Before: before, After: c.snapshot(ctx, id)})
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *TaskController) Subtasks(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "missing task ID"})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	subtasks, err := c.interactor.Subtasks(ctx, id, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get subtasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"subtasks": subtasks,
+	})
 }
 
 func (c *TaskController) UpdateTask(ctx *gin.Context) {
@@ -67,6 +336,8 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 		PlannedAt time.Time       `json:"planned_at"`
 		Priority  domain.Priority `json:"priority" binding:"required"`
 		Status    domain.Status   `json:"status"`
+		// Scope: this (по умолчанию) или future — для повторяющихся задач.
+		Scope domain.EditScope `json:"scope"`
 	}
 	var req UpdateTaskRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -76,24 +347,34 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 		})
 		return
 	}
-	err := c.interactor.UpdateTask(ctx, req.ID, req.Title, req.Image, req.Content, req.UserID, req.Priority, req.Status)
+	actorID, _ := ctx.Keys["userID"].(string)
+	before := c.snapshot(ctx, req.ID)
+	err := c.interactor.UpdateTask(ctx, req.ID, req.Title, req.Image, req.Content, req.PlannedAt, req.UserID, actorID, req.Priority, req.Status, req.Scope)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		ctx.JSON(taskErrorStatus(err), gin.H{
 			"error":   "failed to update task",
 			"details": err.Error(),
 		})
 		return
 	}
+	auditEntry(ctx, &domain.AuditEntry{EntityID: req.ID, Action: domain.AuditUpdate, Before: before, After: c.snapshot(ctx, req.ID)})
 	ctx.JSON(http.StatusOK, gin.H{})
 
 }
 
+// CreateTask создаёт задачу; с ?template=id — задачи по шаблону (см. createFromTemplate).
 func (c *TaskController) CreateTask(ctx *gin.Context) {
+	if templateID := ctx.Query("template"); templateID != "" {
+		c.createFromTemplate(ctx, templateID)
+		return
+	}
 	type CreateTaskRequest struct {
 		Title     string          `json:"title" binding:"required,min=3,max=50"`
 		Image     string          `json:"image"`
 		Content   string          `json:"content"`
 		UserID    string          `json:"user_id"`
+		ParentID  string          `json:"parent_id"`
+		ProjectID string          `json:"project_id"`
 		PlannedAt time.Time       `json:"planned_at"`
 		Priority  domain.Priority `json:"priority" binding:"required"`
 		Status    domain.Status   `json:"status"`
@@ -107,14 +388,16 @@ func (c *TaskController) CreateTask(ctx *gin.Context) {
 		return
 	}
 
-	taskID, err := c.interactor.CreateTask(ctx, req.Title, req.Image, req.Content, req.PlannedAt, req.UserID, req.Priority, req.Status)
+	creatorID, _ := ctx.Keys["userID"].(string)
+	taskID, err := c.interactor.CreateTask(ctx, req.Title, req.Image, req.Content, req.PlannedAt, req.UserID, creatorID, req.ParentID, req.ProjectID, req.Priority, req.Status)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		ctx.JSON(taskErrorStatus(err), gin.H{
 			"error":   "failed to create task",
 			"details": err.Error(),
 		})
 		return
 	}
+	auditEntry(ctx, &domain.AuditEntry{EntityID: taskID, After: c.snapshot(ctx, taskID)})
 	ctx.JSON(http.StatusOK, gin.H{
 		"taskID": taskID,
 		"userID": ctx.Keys["userName"],
@@ -122,6 +405,50 @@ func (c *TaskController) CreateTask(ctx *gin.Context) {
 
 }
 
+// createFromTemplate создаёт задачу по шаблону, а для плейбука — и подзадачи шагов.
+// params подставляются в заголовки: {"client": "ТТК"} для шаблона «Подключение {client}».
+func (c *TaskController) createFromTemplate(ctx *gin.Context, templateID string) {
+	type CreateFromTemplateRequest struct {
+		UserID    string            `json:"user_id"`
+		ParentID  string            `json:"parent_id"`
+		ProjectID string            `json:"project_id"`
+		PlannedAt time.Time         `json:"planned_at"`
+		Params    map[string]string `json:"params"`
+	}
+	var req CreateFromTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	creatorID, _ := ctx.Keys["userID"].(string)
+	ids, err := c.interactor.CreateFromTemplate(ctx, &domain.TemplateRequest{
+		TemplateID: templateID,
+		CreatorID:  creatorID,
+		UserID:     req.UserID,
+		ProjectID:  req.ProjectID,
+		ParentID:   req.ParentID,
+		PlannedAt:  req.PlannedAt,
+		Params:     req.Params,
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create task from template",
+			"details": err.Error(),
+		})
+		return
+	}
+	for _, id := range ids {
+		auditEntry(ctx, &domain.AuditEntry{EntityID: id, After: c.snapshot(ctx, id)})
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"taskID":  ids[0],
+		"taskIDs": ids,
+	})
+}
+
 func (c *TaskController) DeleteTask(ctx *gin.Context) {
 	id := ctx.Param("id")
 	if id == "" {
@@ -129,13 +456,243 @@ func (c *TaskController) DeleteTask(ctx *gin.Context) {
 		return
 	}
 
-	err := c.interactor.DeleteTask(ctx, id)
+	before := c.snapshot(ctx, id)
+	userID, _ := ctx.Keys["userID"].(string)
+	err := c.interactor.DeleteTask(ctx, id, userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		ctx.JSON(taskErrorStatus(err), gin.H{
 			"error":   "failed to create task",
 			"details": err.Error(),
 		})
 		return
 	}
+	auditEntry(ctx, &domain.AuditEntry{Before: before})
 	ctx.JSON(http.StatusOK, gin.H{})
 }
+
+// BulkUpdate применяет операцию к задачам из ids или, при filter=true,
+// к задачам, подходящим под фильтр из строки запроса (как в /task/show).
+func (c *TaskController) BulkUpdate(ctx *gin.Context) {
+	type BulkUpdateRequest struct {
+		IDs       []string        `json:"ids"`
+		UseFilter bool            `json:"filter"`
+		Operation string          `json:"operation" binding:"required"`
+		Status    domain.Status   `json:"status"`
+		Priority  domain.Priority `json:"priority"`
+		UserID    string          `json:"user_id"`
+		LabelID   string          `json:"label_id"`
+	}
+	var req BulkUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	bulk := domain.BulkRequest{
+		IDs:       req.IDs,
+		Operation: domain.BulkOperation(strings.ToLower(req.Operation)),
+		Status:    domain.Status(strings.ToUpper(string(req.Status))),
+		Priority:  domain.Priority(strings.ToUpper(string(req.Priority))),
+		UserID:    req.UserID,
+		LabelID:   req.LabelID,
+	}
+	if req.UseFilter {
+		if len(req.IDs) > 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ids and filter are mutually exclusive"})
+			return
+		}
+		filter, err := taskFilterFromQuery(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid filter",
+				"details": err.Error(),
+			})
+			return
+		}
+		bulk.Filter = &filter
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	result, err := c.interactor.BulkUpdate(ctx, actorID, &bulk)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to apply bulk operation",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditBulk(ctx, &bulk, result)
+	ctx.JSON(http.StatusOK, gin.H{
+		"result": result,
+	})
+}
+
+// auditBulk описывает для журнала аудита каждую изменённую задачу массовой операции.
+func auditBulk(ctx *gin.Context, bulk *domain.BulkRequest, result *domain.BulkResult) {
+	action, field, value := domain.AuditUpdate, "", ""
+	switch bulk.Operation {
+	case domain.BulkStatus:
+		action, field, value = domain.AuditTransition, "status", string(bulk.Status)
+	case domain.BulkPriority:
+		field, value = "priority", string(bulk.Priority)
+	case domain.BulkAssign:
+		action, field, value = domain.AuditAssign, "user_id", bulk.UserID
+	case domain.BulkAddLabel:
+		field, value = "label_id", bulk.LabelID
+	case domain.BulkDelete:
+		action = domain.AuditDelete
+	}
+	auditNothing(ctx)
+	for _, item := range result.Items {
+		if !item.Changed {
+			continue
+		}
+		entry := &domain.AuditEntry{EntityID: item.TaskID, Action: action}
+		if field != "" {
+			entry.After = map[string]any{field: value}
+		}
+		auditEntry(ctx, entry)
+	}
+}
+
+func taskFilterFromQuery(ctx *gin.Context) (domain.TaskFilter, error) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("p", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "6"))
+	filter := domain.TaskFilter{
+		AssigneeID: ctx.Query("assignee"),
+		ProjectID:  ctx.Query("project"),
+		Query:      strings.TrimSpace(ctx.Query("q")),
+		SortBy:     domain.TaskSort(ctx.Query("sort")),
+		Page:       page,
+		Limit:      limit,
+	}
+	if filter.AssigneeID == "me" {
+		filter.AssigneeID, _ = ctx.Keys["userID"].(string)
+	}
+	for _, status := range splitQuery(ctx.Query("status")) {
+		filter.Statuses = append(filter.Statuses, domain.Status(strings.ToUpper(status)))
+	}
+	for _, priority := range splitQuery(ctx.Query("priority")) {
+		filter.Priorities = append(filter.Priorities, domain.Priority(strings.ToUpper(priority)))
+	}
+	// label=id1,id2 — хотя бы одна из меток; field[id]=значение — равенство значения поля
+	filter.LabelIDs = splitQuery(ctx.Query("label"))
+	if fields := ctx.QueryMap("field"); len(fields) > 0 {
+		filter.Fields = fields
+	}
+	switch strings.ToLower(ctx.DefaultQuery("order", "asc")) {
+	case "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return filter, errors.New("order must be asc or desc")
+	}
+	var err error
+	if filter.PlannedFrom, err = parseQueryTime(ctx.Query("planned_from"), false); err != nil {
+		return filter, fmt.Errorf("planned_from: %w", err)
+	}
+	if filter.PlannedTo, err = parseQueryTime(ctx.Query("planned_to"), true); err != nil {
+		return filter, fmt.Errorf("planned_to: %w", err)
+	}
+	return filter, nil
+}
+
+func splitQuery(value string) []string {
+	var parts []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// parseQueryTime принимает RFC3339 или дату YYYY-MM-DD. Для верхней границы
+// дата без времени означает конец дня.
+func parseQueryTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New("expected RFC3339 or YYYY-MM-DD")
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// taskErrorStatus подбирает HTTP-статус для ошибок задач.
+func taskErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidPriority),
+		errors.Is(err, domain.ErrInvalidSort),
+		errors.Is(err, domain.ErrInvalidNeighbor),
+		errors.Is(err, domain.ErrParentNotFound),
+		errors.Is(err, domain.ErrChecklistOrder),
+		errors.Is(err, domain.ErrInvalidRecurrence),
+		errors.Is(err, domain.ErrInvalidEditScope),
+		errors.Is(err, domain.ErrNotRecurring),
+		errors.Is(err, domain.ErrInvalidTimeEntry),
+		errors.Is(err, domain.ErrInvalidSprint),
+		errors.Is(err, domain.ErrInvalidEstimate),
+		errors.Is(err, domain.ErrInvalidLabel),
+		errors.Is(err, domain.ErrInvalidField),
+		errors.Is(err, domain.ErrInvalidFieldValue),
+		errors.Is(err, domain.ErrInvalidProject),
+		errors.Is(err, domain.ErrInvalidProjectRole),
+		errors.Is(err, domain.ErrInvalidTaskKey),
+		errors.Is(err, domain.ErrInvalidComment),
+		errors.Is(err, domain.ErrInvalidLinkKind),
+		errors.Is(err, domain.ErrInvalidBulk),
+		errors.Is(err, domain.ErrBulkTooLarge),
+		errors.Is(err, domain.ErrInvalidTemplate),
+		errors.Is(err, domain.ErrInvalidTableFormat),
+		errors.Is(err, domain.ErrInvalidImport),
+		errors.Is(err, domain.ErrInvalidReport),
+		errors.Is(err, domain.ErrInvalidAuditFilter),
+		errors.Is(err, domain.ErrInvalidHistoryFilter),
+		errors.Is(err, domain.ErrUnknownChain):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrTimeEntryForbidden),
+		errors.Is(err, domain.ErrAdminRequired),
+		errors.Is(err, domain.ErrNotProjectMember),
+		errors.Is(err, domain.ErrProjectRole):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrChecklistItemNotFound),
+		errors.Is(err, domain.ErrDependencyNotFound),
+		errors.Is(err, domain.ErrTimeEntryNotFound),
+		errors.Is(err, domain.ErrNoRunningTimer),
+		errors.Is(err, domain.ErrSprintNotFound),
+		errors.Is(err, domain.ErrLabelNotFound),
+		errors.Is(err, domain.ErrFieldNotFound),
+		errors.Is(err, domain.ErrProjectNotFound),
+		errors.Is(err, domain.ErrArticleNotFound),
+		errors.Is(err, domain.ErrArticleLinkNotFound),
+		errors.Is(err, domain.ErrTemplateNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTransitionNotAllowed),
+		errors.Is(err, domain.ErrWIPLimit),
+		errors.Is(err, domain.ErrTaskChanged),
+		errors.Is(err, domain.ErrTaskCycle),
+		errors.Is(err, domain.ErrOpenSubtasks),
+		errors.Is(err, domain.ErrTaskBlocked),
+		errors.Is(err, domain.ErrDependencyCycle),
+		errors.Is(err, domain.ErrDependencyExists),
+		errors.Is(err, domain.ErrTimerRunning),
+		errors.Is(err, domain.ErrLabelExists),
+		errors.Is(err, domain.ErrFieldExists),
+		errors.Is(err, domain.ErrProjectKeyExists),
+		errors.Is(err, domain.ErrLastProjectOwner):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
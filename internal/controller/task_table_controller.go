@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+// maxImportSize — ограничение размера загружаемого файла.
+const maxImportSize = 10 << 20
+
+var tableContentTypes = map[domain.TableFormat]string{
+	domain.FormatCSV:  "text/csv; charset=utf-8",
+	domain.FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type TaskTableController struct {
+	interactor domain.TaskTableInteractor
+}
+
+func NewTaskTableController(interactor domain.TaskTableInteractor) *TaskTableController {
+	return &TaskTableController{interactor: interactor}
+}
+
+// Export выгружает задачи по тем же фильтрам, что и /task/show, в CSV или
+// XLSX (format, по умолчанию csv). Файл пишется в ответ по мере чтения задач.
+func (c *TaskTableController) Export(ctx *gin.Context) {
+	format := domain.TableFormat(strings.ToLower(ctx.DefaultQuery("format", string(domain.FormatCSV))))
+	if !format.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid format",
+			"details": domain.ErrInvalidTableFormat.Error(),
+		})
+		return
+	}
+	filter, err := taskFilterFromQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid filter",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+
+	ctx.Header("Content-Type", tableContentTypes[format])
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	if err := c.interactor.ExportTasks(ctx, userID, filter, format, ctx.Writer); err != nil {
+		// после начала записи ответ уже не заменить, выгрузка просто обрывается
+		if ctx.Writer.Written() {
+			ctx.Error(err)
+			return
+		}
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to export tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+}
+
+// Import загружает задачи из файла file (multipart). Формат — из format или
+// расширения файла, project — проект для строк без столбца project,
+// dry_run=true — только проверка. При ошибках в строках задачи не создаются
+// и ответ 422 содержит ошибки по строкам.
+func (c *TaskTableController) Import(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "file is required",
+			"details": err.Error(),
+		})
+		return
+	}
+	format := domain.TableFormat(strings.ToLower(ctx.Query("format")))
+	if format == "" {
+		format = domain.TableFormat(strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), ".")))
+	}
+	if !format.Valid() {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid format",
+			"details": domain.ErrInvalidTableFormat.Error(),
+		})
+		return
+	}
+	dryRun, _ := strconv.ParseBool(ctx.Query("dry_run"))
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "failed to read file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+	userID, _ := ctx.Keys["userID"].(string)
+
+	result, err := c.interactor.ImportTasks(ctx, userID, file, header.Size, domain.ImportOptions{
+		Format:    format,
+		DryRun:    dryRun,
+		ProjectID: ctx.Query("project"),
+	})
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to import tasks",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditNothing(ctx)
+	for _, id := range result.TaskIDs {
+		auditEntry(ctx, &domain.AuditEntry{EntityID: id, Action: domain.AuditCreate})
+	}
+	status := http.StatusOK
+	if len(result.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	} else if result.Created > 0 {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, gin.H{
+		"import": result,
+	})
+}
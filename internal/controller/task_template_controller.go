@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type TaskTemplateController struct {
+	interactor domain.TaskTemplateInteractor
+}
+
+func NewTaskTemplateController(interactor domain.TaskTemplateInteractor) *TaskTemplateController {
+	return &TaskTemplateController{interactor: interactor}
+}
+
+type templateStepRequest struct {
+	TitlePattern   string          `json:"title_pattern" binding:"required,max=100"`
+	Content        string          `json:"content"`
+	Priority       domain.Priority `json:"priority"`
+	Checklist      []string        `json:"checklist"`
+	DueOffsetHours int             `json:"due_offset_hours"`
+}
+
+func (r templateStepRequest) template() *domain.TaskTemplate {
+	return &domain.TaskTemplate{
+		TitlePattern:   r.TitlePattern,
+		Content:        r.Content,
+		Priority:       r.Priority,
+		Checklist:      r.Checklist,
+		DueOffsetHours: r.DueOffsetHours,
+	}
+}
+
+// CreateTemplate создаёт шаблон; непустой steps делает его плейбуком.
+func (c *TaskTemplateController) CreateTemplate(ctx *gin.Context) {
+	type CreateTemplateRequest struct {
+		templateStepRequest
+		Name      string                `json:"name" binding:"required,max=100"`
+		ProjectID string                `json:"project_id"`
+		Steps     []templateStepRequest `json:"steps" binding:"dive"`
+	}
+	var req CreateTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	template := req.template()
+	template.Name = req.Name
+	template.ProjectID = req.ProjectID
+	for _, step := range req.Steps {
+		template.Steps = append(template.Steps, step.template())
+	}
+	actorID, _ := ctx.Keys["userID"].(string)
+	created, err := c.interactor.CreateTemplate(ctx, actorID, template)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to create template",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{
+		EntityID: created.ID,
+		After: map[string]any{
+			"kind":       "template",
+			"name":       created.Name,
+			"project_id": created.ProjectID,
+		},
+	})
+	ctx.JSON(http.StatusOK, gin.H{
+		"template": created,
+	})
+}
+
+func (c *TaskTemplateController) Template(ctx *gin.Context) {
+	template, err := c.interactor.Template(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get template",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"template": template,
+	})
+}
+
+// Templates возвращает общие шаблоны, а с ?project=id — и шаблоны проекта.
+func (c *TaskTemplateController) Templates(ctx *gin.Context) {
+	templates, err := c.interactor.Templates(ctx, ctx.Query("project"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get templates",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+	})
+}
+
+func (c *TaskTemplateController) DeleteTemplate(ctx *gin.Context) {
+	actorID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.DeleteTemplate(ctx, actorID, ctx.Param("id")); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to delete template",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
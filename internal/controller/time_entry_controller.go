@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type TimeEntryController struct {
+	interactor domain.TimeEntryInteractor
+}
+
+func NewTimeEntryController(interactor domain.TimeEntryInteractor) *TimeEntryController {
+	return &TimeEntryController{interactor: interactor}
+}
+
+func (c *TimeEntryController) StartTimer(ctx *gin.Context) {
+	type StartTimerRequest struct {
+		Note string `json:"note" binding:"max=500"`
+	}
+	var req StartTimerRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid request body",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	entry, err := c.interactor.StartTimer(ctx, ctx.Param("id"), userID, req.Note)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to start timer",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{After: entry.AuditFields()})
+	ctx.JSON(http.StatusOK, gin.H{
+		"entry": entry,
+	})
+}
+
+func (c *TimeEntryController) StopTimer(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	entry, err := c.interactor.StopTimer(ctx, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to stop timer",
+			"details": err.Error(),
+		})
+		return
+	}
+	// таймер — часть учёта времени задачи, поэтому в журнале это изменение задачи
+	auditEntry(ctx, &domain.AuditEntry{EntityID: entry.TaskID, Action: domain.AuditUpdate, After: entry.AuditFields()})
+	ctx.JSON(http.StatusOK, gin.H{
+		"entry": entry,
+	})
+}
+
+func (c *TimeEntryController) RunningTimer(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	entry, err := c.interactor.RunningTimer(ctx, userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get running timer",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"entry": entry,
+	})
+}
+
+// AddEntry добавляет ручную запись: начало и длительность в секундах.
+func (c *TimeEntryController) AddEntry(ctx *gin.Context) {
+	type AddEntryRequest struct {
+		StartedAt time.Time `json:"started_at" binding:"required"`
+		Seconds   int       `json:"seconds" binding:"required,min=1"`
+		Note      string    `json:"note" binding:"max=500"`
+	}
+	var req AddEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	entry, err := c.interactor.AddEntry(ctx, ctx.Param("id"), userID, req.StartedAt, req.Seconds, req.Note)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to add time entry",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{After: entry.AuditFields()})
+	ctx.JSON(http.StatusOK, gin.H{
+		"entry": entry,
+	})
+}
+
+func (c *TimeEntryController) DeleteEntry(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	entry, err := c.interactor.DeleteEntry(ctx, userID, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to delete time entry",
+			"details": err.Error(),
+		})
+		return
+	}
+	auditEntry(ctx, &domain.AuditEntry{EntityID: entry.TaskID, Action: domain.AuditUpdate, Before: entry.AuditFields()})
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *TimeEntryController) TaskEntries(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	entries, total, err := c.interactor.TaskEntries(ctx, ctx.Param("id"), userID)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get time entries",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"total":   total,
+	})
+}
+
+// Timesheet возвращает табель за неделю, содержащую дату week (YYYY-MM-DD, по умолчанию текущая);
+// format=csv — выгрузка файлом.
+func (c *TimeEntryController) Timesheet(ctx *gin.Context) {
+	day := time.Now()
+	if week := ctx.Query("week"); week != "" {
+		parsed, err := time.Parse(time.DateOnly, week)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error":   "invalid week",
+				"details": err.Error(),
+			})
+			return
+		}
+		// полдень, чтобы дата не сдвинулась при переводе в часовой пояс табеля
+		day = parsed.Add(12 * time.Hour)
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+
+	if ctx.Query("format") == "csv" {
+		data, err := c.interactor.TimesheetCSV(ctx, userID, day)
+		if err != nil {
+			ctx.JSON(taskErrorStatus(err), gin.H{
+				"error":   "failed to export timesheet",
+				"details": err.Error(),
+			})
+			return
+		}
+		ctx.Header("Content-Disposition", `attachment; filename="timesheet.csv"`)
+		ctx.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+		return
+	}
+
+	sheet, err := c.interactor.Timesheet(ctx, userID, day)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get timesheet",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"timesheet": sheet,
+	})
+}
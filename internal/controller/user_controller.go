@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -65,13 +66,20 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 	}
 
 	// Если все проверки пройдены
-	if err := c.interactor.CreateUser(ctx, req.Login, req.Name, req.Pass); err != nil {
+	id, err := c.interactor.CreateUser(ctx, req.Login, req.Name, req.Pass)
+	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "failed to create user",
 			"details": err.Error(),
 		})
 		return
 	}
+	// при регистрации автор — сам новый пользователь
+	auditEntry(ctx, &domain.AuditEntry{
+		ActorID:  id,
+		EntityID: id,
+		After:    map[string]any{"login": req.Login, "name": req.Name},
+	})
 
 	ctx.JSON(http.StatusOK, gin.H{})
 }
@@ -197,3 +205,45 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{})
 
 }
+
+// SetReminders задаёт текущему пользователю, за сколько часов до срока задачи напоминать.
+func (c *UserController) SetReminders(ctx *gin.Context) {
+	type SetRemindersRequest struct {
+		Offsets []int `json:"offsets"`
+	}
+	var req SetRemindersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	userID, _ := ctx.Keys["userID"].(string)
+	before, err := c.interactor.User(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "failed to get user",
+			"details": err.Error(),
+		})
+		return
+	}
+	if err := c.interactor.SetReminderOffsets(ctx, userID, req.Offsets); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrInvalidReminderOffsets) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"error":   "failed to set reminders",
+			"details": err.Error(),
+		})
+		return
+	}
+	entry := &domain.AuditEntry{EntityID: userID, Before: before.AuditFields()}
+	// после нормализации смещения могут отличаться от запроса
+	if after, err := c.interactor.User(ctx, userID); err == nil {
+		entry.After = after.AuditFields()
+	}
+	auditEntry(ctx, entry)
+	ctx.JSON(http.StatusOK, gin.H{})
+}
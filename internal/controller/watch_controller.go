@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type WatchController struct {
+	interactor domain.WatchInteractor
+}
+
+func NewWatchController(interactor domain.WatchInteractor) *WatchController {
+	return &WatchController{interactor: interactor}
+}
+
+// Watch подписывает текущего пользователя на задачу.
+func (c *WatchController) Watch(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.Watch(ctx, ctx.Param("id"), userID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to watch task",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *WatchController) Unwatch(ctx *gin.Context) {
+	userID, _ := ctx.Keys["userID"].(string)
+	if err := c.interactor.Unwatch(ctx, ctx.Param("id"), userID); err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to unwatch task",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+func (c *WatchController) Watchers(ctx *gin.Context) {
+	watchers, err := c.interactor.Watchers(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get watchers",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"watchers": watchers,
+	})
+}
+
+func (c *WatchController) AddComment(ctx *gin.Context) {
+	type AddCommentRequest struct {
+		Body string `json:"body" binding:"required"`
+	}
+	var req AddCommentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+	authorID, _ := ctx.Keys["userID"].(string)
+	comment, err := c.interactor.AddComment(ctx, ctx.Param("id"), authorID, req.Body)
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to add comment",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"comment": comment,
+	})
+}
+
+// Comments возвращает комментарии задачи, старые первыми.
+func (c *WatchController) Comments(ctx *gin.Context) {
+	comments, err := c.interactor.Comments(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(taskErrorStatus(err), gin.H{
+			"error":   "failed to get comments",
+			"details": err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+	})
+}
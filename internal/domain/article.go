@@ -2,9 +2,15 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var (
+	ErrInvalidLanguage   = errors.New("invalid language")
+	ErrTranslationExists = errors.New("translation for this language already exists")
+)
+
 type Article struct {
 	ID         string
 	Title      string
@@ -14,12 +20,22 @@ type Article struct {
 	Content    string
 	LastEditor string
 	Creator    string
+	Language   string
+	// SourceID ссылается на исходную статью, если это перевод.
+	SourceID string
+	Revision int
+	// SourceRevision — ревизия исходной статьи, с которой синхронизирован перевод.
+	SourceRevision int
+	Outdated       bool
 }
 
 type ArticleInteractor interface {
-	CreateArticle(ctx context.Context, title string, image string, content string, creatorName string) (*Article, error)
+	CreateArticle(ctx context.Context, title string, image string, content string, creatorName string, language string) (*Article, error)
 	Article(ctx context.Context, id string) (*Article, error)
+	ArticleVariant(ctx context.Context, id string, languages []string) (*Article, error)
 	Articles(ctx context.Context, page, limit int) ([]*Article, error)
+	Translations(ctx context.Context, id string) ([]*Article, error)
+	CreateTranslation(ctx context.Context, sourceID string, language string, title string, image string, content string, creatorName string) (*Article, error)
 	UpdateArticle(ctx context.Context, id string, title string, image string, content string, editorName string) (*Article, error)
 	DeteleArticle(ctx context.Context, id string, userID string) error
 }
@@ -28,6 +44,7 @@ type ArticleRepository interface {
 	CreateArticle(ctx context.Context, article *Article) (string, error)
 	Article(ctx context.Context, id string) (*Article, error)
 	Articles(ctx context.Context, page, limit int) ([]*Article, error)
+	Translations(ctx context.Context, sourceID string) ([]*Article, error)
 	UpdateArticle(ctx context.Context, article *Article) (*Article, error)
 	DeleteArticle(ctx context.Context, id string) error
 }
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrArticleNotFound     = errors.New("article not found")
+	ErrInvalidLinkKind     = errors.New("invalid article link kind")
+	ErrArticleLinkNotFound = errors.New("article link not found")
+)
+
+// ArticleLinkKind — чем статья является для задачи.
+type ArticleLinkKind string
+
+const (
+	LinkInstruction ArticleLinkKind = "INSTRUCTION"
+	LinkResult      ArticleLinkKind = "RESULT"
+	LinkReference   ArticleLinkKind = "REFERENCE"
+)
+
+// ParseArticleLinkKind принимает тип связи в любом регистре.
+func ParseArticleLinkKind(kind string) (ArticleLinkKind, error) {
+	k := ArticleLinkKind(strings.ToUpper(strings.TrimSpace(kind)))
+	if k != LinkInstruction && k != LinkResult && k != LinkReference {
+		return "", ErrInvalidLinkKind
+	}
+	return k, nil
+}
+
+// ArticleLinkWarning — изменение статьи после того, как её связали с задачей.
+type ArticleLinkWarning string
+
+const (
+	WarningArticleUpdated ArticleLinkWarning = "ARTICLE_UPDATED"
+	WarningArticleDeleted ArticleLinkWarning = "ARTICLE_DELETED"
+)
+
+// TaskArticleLink — связь задачи со статьёй базы знаний. Статья может быть
+// удалена, поэтому связь хранит её заголовок и ревизию на момент связывания.
+type TaskArticleLink struct {
+	TaskID       string
+	TaskTitle    string
+	ArticleID    string
+	ArticleTitle string
+	Kind         ArticleLinkKind
+	// LinkedRevision — ревизия статьи, с которой задача сверена.
+	LinkedRevision int
+	// ArticleRevision — текущая ревизия статьи, 0 — статья удалена.
+	ArticleRevision int
+	CreatedByID     string
+	CreatedAt       time.Time
+	Warning         ArticleLinkWarning
+}
+
+// Check отмечает связь предупреждением, если статья изменена или удалена после связывания.
+func (l *TaskArticleLink) Check() {
+	switch {
+	case l.ArticleRevision == 0:
+		l.Warning = WarningArticleDeleted
+	case l.ArticleRevision > l.LinkedRevision:
+		l.Warning = WarningArticleUpdated
+	default:
+		l.Warning = ""
+	}
+}
+
+type ArticleLinkInteractor interface {
+	// LinkArticle связывает задачу со статьёй или меняет тип связи. Повторное
+	// связывание сверяет задачу с текущей ревизией статьи и снимает предупреждение.
+	LinkArticle(ctx context.Context, taskID string, articleID string, kind ArticleLinkKind, actorID string) (*TaskArticleLink, error)
+	UnlinkArticle(ctx context.Context, taskID string, articleID string, actorID string) error
+	TaskArticles(ctx context.Context, taskID string) ([]*TaskArticleLink, error)
+	ArticleTasks(ctx context.Context, articleID string) ([]*TaskArticleLink, error)
+}
+
+type ArticleLinkRepository interface {
+	Task(ctx context.Context, id string) (*Task, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	ArticlesByIDs(ctx context.Context, ids []string) ([]*Article, error)
+	SetArticleLink(ctx context.Context, link *TaskArticleLink) error
+	// DeleteArticleLink возвращает false, если связи нет.
+	DeleteArticleLink(ctx context.Context, taskID string, articleID string) (bool, error)
+	// TaskArticleLinks и ArticleTaskLinks заполняют текущую ревизию статьи, но не Warning.
+	TaskArticleLinks(ctx context.Context, taskID string) ([]*TaskArticleLink, error)
+	ArticleTaskLinks(ctx context.Context, articleID string) ([]*TaskArticleLink, error)
+}
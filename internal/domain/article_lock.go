@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrArticleLocked = errors.New("article is locked by another user")
+	ErrLockNotHeld   = errors.New("article lock is not held by this user")
+	ErrAdminRequired = errors.New("admin rights required")
+)
+
+// ArticleLock — эксклюзивная аренда статьи на редактирование,
+// продлевается heartbeat-запросами и истекает в ExpiresAt.
+type ArticleLock struct {
+	ArticleID  string
+	UserID     string
+	UserName   string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+	Lease      time.Duration
+}
+
+type ArticleLockInteractor interface {
+	Acquire(ctx context.Context, articleID string, userID string, userName string, lease time.Duration) (*ArticleLock, error)
+	Heartbeat(ctx context.Context, articleID string, userID string) (*ArticleLock, error)
+	Release(ctx context.Context, articleID string, userID string) error
+	Break(ctx context.Context, articleID string, userID string) error
+	Lock(ctx context.Context, articleID string) (*ArticleLock, error)
+	CheckWrite(ctx context.Context, articleID string, userID string) error
+	Run(ctx context.Context)
+}
+
+type ArticleLockRepository interface {
+	AcquireLock(ctx context.Context, lock *ArticleLock) (bool, error)
+	ExtendLock(ctx context.Context, articleID string, userID string, expiresAt time.Time) (bool, error)
+	ArticleLock(ctx context.Context, articleID string) (*ArticleLock, error)
+	DeleteLock(ctx context.Context, articleID string) error
+	DeleteUserLock(ctx context.Context, articleID string, userID string) (bool, error)
+	DeleteExpiredLocks(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+// AuditEntity — тип сущности в журнале аудита. SETTINGS — настройки:
+// метки, поля, шаблоны, проекты, спринты и личные настройки пользователя.
+type AuditEntity string
+
+const (
+	AuditUser     AuditEntity = "USER"
+	AuditTask     AuditEntity = "TASK"
+	AuditArticle  AuditEntity = "ARTICLE"
+	AuditSettings AuditEntity = "SETTINGS"
+)
+
+func (e AuditEntity) Valid() bool {
+	switch e {
+	case AuditUser, AuditTask, AuditArticle, AuditSettings:
+		return true
+	}
+	return false
+}
+
+type AuditAction string
+
+const (
+	AuditCreate     AuditAction = "CREATE"
+	AuditUpdate     AuditAction = "UPDATE"
+	AuditDelete     AuditAction = "DELETE"
+	AuditAssign     AuditAction = "ASSIGN"
+	AuditTransition AuditAction = "TRANSITION"
+)
+
+func (a AuditAction) Valid() bool {
+	switch a {
+	case AuditCreate, AuditUpdate, AuditDelete, AuditAssign, AuditTransition:
+		return true
+	}
+	return false
+}
+
+// AuditEntry — запись журнала аудита. Before и After содержат только
+// изменившиеся поля (см. AuditDiff); Route — метод и шаблон пути запроса.
+// Записи связаны цепочкой хешей (см. Chain).
+type AuditEntry struct {
+	ID         string
+	ActorID    string
+	EntityType AuditEntity
+	EntityID   string
+	Action     AuditAction
+	Before     map[string]any
+	After      map[string]any
+	Route      string
+	RequestID  string
+	IP         string
+	UserAgent  string
+	CreatedAt  time.Time
+	ChainLink
+}
+
+// AuditDiff оставляет в снимках до и после только различающиеся поля.
+// Снимок nil — сущности не было (создание) или не стало (удаление).
+func AuditDiff(before, after map[string]any) (map[string]any, map[string]any) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	changedBefore := make(map[string]any)
+	changedAfter := make(map[string]any)
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			changedBefore[key] = before[key]
+			changedAfter[key] = value
+		}
+	}
+	for key, old := range before {
+		if _, ok := after[key]; !ok {
+			changedBefore[key] = old
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+// AuditFields — снимок полей задачи для журнала аудита.
+func (t *Task) AuditFields() map[string]any {
+	return map[string]any{
+		"title":      t.Title,
+		"content":    t.Content,
+		"image":      t.Image,
+		"status":     string(t.Status),
+		"priority":   string(t.Priority),
+		"user_id":    t.UserID,
+		"planned_at": t.PlannedAt.UTC().Format(time.RFC3339),
+		"parent_id":  t.ParentID,
+		"project_id": t.ProjectID,
+		"sprint_id":  t.SprintID,
+	}
+}
+
+// AuditFields — снимок записи учёта времени для журнала аудита задачи.
+func (e *TimeEntry) AuditFields() map[string]any {
+	fields := map[string]any{
+		"time_entry_id": e.ID,
+		"started_at":    e.StartedAt.UTC().Format(time.RFC3339),
+		"seconds":       e.Seconds,
+		"note":          e.Note,
+	}
+	if e.EndedAt != nil {
+		fields["ended_at"] = e.EndedAt.UTC().Format(time.RFC3339)
+	}
+	return fields
+}
+
+// AuditFields — снимок полей статьи для журнала аудита.
+func (a *Article) AuditFields() map[string]any {
+	return map[string]any{
+		"title":    a.Title,
+		"content":  a.Content,
+		"image":    a.Image,
+		"language": a.Language,
+		"revision": a.Revision,
+	}
+}
+
+// AuditFields — снимок полей пользователя без хеша пароля.
+func (u *User) AuditFields() map[string]any {
+	return map[string]any{
+		"login":            u.Login,
+		"name":             u.Name,
+		"role":             string(u.IsAdmin),
+		"reminder_offsets": u.ReminderOffsets,
+	}
+}
+
+// AuditFilter — выборка журнала; пустые поля не ограничивают её, период [From, To).
+type AuditFilter struct {
+	EntityType AuditEntity
+	EntityID   string
+	ActorID    string
+	Action     AuditAction
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Limit      int
+}
+
+func (f *AuditFilter) Normalize() error {
+	if f.EntityType != "" && !f.EntityType.Valid() {
+		return fmt.Errorf("%w: unknown entity type", ErrInvalidAuditFilter)
+	}
+	if f.Action != "" && !f.Action.Valid() {
+		return fmt.Errorf("%w: unknown action", ErrInvalidAuditFilter)
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidAuditFilter)
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageLimit
+	}
+	f.Limit = min(f.Limit, MaxPageLimit)
+	return nil
+}
+
+type AuditInteractor interface {
+	// Record сохраняет запись. Ошибка записи только логируется: изменение уже выполнено.
+	Record(ctx context.Context, entry *AuditEntry)
+	// Entries возвращает записи по фильтру, новые первыми; доступно администраторам.
+	Entries(ctx context.Context, actorID string, filter AuditFilter) ([]*AuditEntry, error)
+}
+
+type AuditRepository interface {
+	CreateAuditEntry(ctx context.Context, entry *AuditEntry) error
+	AuditEntries(ctx context.Context, filter AuditFilter) ([]*AuditEntry, error)
+	User(ctx context.Context, id string) (*User, error)
+}
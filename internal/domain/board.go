@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrWIPLimit        = errors.New("column WIP limit reached")
+	ErrTaskChanged     = errors.New("task was changed concurrently")
+	ErrInvalidNeighbor = errors.New("neighbor task is not in the target column")
+)
+
+// BoardStatuses — порядок колонок на доске.
+var BoardStatuses = []Status{Pending, Current, Completed}
+
+const SortRank TaskSort = "rank"
+
+type BoardColumn struct {
+	Status Status
+	Count  int
+	// WIPLimit действует на колонку доски каждого проекта отдельно.
+	WIPLimit int
+	Tasks    []*Task
+}
+
+// TaskMove — перенос задачи в колонку To на позицию Rank.
+// WIPLimit > 0 ограничивает количество задач в колонке доски проекта ProjectID
+// (пустой — доски задач без проекта).
+type TaskMove struct {
+	TaskID    string
+	ProjectID string
+	From      Status
+	To        Status
+	Rank      string
+	UserID    string
+	WIPLimit  int
+}
+
+type BoardInteractor interface {
+	Board(ctx context.Context, actorID string, filter TaskFilter) ([]*BoardColumn, error)
+	MoveTask(ctx context.Context, id string, status Status, afterID string, beforeID string, actorID string) (*Task, error)
+}
+
+type BoardRepository interface {
+	Task(ctx context.Context, id string) (*Task, error)
+	Tasks(ctx context.Context, filter TaskFilter) ([]*Task, int, error)
+	MoveTask(ctx context.Context, move *TaskMove) (bool, error)
+	// ColumnTaskIDs возвращает задачи колонки status доски проекта projectID.
+	ColumnTaskIDs(ctx context.Context, projectID string, status Status) ([]string, error)
+	RankTasks(ctx context.Context, ranks map[string]string) error
+	OpenSubtasks(ctx context.Context, id string) (int, error)
+	OpenBlockers(ctx context.Context, id string) (int, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
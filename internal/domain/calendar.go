@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrFeedNotFound        = errors.New("calendar feed not found")
+	ErrInvalidCalendarKind = errors.New("calendar kind must be todo or event")
+)
+
+// CalendarKind — в каком виде задачи попадают в календарь.
+type CalendarKind string
+
+const (
+	CalendarTodo  CalendarKind = "todo"
+	CalendarEvent CalendarKind = "event"
+)
+
+type CalendarInteractor interface {
+	// Feed строит .ics с задачами владельца токена.
+	Feed(ctx context.Context, token string, kind CalendarKind) ([]byte, error)
+	// RotateToken выдаёт новый токен ленты; старая ссылка перестаёт работать.
+	RotateToken(ctx context.Context, userID string) (string, error)
+}
+
+type CalendarRepository interface {
+	UserByFeedToken(ctx context.Context, tokenHash string) (*User, error)
+	SetFeedToken(ctx context.Context, userID string, tokenHash string) error
+	// CalendarTasks — задачи пользователя: все незавершённые и завершённые после since.
+	CalendarTasks(ctx context.Context, userID string, since time.Time) ([]*Task, error)
+}
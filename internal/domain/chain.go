@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrUnknownChain = errors.New("unknown chain")
+
+// Chain — журнал, записи которого связаны цепочкой хешей: каждая запись
+// хранит хеш предыдущей и хеш своего содержимого вместе с ним, поэтому
+// правка, удаление или вставка записи ломают все последующие звенья.
+type Chain string
+
+const (
+	ChainHistory Chain = "history"
+	ChainAudit   Chain = "audit"
+)
+
+var Chains = []Chain{ChainHistory, ChainAudit}
+
+func (c Chain) Valid() bool {
+	switch c {
+	case ChainHistory, ChainAudit:
+		return true
+	}
+	return false
+}
+
+// ChainLink — положение записи в цепочке. Seq идёт подряд с 1, у первой
+// записи PrevHash пустой.
+type ChainLink struct {
+	Seq      int
+	PrevHash string
+	Hash     string
+}
+
+// ChainHash — sha256 от хеша предыдущей записи и содержимого текущей, в hex.
+func ChainHash(prevHash, content string) string {
+	sum := sha256.Sum256([]byte(prevHash + "\n" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainTime приводит время записи к точности, с которой его хранит база
+// (миллисекунды), чтобы хеш совпал при проверке.
+func ChainTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+// chainContent сериализует поля записи в фиксированном порядке.
+// Ключи снимков json.Marshal сортирует сам.
+func chainContent(fields ...any) string {
+	content, _ := json.Marshal(fields)
+	return string(content)
+}
+
+// ChainContent — содержимое события истории, которое покрывает хеш.
+func (h *History) ChainContent() string {
+	return chainContent(
+		h.Seq,
+		h.ArticleId,
+		h.UserId,
+		string(h.EventType),
+		h.ArticleTitle,
+		ChainTime(h.ChangedAt).Format(time.RFC3339Nano),
+	)
+}
+
+// ChainContent — содержимое записи аудита, которое покрывает хеш.
+func (e *AuditEntry) ChainContent() string {
+	return chainContent(
+		e.Seq,
+		e.ActorID,
+		string(e.EntityType),
+		e.EntityID,
+		string(e.Action),
+		e.Before,
+		e.After,
+		e.Route,
+		e.RequestID,
+		e.IP,
+		e.UserAgent,
+		ChainTime(e.CreatedAt).Format(time.RFC3339Nano),
+	)
+}
+
+// ChainRecord — запись цепочки для проверки: звено и содержимое, посчитанное
+// заново по сохранённым полям.
+type ChainRecord struct {
+	ID string
+	ChainLink
+	Content string
+}
+
+// ChainBreak — первое звено, которое не сходится.
+type ChainBreak struct {
+	Seq      int
+	RecordID string
+	Reason   string
+}
+
+// ChainReport — итог проверки цепочки. Head — последнее проверенное звено:
+// его хеш можно сохранить вне базы, чтобы заметить и удаление записей с конца.
+// Unchained — id записей без номера, появившихся уже после начала цепочки:
+// такие записи добавлены в обход неё.
+type ChainReport struct {
+	Chain     Chain
+	Records   int
+	Head      ChainLink
+	Valid     bool
+	Break     *ChainBreak
+	Unchained []string
+}
+
+type ChainInteractor interface {
+	// Verify проходит цепочку от первой записи; доступно администраторам.
+	Verify(ctx context.Context, actorID string, chain Chain) (*ChainReport, error)
+}
+
+type ChainRepository interface {
+	// ChainRecords возвращает до limit записей цепочки с Seq больше afterSeq по порядку.
+	ChainRecords(ctx context.Context, chain Chain, afterSeq int, limit int) ([]*ChainRecord, error)
+	// UnchainedRecords возвращает id до limit записей без номера, созданных
+	// не раньше первой записи цепочки.
+	UnchainedRecords(ctx context.Context, chain Chain, limit int) ([]string, error)
+	User(ctx context.Context, id string) (*User, error)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrChecklistOrder        = errors.New("order must list every checklist item exactly once")
+)
+
+type ChecklistItem struct {
+	ID        string
+	TaskID    string
+	Text      string
+	Done      bool
+	Position  int
+	CreatedAt time.Time
+}
+
+type ChecklistInteractor interface {
+	Checklist(ctx context.Context, taskID string, userID string) ([]*ChecklistItem, error)
+	AddItem(ctx context.Context, taskID string, text string, actorID string) (*ChecklistItem, error)
+	UpdateItem(ctx context.Context, taskID string, id string, text *string, done *bool, actorID string) (*ChecklistItem, error)
+	ReorderItems(ctx context.Context, taskID string, ids []string, actorID string) ([]*ChecklistItem, error)
+	DeleteItem(ctx context.Context, taskID string, id string, actorID string) error
+}
+
+type ChecklistRepository interface {
+	ChecklistItems(ctx context.Context, taskID string) ([]*ChecklistItem, error)
+	CreateChecklistItem(ctx context.Context, item *ChecklistItem) (*ChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, item *ChecklistItem) error
+	PositionChecklistItems(ctx context.Context, positions map[string]int) error
+	DeleteChecklistItem(ctx context.Context, id string) error
+}
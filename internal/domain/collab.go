@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/lib/ot"
+)
+
+var ErrStaleRevision = errors.New("operation revision is ahead of the document")
+
+const (
+	CollabInit      = "init"
+	CollabAck       = "ack"
+	CollabOperation = "operation"
+	CollabCursor    = "cursor"
+	CollabJoin      = "join"
+	CollabLeave     = "leave"
+	CollabError     = "error"
+)
+
+// CollabMessage — сообщение протокола совместного редактирования (в обе стороны).
+type CollabMessage struct {
+	Type      string          `json:"type"`
+	Revision  int             `json:"revision"`
+	Document  string          `json:"document,omitempty"`
+	ClientID  string          `json:"client_id,omitempty"`
+	UserID    string          `json:"user_id,omitempty"`
+	UserName  string          `json:"user_name,omitempty"`
+	Operation *ot.Operation   `json:"operation,omitempty"`
+	Cursor    *CursorPosition `json:"cursor,omitempty"`
+	Peers     []CollabPeer    `json:"peers,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+type CursorPosition struct {
+	Position     int `json:"position"`
+	SelectionEnd int `json:"selection_end"`
+}
+
+type CollabPeer struct {
+	ClientID string          `json:"client_id"`
+	UserID   string          `json:"user_id"`
+	UserName string          `json:"user_name"`
+	Cursor   *CursorPosition `json:"cursor,omitempty"`
+}
+
+// CollabClient — подключённый редактор. Реализуется WebSocket-соединением
+// или любым in-process клиентом (например, в тестах).
+type CollabClient interface {
+	ID() string
+	UserID() string
+	UserName() string
+	Send(msg CollabMessage) error
+}
+
+type CollabInteractor interface {
+	Join(ctx context.Context, articleID string, client CollabClient) error
+	Leave(ctx context.Context, articleID string, clientID string)
+	Submit(ctx context.Context, articleID string, clientID string, revision int, op ot.Operation) error
+	UpdateCursor(ctx context.Context, articleID string, clientID string, cursor CursorPosition) error
+	Run(ctx context.Context)
+}
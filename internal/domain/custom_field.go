@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrFieldNotFound     = errors.New("custom field not found")
+	ErrInvalidField      = errors.New("invalid custom field")
+	ErrFieldExists       = errors.New("custom field with this name already exists")
+	ErrInvalidFieldValue = errors.New("invalid custom field value")
+)
+
+type FieldType string
+
+const (
+	FieldText   FieldType = "TEXT"
+	FieldNumber FieldType = "NUMBER"
+	FieldDate   FieldType = "DATE"
+	FieldEnum   FieldType = "ENUM"
+	FieldUser   FieldType = "USER"
+)
+
+const maxFieldText = 1000
+
+// CustomField — поле задачи, заданное администратором или владельцем проекта.
+// Поле без ProjectID общее, поле проекта заполняется только у его задач.
+// Options — допустимые значения ENUM.
+type CustomField struct {
+	ID        string
+	Name      string
+	Type      FieldType
+	Options   []string
+	ProjectID string
+	CreatedAt time.Time
+}
+
+func (f *CustomField) Validate() error {
+	f.Name = strings.TrimSpace(f.Name)
+	if f.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidField)
+	}
+	switch f.Type {
+	case FieldText, FieldNumber, FieldDate, FieldUser:
+		if len(f.Options) > 0 {
+			return fmt.Errorf("%w: options are allowed only for ENUM", ErrInvalidField)
+		}
+	case FieldEnum:
+		options := make([]string, 0, len(f.Options))
+		for _, option := range f.Options {
+			if option = strings.TrimSpace(option); option != "" && !slices.Contains(options, option) {
+				options = append(options, option)
+			}
+		}
+		if len(options) == 0 {
+			return fmt.Errorf("%w: ENUM needs at least one option", ErrInvalidField)
+		}
+		f.Options = options
+	default:
+		return fmt.Errorf("%w: type %q", ErrInvalidField, f.Type)
+	}
+	return nil
+}
+
+// Normalize проверяет значение по типу поля и возвращает его каноническую
+// строковую форму, в которой оно хранится и сравнивается в фильтрах:
+// число — без лишних нулей, дата — YYYY-MM-DD. Существование пользователя
+// для USER проверяет вызывающий.
+func (f *CustomField) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrInvalidFieldValue, f.Name)
+	}
+	switch f.Type {
+	case FieldText:
+		if len([]rune(value)) > maxFieldText {
+			return "", fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidFieldValue, f.Name, maxFieldText)
+		}
+	case FieldNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a number", ErrInvalidFieldValue, f.Name)
+		}
+		value = strconv.FormatFloat(number, 'f', -1, 64)
+	case FieldDate:
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a date YYYY-MM-DD", ErrInvalidFieldValue, f.Name)
+		}
+		value = date.Format(time.DateOnly)
+	case FieldEnum:
+		if !slices.Contains(f.Options, value) {
+			return "", fmt.Errorf("%w: %s must be one of %s", ErrInvalidFieldValue, f.Name, strings.Join(f.Options, ", "))
+		}
+	}
+	return value, nil
+}
+
+// FieldValue — значение пользовательского поля у задачи.
+type FieldValue struct {
+	FieldID string
+	Name    string
+	Type    FieldType
+	Value   string
+}
+
+type CustomFieldInteractor interface {
+	// CreateField и DeleteField доступны администраторам, для полей проекта — и его владельцам.
+	CreateField(ctx context.Context, actorID string, field *CustomField) (*CustomField, error)
+	// Fields возвращает общие поля и, если задан projectID, поля проекта.
+	Fields(ctx context.Context, projectID string) ([]*CustomField, error)
+	DeleteField(ctx context.Context, actorID string, id string) error
+	// SetTaskFields задаёт значения полей задачи; пустое значение удаляет его.
+	SetTaskFields(ctx context.Context, taskID string, values map[string]string) ([]*FieldValue, error)
+}
+
+type CustomFieldRepository interface {
+	CreateField(ctx context.Context, field *CustomField) (*CustomField, error)
+	Field(ctx context.Context, id string) (*CustomField, error)
+	FieldByName(ctx context.Context, projectID string, name string) (*CustomField, error)
+	Fields(ctx context.Context, projectID string) ([]*CustomField, error)
+	DeleteField(ctx context.Context, id string) error
+	Task(ctx context.Context, id string) (*Task, error)
+	User(ctx context.Context, id string) (*User, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	// SetTaskFields записывает значения (пустое — удалить) одной транзакцией.
+	SetTaskFields(ctx context.Context, taskID string, values map[string]string) error
+	TaskFields(ctx context.Context, taskID string) ([]*FieldValue, error)
+}
@@ -2,34 +2,82 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+var ErrInvalidHistoryFilter = errors.New("invalid history filter")
+
 type EventType string
 
 const (
 	Changed EventType = "CHANGED"
 	Delete  EventType = "DELETE"
 	Create  EventType = "CREATE"
+	// Updated — значение, которое пишется в базу при изменении статьи.
+	Updated EventType = "UPDATED"
 )
 
+func (e EventType) Valid() bool {
+	switch e {
+	case Create, Updated, Delete:
+		return true
+	}
+	return false
+}
+
 type History struct {
 	ID           string
 	ArticleId    string
 	UserId       string
+	UserName     string // отображаемое имя автора изменения
 	ChangedAt    time.Time
 	EventType    EventType
 	ArticleTitle string
+	ChainLink
+}
+
+// HistoryFilter — выборка событий; пустые поля не ограничивают её, период
+// [From, To] включительно. По умолчанию новые события первыми.
+type HistoryFilter struct {
+	ArticleID string
+	UserID    string
+	EventType EventType
+	From      *time.Time
+	To        *time.Time
+	Ascending bool
+	Page      int
+	Limit     int
+}
+
+func (f *HistoryFilter) Normalize() error {
+	if f.EventType != "" && !f.EventType.Valid() {
+		return fmt.Errorf("%w: unknown event type", ErrInvalidHistoryFilter)
+	}
+	if f.From != nil && f.To != nil && f.To.Before(*f.From) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidHistoryFilter)
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageLimit
+	}
+	f.Limit = min(f.Limit, MaxPageLimit)
+	return nil
 }
 
 type HistoryInteractor interface {
 	InitHistory(ctx context.Context, articleID string, userID string, articleTitle string) error
 	UpdateHistory(ctx context.Context, articleID string, userID string, eventType EventType, articleTitle string) error
-	Histories(ctx context.Context, page, limit int) ([]*History, error)
+	Histories(ctx context.Context, filter HistoryFilter) ([]*History, error)
+	// ArticleHistory — события одной статьи, в том числе уже удалённой.
+	ArticleHistory(ctx context.Context, articleID string, filter HistoryFilter) ([]*History, error)
 }
 
 type HistoryRepository interface {
 	InitHistory(ctx context.Context, history *History) error
 	UpdateHistory(ctx context.Context, history *History) error
-	Histories(ctx context.Context, page, limit int) ([]*History, error)
+	Histories(ctx context.Context, filter HistoryFilter) ([]*History, error)
 }
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	ErrLabelNotFound = errors.New("label not found")
+	ErrInvalidLabel  = errors.New("invalid label")
+	ErrLabelExists   = errors.New("label with this name already exists")
+)
+
+var labelColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label — цветная метка. Метка без ProjectID общая для всех задач,
+// метка проекта применяется только к его задачам.
+type Label struct {
+	ID        string
+	Name      string
+	Color     string
+	ProjectID string
+	CreatedAt time.Time
+}
+
+// Validate проверяет имя и цвет метки (#rrggbb) и приводит их к единому виду.
+func (l *Label) Validate() error {
+	l.Name = strings.TrimSpace(l.Name)
+	if l.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLabel)
+	}
+	if !labelColor.MatchString(l.Color) {
+		return fmt.Errorf("%w: color must be #rrggbb", ErrInvalidLabel)
+	}
+	l.Color = strings.ToLower(l.Color)
+	return nil
+}
+
+// Метками проекта управляют его участники с правом редактирования.
+type LabelInteractor interface {
+	CreateLabel(ctx context.Context, actorID string, label *Label) (*Label, error)
+	UpdateLabel(ctx context.Context, actorID string, label *Label) (*Label, error)
+	// Labels возвращает общие метки и, если задан projectID, метки проекта.
+	Labels(ctx context.Context, projectID string) ([]*Label, error)
+	DeleteLabel(ctx context.Context, actorID string, id string) error
+	// SetTaskLabels заменяет метки задачи переданным набором.
+	SetTaskLabels(ctx context.Context, taskID string, labelIDs []string) ([]*Label, error)
+}
+
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, label *Label) (*Label, error)
+	UpdateLabel(ctx context.Context, label *Label) error
+	Label(ctx context.Context, id string) (*Label, error)
+	LabelByName(ctx context.Context, projectID string, name string) (*Label, error)
+	Labels(ctx context.Context, projectID string) ([]*Label, error)
+	LabelsByIDs(ctx context.Context, ids []string) ([]*Label, error)
+	DeleteLabel(ctx context.Context, id string) error
+	Task(ctx context.Context, id string) (*Task, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	SetTaskLabels(ctx context.Context, taskID string, labelIDs []string) error
+}
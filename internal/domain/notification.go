@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationKind string
+
+const (
+	NotifyReminder   NotificationKind = "REMINDER"
+	NotifyOverdue    NotificationKind = "OVERDUE"
+	NotifyEscalation NotificationKind = "ESCALATION"
+	// события задач, которые получают наблюдатели
+	NotifyStatusChanged   NotificationKind = "STATUS_CHANGED"
+	NotifyReassigned      NotificationKind = "REASSIGNED"
+	NotifyComment         NotificationKind = "COMMENT"
+	NotifyDeadlineChanged NotificationKind = "DEADLINE_CHANGED"
+)
+
+type Notification struct {
+	ID        string
+	UserID    string
+	Kind      NotificationKind
+	TaskID    string
+	Message   string
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+// Notifier — канал доставки уведомлений (в приложении, лог и т. п.).
+type Notifier interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
+
+type NotificationInteractor interface {
+	// Send доставляет уведомление во все каналы.
+	Send(ctx context.Context, notification *Notification) error
+	Notifications(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]*Notification, error)
+	MarkRead(ctx context.Context, userID string, id string) error
+	MarkAllRead(ctx context.Context, userID string) error
+}
+
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *Notification) error
+	Notifications(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]*Notification, error)
+	// MarkNotificationRead возвращает false, если у пользователя нет такого уведомления.
+	MarkNotificationRead(ctx context.Context, userID string, id string, at time.Time) (bool, error)
+	MarkAllNotificationsRead(ctx context.Context, userID string, at time.Time) error
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrInvalidProject     = errors.New("invalid project")
+	ErrProjectKeyExists   = errors.New("project with this key already exists")
+	ErrNotProjectMember   = errors.New("user is not a project member")
+	ErrProjectRole        = errors.New("project role does not allow this action")
+	ErrLastProjectOwner   = errors.New("project must keep at least one owner")
+	ErrInvalidProjectRole = errors.New("invalid project role")
+	ErrInvalidTaskKey     = errors.New("invalid task key")
+)
+
+// ProjectRole — роль участника проекта. VIEWER только читает,
+// MEMBER создаёт и ведёт задачи, OWNER также управляет участниками.
+type ProjectRole string
+
+const (
+	ProjectOwner  ProjectRole = "OWNER"
+	ProjectMember ProjectRole = "MEMBER"
+	ProjectViewer ProjectRole = "VIEWER"
+)
+
+func (r ProjectRole) Valid() bool {
+	return r == ProjectOwner || r == ProjectMember || r == ProjectViewer
+}
+
+// CanEdit — может ли роль создавать и менять задачи проекта.
+func (r ProjectRole) CanEdit() bool {
+	return r == ProjectOwner || r == ProjectMember
+}
+
+var projectKey = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+// Project — контейнер задач. Key — префикс номеров задач (OPS-42),
+// TaskCounter — последний выданный номер.
+type Project struct {
+	ID          string
+	Key         string
+	Name        string
+	Description string
+	TaskCounter int
+	CreatedByID string
+	CreatedAt   time.Time
+	Members     []*ProjectMembership
+}
+
+func (p *Project) Validate() error {
+	p.Key = strings.ToUpper(strings.TrimSpace(p.Key))
+	if !projectKey.MatchString(p.Key) {
+		return fmt.Errorf("%w: key must be 2-10 latin letters or digits starting with a letter", ErrInvalidProject)
+	}
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidProject)
+	}
+	return nil
+}
+
+type ProjectMembership struct {
+	ProjectID string
+	UserID    string
+	UserName  string
+	Role      ProjectRole
+	JoinedAt  time.Time
+}
+
+// TaskKey собирает человекочитаемый номер задачи: OPS-42.
+func TaskKey(projectKey string, number int) string {
+	return projectKey + "-" + strconv.Itoa(number)
+}
+
+// ParseTaskKey разбирает номер задачи вида OPS-42.
+func ParseTaskKey(key string) (string, int, error) {
+	i := strings.LastIndex(key, "-")
+	if i <= 0 {
+		return "", 0, ErrInvalidTaskKey
+	}
+	number, err := strconv.Atoi(key[i+1:])
+	if err != nil || number <= 0 {
+		return "", 0, ErrInvalidTaskKey
+	}
+	return strings.ToUpper(key[:i]), number, nil
+}
+
+type ProjectInteractor interface {
+	// CreateProject создаёт проект; автор становится его владельцем.
+	CreateProject(ctx context.Context, actorID string, project *Project) (*Project, error)
+	// Projects возвращает проекты, в которых состоит пользователь.
+	Projects(ctx context.Context, userID string) ([]*Project, error)
+	Project(ctx context.Context, id string, actorID string) (*Project, error)
+	SetMember(ctx context.Context, projectID string, actorID string, userID string, role ProjectRole) (*ProjectMembership, error)
+	RemoveMember(ctx context.Context, projectID string, actorID string, userID string) error
+	// Tasks возвращает задачи проекта участнику проекта.
+	Tasks(ctx context.Context, projectID string, actorID string, filter TaskFilter) (*TaskPage, error)
+}
+
+type ProjectRepository interface {
+	CreateProject(ctx context.Context, project *Project) (*Project, error)
+	Project(ctx context.Context, id string) (*Project, error)
+	ProjectByKey(ctx context.Context, key string) (*Project, error)
+	UserProjects(ctx context.Context, userID string) ([]*Project, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	ProjectMembers(ctx context.Context, projectID string) ([]*ProjectMembership, error)
+	SetProjectMember(ctx context.Context, member *ProjectMembership) error
+	// RemoveProjectMember удаляет участника, если после этого у проекта остаётся владелец; false — не остаётся.
+	RemoveProjectMember(ctx context.Context, projectID string, userID string) (bool, error)
+	// DemoteProjectOwner меняет роль владельца, если он не последний; false — последний.
+	DemoteProjectOwner(ctx context.Context, member *ProjectMembership) (bool, error)
+	User(ctx context.Context, id string) (*User, error)
+	Tasks(ctx context.Context, filter TaskFilter) ([]*Task, int, error)
+}
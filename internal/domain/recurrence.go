@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+	ErrInvalidEditScope  = errors.New("invalid edit scope")
+	ErrNotRecurring      = errors.New("task is not a recurrence occurrence")
+)
+
+type RecurrenceFrequency string
+
+const (
+	Daily   RecurrenceFrequency = "DAILY"
+	Weekly  RecurrenceFrequency = "WEEKLY"
+	Monthly RecurrenceFrequency = "MONTHLY"
+)
+
+// RecurrenceMode — когда создаётся следующее вхождение.
+type RecurrenceMode string
+
+const (
+	// OnSchedule — по расписанию, независимо от выполнения предыдущих.
+	OnSchedule RecurrenceMode = "SCHEDULE"
+	// OnCompletion — после завершения предыдущего вхождения; пропущенные даты не создаются.
+	OnCompletion RecurrenceMode = "COMPLETION"
+)
+
+// EditScope — какие вхождения повторяющейся задачи затрагивает изменение.
+type EditScope string
+
+const (
+	ScopeThis   EditScope = "this"
+	ScopeFuture EditScope = "future"
+)
+
+func (s EditScope) Valid() bool {
+	return s == ScopeThis || s == ScopeFuture
+}
+
+// Recurrence — шаблон повторяющейся задачи и правило повторения.
+// Вхождение с номером слота n запланировано на StartAt + n*Interval единиц Frequency.
+type Recurrence struct {
+	ID        string
+	Frequency RecurrenceFrequency
+	Interval  int
+	Until     *time.Time
+	// Count — сколько всего вхождений создать, 0 — без ограничения.
+	Count       int
+	Mode        RecurrenceMode
+	StartAt     time.Time
+	Slot        int
+	NextAt      time.Time
+	Occurrences int
+	Active      bool
+	Title       string
+	Content     string
+	Image       string
+	Priority    Priority
+	UserID      string
+	CreatorID   string
+	// ProjectID — проект, в котором создаются вхождения; пусто — задачи без проекта.
+	ProjectID string
+	CreatedAt time.Time
+}
+
+func (r *Recurrence) Validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly:
+	default:
+		return fmt.Errorf("%w: frequency %q", ErrInvalidRecurrence, r.Frequency)
+	}
+	if r.Mode != OnSchedule && r.Mode != OnCompletion {
+		return fmt.Errorf("%w: mode %q", ErrInvalidRecurrence, r.Mode)
+	}
+	if r.Interval < 1 || r.Count < 0 {
+		return fmt.Errorf("%w: interval and count must be positive", ErrInvalidRecurrence)
+	}
+	if r.Until != nil && r.Until.Before(r.StartAt) {
+		return fmt.Errorf("%w: until is before start", ErrInvalidRecurrence)
+	}
+	if !r.Priority.Valid() {
+		return ErrInvalidPriority
+	}
+	return nil
+}
+
+// OccurrenceAt возвращает дату слота. Для месячного повторения день месяца
+// берётся из StartAt и прижимается к концу короткого месяца (31 -> 28/29/30).
+func (r *Recurrence) OccurrenceAt(slot int) time.Time {
+	switch r.Frequency {
+	case Weekly:
+		return r.StartAt.AddDate(0, 0, 7*slot*r.Interval)
+	case Monthly:
+		year, month, day := r.StartAt.Date()
+		first := time.Date(year, month+time.Month(slot*r.Interval), 1,
+			r.StartAt.Hour(), r.StartAt.Minute(), r.StartAt.Second(), r.StartAt.Nanosecond(), r.StartAt.Location())
+		lastDay := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(day, lastDay)-1)
+	default:
+		return r.StartAt.AddDate(0, 0, slot*r.Interval)
+	}
+}
+
+// Exhausted сообщает, что вхождение на дату at уже не должно создаваться.
+func (r *Recurrence) Exhausted(at time.Time) bool {
+	if r.Count > 0 && r.Occurrences >= r.Count {
+		return true
+	}
+	return r.Until != nil && at.After(*r.Until)
+}
+
+// ApplyRRule заполняет правило из строки в духе RFC 5545:
+// "FREQ=WEEKLY;INTERVAL=2;COUNT=10" или "FREQ=MONTHLY;UNTIL=20261231T000000Z".
+func (r *Recurrence) ApplyRRule(rule string) error {
+	r.Interval = 1
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrInvalidRecurrence, part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Frequency = RecurrenceFrequency(strings.ToUpper(value))
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseRRuleTime(value)
+			r.Until = &until
+		default:
+			return fmt.Errorf("%w: unsupported part %q", ErrInvalidRecurrence, key)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidRecurrence, part)
+		}
+	}
+	return nil
+}
+
+func parseRRuleTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidRecurrence
+}
+
+type RecurrenceInteractor interface {
+	CreateRecurrence(ctx context.Context, recurrence *Recurrence, rrule string) (*Recurrence, error)
+	// Recurrence, Recurrences и StopRecurrence доступны для правил проекта только его участникам.
+	Recurrence(ctx context.Context, id string, userID string) (*Recurrence, error)
+	Recurrences(ctx context.Context, userID string) ([]*Recurrence, error)
+	StopRecurrence(ctx context.Context, id string, actorID string) error
+	Run(ctx context.Context)
+}
+
+type RecurrenceRepository interface {
+	CreateRecurrence(ctx context.Context, recurrence *Recurrence) (string, error)
+	Recurrence(ctx context.Context, id string) (*Recurrence, error)
+	Recurrences(ctx context.Context) ([]*Recurrence, error)
+	// DueRecurrences — активные правила, которым пора создать вхождение:
+	// по расписанию — NextAt не позже before, по завершению — нет открытых вхождений.
+	DueRecurrences(ctx context.Context, before time.Time) ([]*Recurrence, error)
+	// MaterializeOccurrence создаёт задачу и сохраняет продвинутое правило, только если
+	// число вхождений всё ещё равно prevOccurrences. Возвращает false, если опередили.
+	MaterializeOccurrence(ctx context.Context, recurrence *Recurrence, prevOccurrences int, task *Task) (bool, error)
+	StopRecurrence(ctx context.Context, id string) error
+	LastRank(ctx context.Context, projectID string, status Status) (string, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidReminderOffsets = errors.New("invalid reminder offsets")
+
+// DeadlineTask — открытая задача со сроком, которую проверяет планировщик напоминаний.
+type DeadlineTask struct {
+	ID        string
+	Title     string
+	UserID    string
+	CreatorID string
+	Priority  Priority
+	PlannedAt time.Time
+	// ReminderOffsets — смещения исполнителя в часах, RemindedOffsets — уже отправленные.
+	ReminderOffsets []int
+	RemindedOffsets []int
+}
+
+type ReminderInteractor interface {
+	Run(ctx context.Context)
+}
+
+type ReminderRepository interface {
+	// UpcomingTasks — открытые задачи со сроком в интервале (from, to].
+	UpcomingTasks(ctx context.Context, from time.Time, to time.Time) ([]*DeadlineTask, error)
+	// MarkReminded отмечает отправку напоминания за offset часов; false — уже отмечено.
+	MarkReminded(ctx context.Context, taskID string, offset int) (bool, error)
+	// MarkOverdue помечает просроченные открытые задачи и возвращает помеченные впервые.
+	MarkOverdue(ctx context.Context, now time.Time) ([]*DeadlineTask, error)
+	// MarkEscalated помечает просроченные раньше before задачи приоритета HIGH
+	// как эскалированные и возвращает их.
+	MarkEscalated(ctx context.Context, before time.Time, now time.Time) ([]*DeadlineTask, error)
+	// TaskAssignments — журнал назначений задачи, новые первыми.
+	TaskAssignments(ctx context.Context, taskID string) ([]*TaskAssignment, error)
+	Admins(ctx context.Context) ([]*User, error)
+}
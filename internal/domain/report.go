@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidReport = errors.New("invalid report period")
+
+const (
+	DefaultReportDays = 30
+	MaxReportDays     = 366
+)
+
+// ReportFilter — период отчёта [From, To) и его охват: ProjectID — команда
+// проекта, UserID — один пользователь, оба пустые — все пользователи.
+type ReportFilter struct {
+	From      time.Time
+	To        time.Time
+	ProjectID string
+	UserID    string
+}
+
+// Normalize подставляет период по умолчанию — последние DefaultReportDays дней до now.
+func (f *ReportFilter) Normalize(now time.Time) error {
+	if f.To.IsZero() {
+		f.To = now
+	}
+	if f.From.IsZero() {
+		f.From = f.To.AddDate(0, 0, -DefaultReportDays)
+	}
+	if !f.From.Before(f.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidReport)
+	}
+	if f.To.Sub(f.From) > MaxReportDays*24*time.Hour {
+		return fmt.Errorf("%w: period longer than %d days", ErrInvalidReport, MaxReportDays)
+	}
+	return nil
+}
+
+// WorkloadStats — суммы показателей пользователя, посчитанные в базе.
+// Открытые и просроченные задачи — на момент запроса, остальное — за период.
+// Время выполнения задачи (lead) считается от создания до перехода в
+// COMPLETED, время работы (cycle) — от первого перехода в CURRENT.
+type WorkloadStats struct {
+	UserID          string
+	UserName        string
+	OpenHigh        int
+	OpenMiddle      int
+	OpenLow         int
+	Overdue         int
+	Completed       int
+	CompletedLate   int
+	LeadSeconds     float64
+	CycleTasks      int
+	CycleSeconds    float64
+	ArticlesCreated int
+	ArticlesEdited  int
+}
+
+// Add прибавляет показатели other, чтобы получить итог по команде.
+func (s *WorkloadStats) Add(other *WorkloadStats) {
+	s.OpenHigh += other.OpenHigh
+	s.OpenMiddle += other.OpenMiddle
+	s.OpenLow += other.OpenLow
+	s.Overdue += other.Overdue
+	s.Completed += other.Completed
+	s.CompletedLate += other.CompletedLate
+	s.LeadSeconds += other.LeadSeconds
+	s.CycleTasks += other.CycleTasks
+	s.CycleSeconds += other.CycleSeconds
+	s.ArticlesCreated += other.ArticlesCreated
+	s.ArticlesEdited += other.ArticlesEdited
+}
+
+// Workload — показатели пользователя или команды в отчёте.
+// Средние равны 0, если за период не завершено ни одной задачи.
+type Workload struct {
+	UserID          string
+	UserName        string
+	Open            map[Priority]int
+	OpenTotal       int
+	Overdue         int
+	Completed       int
+	CompletedLate   int
+	AvgLeadHours    float64
+	AvgCycleHours   float64
+	ArticlesCreated int
+	ArticlesEdited  int
+}
+
+func (s *WorkloadStats) Workload() *Workload {
+	w := &Workload{
+		UserID:          s.UserID,
+		UserName:        s.UserName,
+		Open:            map[Priority]int{High: s.OpenHigh, Middle: s.OpenMiddle, Low: s.OpenLow},
+		OpenTotal:       s.OpenHigh + s.OpenMiddle + s.OpenLow,
+		Overdue:         s.Overdue,
+		Completed:       s.Completed,
+		CompletedLate:   s.CompletedLate,
+		ArticlesCreated: s.ArticlesCreated,
+		ArticlesEdited:  s.ArticlesEdited,
+	}
+	if s.Completed > 0 {
+		w.AvgLeadHours = s.LeadSeconds / float64(s.Completed) / 3600
+	}
+	if s.CycleTasks > 0 {
+		w.AvgCycleHours = s.CycleSeconds / float64(s.CycleTasks) / 3600
+	}
+	return w
+}
+
+// WorkloadReport — отчёт за период: строки по пользователям и итог.
+type WorkloadReport struct {
+	From      time.Time
+	To        time.Time
+	ProjectID string
+	Total     *Workload
+	Users     []*Workload
+}
+
+type ReportInteractor interface {
+	// Workload строит отчёт. Отчёт по себе доступен каждому, по команде
+	// проекта — его участникам, остальные — администраторам.
+	Workload(ctx context.Context, actorID string, filter ReportFilter) (*WorkloadReport, error)
+}
+
+type ReportRepository interface {
+	// WorkloadStats считает показатели агрегирующими запросами, по строке на пользователя.
+	// Для проекта учитываются его задачи, а пользователи — участники и исполнители задач проекта.
+	WorkloadStats(ctx context.Context, filter ReportFilter, now time.Time) ([]*WorkloadStats, error)
+	User(ctx context.Context, id string) (*User, error)
+	Project(ctx context.Context, id string) (*Project, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
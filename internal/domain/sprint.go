@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSprintNotFound  = errors.New("sprint not found")
+	ErrInvalidSprint   = errors.New("invalid sprint")
+	ErrInvalidEstimate = errors.New("invalid estimate")
+)
+
+// EstimateUnit — в чём оцениваются задачи спринта.
+type EstimateUnit string
+
+const (
+	UnitPoints EstimateUnit = "POINTS"
+	UnitHours  EstimateUnit = "HOURS"
+)
+
+type Sprint struct {
+	ID          string
+	Name        string
+	Goal        string
+	StartAt     time.Time
+	EndAt       time.Time
+	Unit        EstimateUnit
+	CreatedByID string
+	CreatedAt   time.Time
+	Tasks       []*Task
+}
+
+func (s *Sprint) Validate() error {
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSprint)
+	}
+	if !s.EndAt.After(s.StartAt) {
+		return fmt.Errorf("%w: end must be after start", ErrInvalidSprint)
+	}
+	if s.Unit == "" {
+		s.Unit = UnitPoints
+	}
+	if s.Unit != UnitPoints && s.Unit != UnitHours {
+		return fmt.Errorf("%w: unit %q", ErrInvalidSprint, s.Unit)
+	}
+	return nil
+}
+
+// BurnPoint — состояние спринта на конец дня Date, в единицах оценки.
+// Remaining — для burndown, Completed и Scope — для burnup, Ideal — равномерное сгорание объёма первого дня.
+type BurnPoint struct {
+	Date      time.Time
+	Scope     float64
+	Completed float64
+	Remaining float64
+	Ideal     float64
+}
+
+type SprintBurn struct {
+	Sprint *Sprint
+	Points []BurnPoint
+}
+
+// SprintVelocity — взятый в спринт и выполненный к его концу объём.
+type SprintVelocity struct {
+	SprintID  string
+	Name      string
+	StartAt   time.Time
+	EndAt     time.Time
+	Unit      EstimateUnit
+	Committed float64
+	Completed float64
+}
+
+type Velocity struct {
+	Sprints []*SprintVelocity
+	Average float64
+}
+
+type SprintInteractor interface {
+	CreateSprint(ctx context.Context, sprint *Sprint) (*Sprint, error)
+	UpdateSprint(ctx context.Context, sprint *Sprint) (*Sprint, error)
+	Sprint(ctx context.Context, id string) (*Sprint, error)
+	Sprints(ctx context.Context) ([]*Sprint, error)
+	DeleteSprint(ctx context.Context, id string) error
+	// PlanTask переносит задачу в спринт (пустой sprintID — убрать из спринта) и задаёт оценку.
+	PlanTask(ctx context.Context, taskID string, sprintID string, estimate *float64, actorID string) (*Task, error)
+	Burndown(ctx context.Context, id string) (*SprintBurn, error)
+	// Velocity возвращает limit последних завершившихся спринтов.
+	Velocity(ctx context.Context, limit int) (*Velocity, error)
+}
+
+type SprintRepository interface {
+	CreateSprint(ctx context.Context, sprint *Sprint) (*Sprint, error)
+	UpdateSprint(ctx context.Context, sprint *Sprint) error
+	Sprint(ctx context.Context, id string) (*Sprint, error)
+	Sprints(ctx context.Context) ([]*Sprint, error)
+	// FinishedSprints возвращает до limit спринтов, закончившихся до before, новые первыми.
+	FinishedSprints(ctx context.Context, before time.Time, limit int) ([]*Sprint, error)
+	DeleteSprint(ctx context.Context, id string) error
+	Task(ctx context.Context, id string) (*Task, error)
+	PlanTask(ctx context.Context, taskID string, sprintID string, estimate *float64) error
+	SprintTasks(ctx context.Context, sprintIDs []string) ([]*Task, error)
+	TasksTransitions(ctx context.Context, taskIDs []string) ([]*TaskTransition, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
+
+// CompletedBy восстанавливает по переходам, была ли задача завершена к моменту at.
+// transitions — переходы задачи по возрастанию времени; без переходов
+// учитывается текущий статус и CompletedAt.
+func CompletedBy(task *Task, transitions []*TaskTransition, at time.Time) bool {
+	if len(transitions) == 0 {
+		return task.Status == Completed && task.CompletedAt != nil && !task.CompletedAt.After(at)
+	}
+	status := transitions[0].From
+	for _, transition := range transitions {
+		if transition.ChangedAt.After(at) {
+			break
+		}
+		status = transition.To
+	}
+	return status == Completed
+}
+
+// CalculateBurn строит ряд по дням спринта start–end: days — концы дней по возрастанию.
+// Задача входит в объём дня, если создана к его концу; неоценённые задачи весят 0.
+func CalculateBurn(tasks []*Task, transitions map[string][]*TaskTransition, start time.Time, end time.Time, days []time.Time) []BurnPoint {
+	points := make([]BurnPoint, 0, len(days))
+	for _, day := range days {
+		point := BurnPoint{Date: day}
+		for _, task := range tasks {
+			if task.CreatedAt.After(day) {
+				continue
+			}
+			estimate := task.EstimateValue()
+			point.Scope += estimate
+			if CompletedBy(task, transitions[task.ID], day) {
+				point.Completed += estimate
+			}
+		}
+		point.Remaining = point.Scope - point.Completed
+		points = append(points, point)
+	}
+	// идеальная линия сжигает объём первого дня равномерно до нуля к концу спринта
+	for i := range points {
+		left := end.Sub(points[i].Date).Seconds() / end.Sub(start).Seconds()
+		points[i].Ideal = points[0].Scope * max(left, 0)
+	}
+	return points
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "errors"
+
+var (
+	ErrParentNotFound = errors.New("parent task not found")
+	ErrTaskCycle      = errors.New("task cannot be nested into itself or its subtask")
+	ErrOpenSubtasks   = errors.New("task has open subtasks")
+)
+
+// TaskProgress — выполнение задачи по всем вложенным подзадачам и пунктам чек-листа.
+type TaskProgress struct {
+	Subtasks          int
+	CompletedSubtasks int
+	ChecklistItems    int
+	DoneItems         int
+	Percent           int
+}
+
+// Calculate считает процент выполнения. Задача без подзадач и чек-листа
+// выполнена на 100% только в статусе COMPLETED.
+func (p *TaskProgress) Calculate(status Status) {
+	total := p.Subtasks + p.ChecklistItems
+	if total == 0 {
+		p.Percent = 0
+		if status == Completed {
+			p.Percent = 100
+		}
+		return
+	}
+	p.Percent = (p.CompletedSubtasks + p.DoneItems) * 100 / total
+}
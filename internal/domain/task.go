@@ -2,9 +2,12 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type Status string
 
 const (
@@ -27,25 +30,143 @@ type Task struct {
 	Content          string
 	Image            string
 	CreatedAt        time.Time
+	UpdatedAt        time.Time
 	UserID           string
 	ReliableUserName string
+	UserLogin        string
+	CreatorID        string
+	CreatorName      string
 	PlannedAt        time.Time
 	Priority         Priority
 	Status           Status
+	StartedAt        *time.Time
+	CompletedAt      *time.Time
+	// OverdueAt — когда планировщик отметил задачу просроченной.
+	OverdueAt *time.Time
+	// Rank — позиция задачи в колонке доски (см. lib.RankBetween).
+	Rank     string
+	ParentID string
+	// RecurrenceID и Occurrence (номер с 1) задаются у вхождений повторяющейся задачи.
+	RecurrenceID string
+	Occurrence   int
+	// Estimate — оценка в единицах спринта (см. Sprint.Unit), nil — не оценена.
+	SprintID string
+	Estimate *float64
+	// ProjectID, ProjectKey и Number задаются у задач проекта; Key — номер вида OPS-42.
+	ProjectID  string
+	ProjectKey string
+	Number     int
+	Key        string
+	Labels     []*Label
+	Fields     []*FieldValue
+	// Progress, BlockedBy, Blocking и Articles заполняются только при запросе одной задачи.
+	Progress  *TaskProgress
+	BlockedBy []*Task
+	Blocking  []*Task
+	Articles  []*TaskArticleLink
+	// Blocked — среди блокирующих задач есть незавершённые.
+	Blocked bool
+}
+
+// EstimateValue возвращает оценку задачи или 0, если она не задана.
+func (t *Task) EstimateValue() float64 {
+	if t.Estimate == nil {
+		return 0
+	}
+	return *t.Estimate
+}
+
+// TaskDraft — новая задача пакета вместе с метками, значениями полей
+// и пунктами чек-листа (см. TaskInteractor.CreateTasks).
+type TaskDraft struct {
+	Task Task
+	// Parent — задача того же пакета, подзадачей которой становится эта;
+	// должна идти в пакете раньше неё.
+	Parent    *TaskDraft
+	LabelIDs  []string
+	Fields    map[string]string
+	Checklist []string
+}
+
+// TaskAssignment — запись журнала переназначений задачи.
+type TaskAssignment struct {
+	ID          string
+	TaskID      string
+	FromUserID  string
+	ToUserID    string
+	ChangedByID string
+	ChangedAt   time.Time
 }
 
 type TaskInteractor interface {
-	CreateTask(ctx context.Context, title string, image string, content string, planned_at time.Time, userID string, priority Priority, status Status) (string, error)
-	Task(ctx context.Context, id string) (*Task, error)
-	Tasks(ctx context.Context, page, limit int) ([]*Task, error)
-	UpdateTask(ctx context.Context, id string, title string, image string, content string, userID string, priority Priority, status Status) error
-	DeleteTask(ctx context.Context, id string) error
+	CreateTask(ctx context.Context, title string, image string, content string, planned_at time.Time, userID string, creatorID string, parentID string, projectID string, priority Priority, status Status) (string, error)
+	// CreateTasks проверяет задачи пакета как CreateTask и создаёт их одной
+	// транзакцией: либо все, либо ни одной. Возвращает id в порядке пакета.
+	CreateTasks(ctx context.Context, creatorID string, drafts []*TaskDraft) ([]string, error)
+	// Task, TaskByKey и Tasks отдают задачи проектов только их участникам.
+	Task(ctx context.Context, id string, actorID string) (*Task, error)
+	// TaskByKey находит задачу проекта по номеру вида OPS-42.
+	TaskByKey(ctx context.Context, key string, actorID string) (*Task, error)
+	Tasks(ctx context.Context, actorID string, filter TaskFilter) (*TaskPage, error)
+	MyTasks(ctx context.Context, userID string, page, limit int) ([]*Task, error)
+	AssignedByMe(ctx context.Context, userID string, page, limit int) ([]*Task, error)
+	UpdateTask(ctx context.Context, id string, title string, image string, content string, planned_at time.Time, userID string, actorID string, priority Priority, status Status, scope EditScope) error
+	AssignTask(ctx context.Context, id string, userID string, actorID string) error
+	Assignments(ctx context.Context, id string, userID string) ([]*TaskAssignment, error)
+	TransitionTask(ctx context.Context, id string, status Status, actorID string) (*Task, error)
+	Transitions(ctx context.Context, id string, userID string) ([]*TaskTransition, error)
+	SetParent(ctx context.Context, id string, parentID string, actorID string) error
+	Subtasks(ctx context.Context, id string, userID string) ([]*Task, error)
+	DeleteTask(ctx context.Context, id string, actorID string) error
+	// CreateFromTemplate создаёт задачу по шаблону, а для плейбука — и подзадачи
+	// по его шагам. Возвращает id созданных задач, первой — основную.
+	CreateFromTemplate(ctx context.Context, req *TemplateRequest) ([]string, error)
+	// BulkUpdate применяет операцию к задачам по id или фильтру с результатом по каждой задаче.
+	BulkUpdate(ctx context.Context, actorID string, req *BulkRequest) (*BulkResult, error)
 }
 
 type TaskRepository interface {
 	CreateTask(ctx context.Context, task *Task) (string, error)
+	// CreateTasks создаёт задачи пакета одной транзакцией. Номера в проекте
+	// выдаются в ней же, создатель и исполнитель подписываются на задачи.
+	CreateTasks(ctx context.Context, drafts []*TaskDraft) ([]string, error)
 	Task(ctx context.Context, id string) (*Task, error)
-	Tasks(ctx context.Context, page, limit int) ([]*Task, error)
+	Tasks(ctx context.Context, filter TaskFilter) ([]*Task, int, error)
+	TasksByAssignee(ctx context.Context, userID string, page, limit int) ([]*Task, error)
+	TasksByCreator(ctx context.Context, creatorID string, page, limit int) ([]*Task, error)
 	UpdateTask(ctx context.Context, task *Task) error
+	ReassignTask(ctx context.Context, assignment *TaskAssignment) error
+	TaskAssignments(ctx context.Context, taskID string) ([]*TaskAssignment, error)
+	TransitionTask(ctx context.Context, task *Task, transition *TaskTransition) error
+	TaskTransitions(ctx context.Context, taskID string) ([]*TaskTransition, error)
+	// LastRank возвращает наибольший ранг в колонке status доски проекта projectID.
+	LastRank(ctx context.Context, projectID string, status Status) (string, error)
+	SetTaskParent(ctx context.Context, id string, parentID string) (bool, error)
+	Subtasks(ctx context.Context, id string) ([]*Task, error)
+	OpenSubtasks(ctx context.Context, id string) (int, error)
+	TaskProgress(ctx context.Context, id string) (*TaskProgress, error)
+	TaskBlockers(ctx context.Context, id string) ([]*Task, error)
+	TaskBlocking(ctx context.Context, id string) ([]*Task, error)
+	OpenBlockers(ctx context.Context, id string) (int, error)
+	Recurrence(ctx context.Context, id string) (*Recurrence, error)
+	// UpdateFutureOccurrences сохраняет шаблон правила и переносит его поля
+	// и исполнителя на незавершённые вхождения с номером больше fromOccurrence.
+	// С reschedule они получают даты нового расписания: вхождение
+	// fromOccurrence+n — дату слота n.
+	UpdateFutureOccurrences(ctx context.Context, recurrence *Recurrence, fromOccurrence int, actorID string, reschedule bool) error
+	Field(ctx context.Context, id string) (*CustomField, error)
+	Project(ctx context.Context, id string) (*Project, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	// NextTaskNumber атомарно увеличивает счётчик задач проекта и возвращает новый номер.
+	NextTaskNumber(ctx context.Context, projectID string) (int, error)
+	TaskByKey(ctx context.Context, projectKey string, number int) (*Task, error)
+	TaskArticleLinks(ctx context.Context, taskID string) ([]*TaskArticleLink, error)
+	TasksByIDs(ctx context.Context, ids []string) ([]*Task, error)
+	Label(ctx context.Context, id string) (*Label, error)
+	Template(ctx context.Context, id string) (*TaskTemplate, error)
+	// ApplyBulk применяет изменения одной транзакцией. Новый исполнитель
+	// подписывается на задачу в той же транзакции. Смена статуса не применяется
+	// к задачам, статус которых изменился после проверки: их id возвращаются в conflicts.
+	ApplyBulk(ctx context.Context, operation BulkOperation, changes []*BulkChange) (conflicts []string, err error)
 	DeleteTask(ctx context.Context, id string) error
 }
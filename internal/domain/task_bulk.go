@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidBulk  = errors.New("invalid bulk operation")
+	ErrBulkTooLarge = errors.New("too many tasks for bulk operation")
+)
+
+// MaxBulkTasks — сколько задач можно изменить одной массовой операцией.
+const MaxBulkTasks = MaxPageLimit
+
+type BulkOperation string
+
+const (
+	BulkStatus   BulkOperation = "status"
+	BulkPriority BulkOperation = "priority"
+	BulkAssign   BulkOperation = "assign"
+	BulkAddLabel BulkOperation = "add_label"
+	BulkDelete   BulkOperation = "delete"
+)
+
+// BulkRequest — массовая операция над задачами IDs или, если IDs пусты,
+// над задачами по фильтру Filter. Из Status, Priority, UserID и LabelID
+// используется поле, соответствующее операции.
+type BulkRequest struct {
+	IDs       []string
+	Filter    *TaskFilter
+	Operation BulkOperation
+	Status    Status
+	Priority  Priority
+	UserID    string
+	LabelID   string
+}
+
+func (r *BulkRequest) Validate() error {
+	if len(r.IDs) == 0 && r.Filter == nil {
+		return fmt.Errorf("%w: ids or filter required", ErrInvalidBulk)
+	}
+	if len(r.IDs) > MaxBulkTasks {
+		return ErrBulkTooLarge
+	}
+	switch r.Operation {
+	case BulkStatus:
+		if !r.Status.Valid() {
+			return ErrInvalidStatus
+		}
+	case BulkPriority:
+		if !r.Priority.Valid() {
+			return ErrInvalidPriority
+		}
+	case BulkAssign:
+		if r.UserID == "" {
+			return fmt.Errorf("%w: user_id required", ErrInvalidBulk)
+		}
+	case BulkAddLabel:
+		if r.LabelID == "" {
+			return fmt.Errorf("%w: label_id required", ErrInvalidBulk)
+		}
+	case BulkDelete:
+	default:
+		return fmt.Errorf("%w: unknown operation %q", ErrInvalidBulk, r.Operation)
+	}
+	return nil
+}
+
+// BulkChange — проверенное изменение одной задачи. Task содержит новые
+// статус и приоритет; Transition и Assignment пишутся в журналы, Event
+// рассылается наблюдателям после применения.
+type BulkChange struct {
+	Task       *Task
+	Transition *TaskTransition
+	Assignment *TaskAssignment
+	LabelID    string
+	Event      *TaskEvent
+}
+
+// BulkItemResult — итог по одной задаче. Changed = false без ошибки —
+// задача уже была в нужном состоянии.
+type BulkItemResult struct {
+	TaskID  string
+	Changed bool
+	Error   string
+}
+
+type BulkResult struct {
+	Operation BulkOperation
+	Applied   int
+	Failed    int
+	Items     []*BulkItemResult
+}
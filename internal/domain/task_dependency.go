@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDependencyCycle    = errors.New("dependency would create a cycle")
+	ErrDependencyExists   = errors.New("dependency already exists")
+	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrTaskBlocked        = errors.New("task is blocked by incomplete tasks")
+)
+
+// TaskDependency — задача BlockerID блокирует задачу BlockedID.
+type TaskDependency struct {
+	ID          string
+	BlockerID   string
+	BlockedID   string
+	CreatedByID string
+	CreatedAt   time.Time
+}
+
+// CriticalPathStep — задача на критическом пути. Finish — самый ранний срок
+// завершения с учётом блокирующих задач, Delay — насколько он позже PlannedAt.
+type CriticalPathStep struct {
+	Task   *Task
+	Finish time.Time
+	Delay  time.Duration
+}
+
+type CriticalPath struct {
+	Steps  []*CriticalPathStep
+	Finish time.Time
+}
+
+type DependencyInteractor interface {
+	AddDependency(ctx context.Context, blockerID string, blockedID string, actorID string) (*TaskDependency, error)
+	RemoveDependency(ctx context.Context, blockerID string, blockedID string, actorID string) error
+	CriticalPath(ctx context.Context, ids []string, userID string) (*CriticalPath, error)
+}
+
+type DependencyRepository interface {
+	Task(ctx context.Context, id string) (*Task, error)
+	TasksByIDs(ctx context.Context, ids []string) ([]*Task, error)
+	Dependency(ctx context.Context, blockerID string, blockedID string) (*TaskDependency, error)
+	// AddDependency возвращает false, если связь замкнула бы цикл.
+	AddDependency(ctx context.Context, dependency *TaskDependency) (bool, error)
+	DeleteDependency(ctx context.Context, id string) error
+	Dependencies(ctx context.Context, ids []string) ([]*TaskDependency, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
+
+// CalculateCriticalPath строит критический путь по срокам PlannedAt: задача не может
+// завершиться раньше своих блокирующих задач, поэтому её срок сдвигается до самого
+// позднего из них. Путь — цепочка блокировок, определяющая итоговый срок набора.
+// Учитываются только связи внутри переданного набора задач.
+func CalculateCriticalPath(tasks []*Task, dependencies []*TaskDependency) (*CriticalPath, error) {
+	byID := make(map[string]*Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	blockers := make(map[string][]string)
+	blocking := make(map[string][]string)
+	pending := make(map[string]int, len(tasks))
+	for _, dep := range dependencies {
+		if byID[dep.BlockerID] == nil || byID[dep.BlockedID] == nil {
+			continue
+		}
+		blockers[dep.BlockedID] = append(blockers[dep.BlockedID], dep.BlockerID)
+		blocking[dep.BlockerID] = append(blocking[dep.BlockerID], dep.BlockedID)
+		pending[dep.BlockedID]++
+	}
+
+	// Топологический порядок (алгоритм Кана): блокирующие задачи идут раньше заблокированных.
+	queue := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		if pending[task.ID] == 0 {
+			queue = append(queue, task.ID)
+		}
+	}
+	finish := make(map[string]time.Time, len(tasks))
+	prev := make(map[string]string, len(tasks))
+	var last string
+	for i := 0; i < len(queue); i++ {
+		id := queue[i]
+		finish[id] = byID[id].PlannedAt
+		for _, blockerID := range blockers[id] {
+			if finish[blockerID].After(finish[id]) {
+				finish[id] = finish[blockerID]
+				prev[id] = blockerID
+			}
+		}
+		// При равных сроках выбираем задачу дальше по цепочке, чтобы путь был полным.
+		if last == "" || !finish[id].Before(finish[last]) {
+			last = id
+		}
+		for _, blockedID := range blocking[id] {
+			pending[blockedID]--
+			if pending[blockedID] == 0 {
+				queue = append(queue, blockedID)
+			}
+		}
+	}
+	if len(queue) < len(byID) {
+		return nil, ErrDependencyCycle
+	}
+
+	path := &CriticalPath{}
+	if last == "" {
+		return path, nil
+	}
+	path.Finish = finish[last]
+	for id := last; id != ""; id = prev[id] {
+		task := byID[id]
+		path.Steps = append(path.Steps, &CriticalPathStep{
+			Task:   task,
+			Finish: finish[id],
+			Delay:  finish[id].Sub(task.PlannedAt),
+		})
+	}
+	for i, j := 0, len(path.Steps)-1; i < j; i, j = i+1, j-1 {
+		path.Steps[i], path.Steps[j] = path.Steps[j], path.Steps[i]
+	}
+	return path, nil
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidSort = errors.New("invalid task sort field")
+
+type TaskSort string
+
+const (
+	SortCreatedAt TaskSort = "created_at"
+	SortPlannedAt TaskSort = "planned_at"
+	SortPriority  TaskSort = "priority"
+)
+
+func (s TaskSort) Valid() bool {
+	switch s {
+	case SortCreatedAt, SortPlannedAt, SortPriority, SortRank:
+		return true
+	}
+	return false
+}
+
+// TaskFilter — параметры выборки задач. Пустые поля не ограничивают выборку.
+type TaskFilter struct {
+	Statuses    []Status
+	Priorities  []Priority
+	AssigneeID  string
+	PlannedFrom *time.Time
+	PlannedTo   *time.Time
+	Query       string
+	// OverdueAt — только незавершённые задачи со сроком раньше этого момента.
+	OverdueAt *time.Time
+	ProjectID string
+	// ViewerID — только задачи без проекта и задачи проектов, где пользователь состоит.
+	ViewerID string
+	// LabelIDs — задачи хотя бы с одной из меток.
+	LabelIDs []string
+	// Fields — id пользовательского поля → значение, которому оно должно быть равно.
+	Fields map[string]string
+	SortBy TaskSort
+	Desc   bool
+	Page   int
+	Limit  int
+}
+
+// TaskPage — страница задач с общим количеством для пагинации.
+type TaskPage struct {
+	Tasks []*Task
+	Total int
+	Page  int
+	Limit int
+	Pages int
+}
+
+const (
+	DefaultPageLimit = 6
+	MaxPageLimit     = 100
+)
+
+// Normalize проверяет фильтр и подставляет значения по умолчанию.
+func (f *TaskFilter) Normalize() error {
+	for _, status := range f.Statuses {
+		if !status.Valid() {
+			return ErrInvalidStatus
+		}
+	}
+	for _, priority := range f.Priorities {
+		if !priority.Valid() {
+			return ErrInvalidPriority
+		}
+	}
+	if f.SortBy == "" {
+		f.SortBy = SortCreatedAt
+	}
+	if !f.SortBy.Valid() {
+		return ErrInvalidSort
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageLimit
+	}
+	f.Limit = min(f.Limit, MaxPageLimit)
+	return nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrInvalidTableFormat = errors.New("unsupported table format")
+	ErrInvalidImport      = errors.New("invalid import file")
+)
+
+// TableFormat — формат выгрузки и загрузки задач.
+type TableFormat string
+
+const (
+	FormatCSV  TableFormat = "csv"
+	FormatXLSX TableFormat = "xlsx"
+)
+
+func (f TableFormat) Valid() bool {
+	return f == FormatCSV || f == FormatXLSX
+}
+
+const (
+	// MaxImportRows — сколько задач можно загрузить одним файлом.
+	MaxImportRows = 1000
+	// MaxImportColumns — сколько столбцов читается из файла загрузки.
+	MaxImportColumns = 256
+)
+
+// Столбцы таблицы задач. Выгрузка пишет все, загрузка читает TaskImportColumns
+// и пропускает остальные, чтобы выгруженный файл можно было загрузить обратно.
+// Пользовательские поля идут столбцами FieldColumnPrefix+имя поля.
+var (
+	TaskExportColumns = []string{"key", "title", "status", "priority", "assignee", "assignee_name", "creator", "planned_at", "created_at", "completed_at", "project", "labels", "content"}
+	TaskImportColumns = []string{"title", "status", "priority", "assignee", "planned_at", "project", "labels", "content"}
+)
+
+const FieldColumnPrefix = "field:"
+
+// ImportRowError — ошибка в строке таблицы; Row — номер строки с 1, строка 1 — заголовок.
+type ImportRowError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+// ImportResult — итог загрузки. При ошибках в строках задачи не создаются;
+// при DryRun файл только проверяется.
+type ImportResult struct {
+	DryRun  bool
+	Rows    int
+	Created int
+	TaskIDs []string
+	Errors  []*ImportRowError
+}
+
+type ImportOptions struct {
+	Format TableFormat
+	DryRun bool
+	// ProjectID — проект для строк без столбца project.
+	ProjectID string
+}
+
+type TaskTableInteractor interface {
+	// ExportTasks пишет задачи по фильтру в w постранично, не собирая выгрузку в памяти.
+	// Задачи проекта выгружает только его участник.
+	ExportTasks(ctx context.Context, actorID string, filter TaskFilter, format TableFormat, w io.Writer) error
+	ImportTasks(ctx context.Context, actorID string, file io.ReaderAt, size int64, opts ImportOptions) (*ImportResult, error)
+}
+
+type TaskTableRepository interface {
+	User(ctx context.Context, id string) (*User, error)
+	UserByLogin(ctx context.Context, login string) (*User, error)
+	ProjectByKey(ctx context.Context, key string) (*Project, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	Fields(ctx context.Context, projectID string) ([]*CustomField, error)
+	FieldByName(ctx context.Context, projectID string, name string) (*CustomField, error)
+	LabelByName(ctx context.Context, projectID string, name string) (*Label, error)
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrTemplateNotFound = errors.New("task template not found")
+	ErrInvalidTemplate  = errors.New("invalid task template")
+)
+
+const (
+	MaxTemplateChecklist = 100
+	MaxPlaybookSteps     = 50
+	MaxTemplateDueOffset = 365 * 24
+)
+
+// TaskTemplate — заготовка задачи. Шаблон с шагами (Steps) — плейбук:
+// по нему создаётся задача и по подзадаче на каждый шаг.
+type TaskTemplate struct {
+	ID   string
+	Name string
+	// TitlePattern — заголовок с подстановками {date} и {имя параметра}.
+	TitlePattern string
+	Content      string
+	Priority     Priority
+	Checklist    []string
+	// DueOffsetHours — срок в часах: у основной задачи — от момента создания,
+	// у шага плейбука — от срока основной задачи.
+	DueOffsetHours int
+	ProjectID      string
+	ParentID       string
+	Position       int
+	CreatedByID    string
+	CreatedAt      time.Time
+	Steps          []*TaskTemplate
+}
+
+// Validate проверяет шаблон и его шаги; шаги не могут иметь своих шагов.
+func (t *TaskTemplate) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTemplate)
+	}
+	if err := t.validateTask(); err != nil {
+		return err
+	}
+	if len(t.Steps) > MaxPlaybookSteps {
+		return fmt.Errorf("%w: at most %d playbook steps", ErrInvalidTemplate, MaxPlaybookSteps)
+	}
+	for i, step := range t.Steps {
+		if len(step.Steps) > 0 {
+			return fmt.Errorf("%w: playbook steps cannot have steps", ErrInvalidTemplate)
+		}
+		step.ProjectID = t.ProjectID
+		step.Position = i
+		if err := step.validateTask(); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validateTask проверяет поля, из которых собирается задача.
+func (t *TaskTemplate) validateTask() error {
+	t.TitlePattern = strings.TrimSpace(t.TitlePattern)
+	if t.TitlePattern == "" {
+		return fmt.Errorf("%w: title pattern is required", ErrInvalidTemplate)
+	}
+	if t.Priority == "" {
+		t.Priority = Middle
+	}
+	if !t.Priority.Valid() {
+		return ErrInvalidPriority
+	}
+	if t.DueOffsetHours < 0 || t.DueOffsetHours > MaxTemplateDueOffset {
+		return fmt.Errorf("%w: due offset must be 0-%d hours", ErrInvalidTemplate, MaxTemplateDueOffset)
+	}
+	if len(t.Checklist) > MaxTemplateChecklist {
+		return fmt.Errorf("%w: at most %d checklist items", ErrInvalidTemplate, MaxTemplateChecklist)
+	}
+	checklist := make([]string, 0, len(t.Checklist))
+	for _, item := range t.Checklist {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if utf8.RuneCountInString(item) > 500 {
+			return fmt.Errorf("%w: checklist item longer than 500 characters", ErrInvalidTemplate)
+		}
+		checklist = append(checklist, item)
+	}
+	t.Checklist = checklist
+	return nil
+}
+
+var templateParam = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
+// Title подставляет параметры в шаблон заголовка. {date} — дата at
+// в формате YYYY-MM-DD, если не передана явно; отсутствующий параметр — ошибка.
+func (t *TaskTemplate) Title(params map[string]string, at time.Time) (string, error) {
+	var missing []string
+	title := templateParam.ReplaceAllStringFunc(t.TitlePattern, func(match string) string {
+		name := match[1 : len(match)-1]
+		if value, ok := params[name]; ok {
+			return value
+		}
+		if name == "date" {
+			return at.Format(time.DateOnly)
+		}
+		missing = append(missing, name)
+		return match
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: missing parameters %s", ErrInvalidTemplate, strings.Join(missing, ", "))
+	}
+	return title, nil
+}
+
+// TemplateRequest — параметры создания задач по шаблону. Пустой UserID —
+// задачи назначаются создателю, нулевой PlannedAt — срок по DueOffsetHours.
+type TemplateRequest struct {
+	TemplateID string
+	CreatorID  string
+	UserID     string
+	ProjectID  string
+	ParentID   string
+	PlannedAt  time.Time
+	Params     map[string]string
+}
+
+type TaskTemplateInteractor interface {
+	CreateTemplate(ctx context.Context, actorID string, template *TaskTemplate) (*TaskTemplate, error)
+	Template(ctx context.Context, id string) (*TaskTemplate, error)
+	// Templates возвращает общие шаблоны и шаблоны проекта projectID.
+	Templates(ctx context.Context, projectID string) ([]*TaskTemplate, error)
+	DeleteTemplate(ctx context.Context, actorID string, id string) error
+}
+
+type TaskTemplateRepository interface {
+	// CreateTemplate сохраняет шаблон вместе с шагами.
+	CreateTemplate(ctx context.Context, template *TaskTemplate) (*TaskTemplate, error)
+	Template(ctx context.Context, id string) (*TaskTemplate, error)
+	Templates(ctx context.Context, projectID string) ([]*TaskTemplate, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	User(ctx context.Context, id string) (*User, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
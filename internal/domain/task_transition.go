@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidStatus        = errors.New("invalid task status")
+	ErrInvalidPriority      = errors.New("invalid task priority")
+	ErrTransitionNotAllowed = errors.New("task status transition is not allowed")
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case Current, Pending, Completed:
+		return true
+	}
+	return false
+}
+
+func (p Priority) Valid() bool {
+	switch p {
+	case High, Middle, Low:
+		return true
+	}
+	return false
+}
+
+// TransitionRule разрешает переход From -> To. Пустой Roles — переход доступен всем.
+type TransitionRule struct {
+	From  Status
+	To    Status
+	Roles []Role
+}
+
+// TransitionGraph — допустимые переходы между статусами задач.
+type TransitionGraph struct {
+	rules []TransitionRule
+	// CloseSubtasksFirst запрещает завершать задачу, пока у неё есть незавершённые подзадачи.
+	CloseSubtasksFirst bool
+}
+
+func NewTransitionGraph(rules []TransitionRule) *TransitionGraph {
+	if len(rules) == 0 {
+		rules = DefaultTransitionRules()
+	}
+	return &TransitionGraph{rules: rules}
+}
+
+// TransitionGuards — данные о связанных задачах, от которых зависит допустимость перехода.
+type TransitionGuards interface {
+	OpenSubtasks(ctx context.Context, id string) (int, error)
+	OpenBlockers(ctx context.Context, id string) (int, error)
+}
+
+// CheckGuards проверяет условия перехода задачи id в статус to помимо графа:
+// в работу нельзя взять задачу с незавершёнными блокирующими задачами,
+// завершить — задачу с открытыми подзадачами (если включено CloseSubtasksFirst).
+func (g *TransitionGraph) CheckGuards(ctx context.Context, guards TransitionGuards, id string, to Status) error {
+	switch {
+	case to == Current:
+		open, err := guards.OpenBlockers(ctx, id)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrTaskBlocked
+		}
+	case to == Completed && g.CloseSubtasksFirst:
+		open, err := guards.OpenSubtasks(ctx, id)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrOpenSubtasks
+		}
+	}
+	return nil
+}
+
+// DefaultTransitionRules: PENDING -> CURRENT -> COMPLETED, возврат в работу
+// и переоткрытие завершённой задачи — только администратору.
+func DefaultTransitionRules() []TransitionRule {
+	return []TransitionRule{
+		{From: Pending, To: Current},
+		{From: Current, To: Pending},
+		{From: Current, To: Completed},
+		{From: Pending, To: Completed, Roles: []Role{AdminRole}},
+		{From: Completed, To: Current, Roles: []Role{AdminRole}},
+	}
+}
+
+func (g *TransitionGraph) Allowed(from Status, to Status, role Role) bool {
+	for _, rule := range g.rules {
+		if rule.From == from && rule.To == to {
+			if len(rule.Roles) == 0 || slices.Contains(rule.Roles, role) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Targets возвращает статусы, в которые можно перейти из from.
+func (g *TransitionGraph) Targets(from Status, role Role) []Status {
+	var targets []Status
+	for _, rule := range g.rules {
+		if rule.From == from && !slices.Contains(targets, rule.To) && g.Allowed(from, rule.To, role) {
+			targets = append(targets, rule.To)
+		}
+	}
+	return targets
+}
+
+// TaskTransition — запись журнала смены статуса задачи.
+type TaskTransition struct {
+	ID        string
+	TaskID    string
+	From      Status
+	To        Status
+	UserID    string
+	ChangedAt time.Time
+}
+
+// ApplyStatus меняет статус задачи и проставляет отметки времени:
+// первый переход в работу фиксирует StartedAt, завершение — CompletedAt,
+// переоткрытие сбрасывает CompletedAt.
+func (t *Task) ApplyStatus(status Status, now time.Time) {
+	t.Status = status
+	switch status {
+	case Current:
+		if t.StartedAt == nil {
+			t.StartedAt = &now
+		}
+		t.CompletedAt = nil
+	case Completed:
+		if t.StartedAt == nil {
+			t.StartedAt = &now
+		}
+		t.CompletedAt = &now
+	default:
+		t.CompletedAt = nil
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrTimerRunning       = errors.New("user already has a running timer")
+	ErrNoRunningTimer     = errors.New("no running timer")
+	ErrInvalidTimeEntry   = errors.New("invalid time entry")
+	ErrTimeEntryNotFound  = errors.New("time entry not found")
+	ErrTimeEntryForbidden = errors.New("time entry belongs to another user")
+)
+
+// TimeEntry — отрезок работы над задачей. У запущенного таймера EndedAt пуст,
+// а Seconds считается на момент запроса.
+type TimeEntry struct {
+	ID        string
+	TaskID    string
+	TaskTitle string
+	UserID    string
+	UserName  string
+	StartedAt time.Time
+	EndedAt   *time.Time
+	Seconds   int
+	Note      string
+	Running   bool
+}
+
+// UserTime — время одного пользователя по задаче.
+type UserTime struct {
+	UserID   string
+	UserName string
+	Seconds  int
+}
+
+type TaskTimeTotal struct {
+	TaskID  string
+	Seconds int
+	Users   []*UserTime
+}
+
+// TimesheetRow — время по задаче за каждый день недели, с понедельника.
+type TimesheetRow struct {
+	TaskID    string
+	TaskTitle string
+	Days      [7]int
+	Seconds   int
+}
+
+type Timesheet struct {
+	UserID    string
+	WeekStart time.Time
+	Rows      []*TimesheetRow
+	Days      [7]int
+	Seconds   int
+	Entries   []*TimeEntry
+}
+
+type TimeEntryInteractor interface {
+	StartTimer(ctx context.Context, taskID string, userID string, note string) (*TimeEntry, error)
+	StopTimer(ctx context.Context, userID string) (*TimeEntry, error)
+	RunningTimer(ctx context.Context, userID string) (*TimeEntry, error)
+	AddEntry(ctx context.Context, taskID string, userID string, startedAt time.Time, seconds int, note string) (*TimeEntry, error)
+	// DeleteEntry удаляет запись пользователя и возвращает её.
+	DeleteEntry(ctx context.Context, userID string, id string) (*TimeEntry, error)
+	TaskEntries(ctx context.Context, taskID string, userID string) ([]*TimeEntry, *TaskTimeTotal, error)
+	// Timesheet возвращает неделю, содержащую day, в часовом поясе табеля.
+	Timesheet(ctx context.Context, userID string, day time.Time) (*Timesheet, error)
+	TimesheetCSV(ctx context.Context, userID string, day time.Time) ([]byte, error)
+}
+
+type TimeEntryRepository interface {
+	Task(ctx context.Context, id string) (*Task, error)
+	// StartTimer создаёт запущенную запись, если у пользователя нет другой; false — уже есть.
+	StartTimer(ctx context.Context, entry *TimeEntry) (bool, error)
+	// StopTimer останавливает запущенную запись пользователя; nil — таймер не запущен.
+	StopTimer(ctx context.Context, userID string, at time.Time) (*TimeEntry, error)
+	RunningTimer(ctx context.Context, userID string) (*TimeEntry, error)
+	CreateTimeEntry(ctx context.Context, entry *TimeEntry) (*TimeEntry, error)
+	TimeEntry(ctx context.Context, id string) (*TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, id string) error
+	TaskTimeEntries(ctx context.Context, taskID string) ([]*TimeEntry, error)
+	UserTimeEntries(ctx context.Context, userID string, from time.Time, to time.Time) ([]*TimeEntry, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+}
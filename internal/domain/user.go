@@ -19,15 +19,18 @@ type User struct {
 	PassHash  []byte
 	CreatedAt time.Time
 	IsAdmin   Role
+	// ReminderOffsets — за сколько часов до срока задачи напоминать. Пустой — настройки по умолчанию.
+	ReminderOffsets []int
 }
 
 type UserInteractor interface {
-	CreateUser(ctx context.Context, login string, name string, pass string) error
+	CreateUser(ctx context.Context, login string, name string, pass string) (string, error)
 	User(ctx context.Context, id string) (*User, error)
 	Login(ctx context.Context, login string, passhash string) (string, error)
 	Users(ctx context.Context, page int, limit int) ([]*User, error)
 	UpdateUser(ctx context.Context, id string, name string, login string, passhash string, role Role) error
 	DeleteUser(ctx context.Context, id string) error
+	SetReminderOffsets(ctx context.Context, id string, offsets []int) error
 }
 
 type UserRepository interface {
@@ -37,5 +40,6 @@ type UserRepository interface {
 	Users(ctx context.Context, page int, limit int) ([]*User, error)
 	UpdateUser(ctx context.Context, user *User) error
 	DeleteUser(ctx context.Context, id string) error
+	SetReminderOffsets(ctx context.Context, id string, offsets []int) error
 	// UpdateUserPassword(ctx context.Context, passHash []byte) error
 }
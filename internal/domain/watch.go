@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidComment = errors.New("invalid comment")
+
+// MaxCommentLength — предельная длина комментария в символах.
+const MaxCommentLength = 5000
+
+// TaskWatcher — пользователь, подписанный на изменения задачи.
+type TaskWatcher struct {
+	TaskID    string
+	UserID    string
+	UserName  string
+	CreatedAt time.Time
+}
+
+type TaskComment struct {
+	ID         string
+	TaskID     string
+	AuthorID   string
+	AuthorName string
+	Body       string
+	CreatedAt  time.Time
+}
+
+// TaskEvent — изменение задачи, о котором сообщается её наблюдателям.
+// Автор изменения уведомление не получает.
+type TaskEvent struct {
+	TaskID  string
+	ActorID string
+	Kind    NotificationKind
+	Message string
+}
+
+type WatchInteractor interface {
+	Watch(ctx context.Context, taskID string, userID string) error
+	Unwatch(ctx context.Context, taskID string, userID string) error
+	Watchers(ctx context.Context, taskID string) ([]*TaskWatcher, error)
+	// AddComment добавляет комментарий, подписывает автора и уведомляет наблюдателей.
+	AddComment(ctx context.Context, taskID string, authorID string, body string) (*TaskComment, error)
+	Comments(ctx context.Context, taskID string) ([]*TaskComment, error)
+	// Subscribe подписывает пользователей без проверок — для создателя и исполнителя задачи.
+	Subscribe(ctx context.Context, taskID string, userIDs ...string) error
+	// Publish рассылает событие наблюдателям. Сбои доставки только логируются.
+	Publish(ctx context.Context, event *TaskEvent)
+}
+
+type WatchRepository interface {
+	Task(ctx context.Context, id string) (*Task, error)
+	ProjectMember(ctx context.Context, projectID string, userID string) (*ProjectMembership, error)
+	// AddWatchers подписывает пользователей; существующие подписки не меняются.
+	AddWatchers(ctx context.Context, taskID string, userIDs []string) error
+	RemoveWatcher(ctx context.Context, taskID string, userID string) error
+	Watchers(ctx context.Context, taskID string) ([]*TaskWatcher, error)
+	CreateComment(ctx context.Context, comment *TaskComment) (*TaskComment, error)
+	Comments(ctx context.Context, taskID string) ([]*TaskComment, error)
+}
@@ -0,0 +1,100 @@
+package lib
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICalProperty — строка свойства iCalendar (RFC 5545). Value записывается как есть,
+// текстовые значения нужно экранировать через ICalText.
+type ICalProperty struct {
+	Name  string
+	Value string
+}
+
+// ICalComponent — компонент календаря: VTODO, VEVENT и т. п.
+type ICalComponent struct {
+	Name       string
+	Properties []ICalProperty
+}
+
+type ICalendar struct {
+	ProdID     string
+	Name       string
+	Components []ICalComponent
+}
+
+// ICalText экранирует текстовое значение: обратный слеш, ';', ',' и переводы строк.
+func ICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// ICalTime форматирует момент времени в UTC: 20261019T093000Z.
+func ICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// WriteTo записывает календарь с переводами строк CRLF и переносом длинных строк.
+func (c *ICalendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	writeICalLine(bw, "BEGIN", "VCALENDAR")
+	writeICalLine(bw, "VERSION", "2.0")
+	writeICalLine(bw, "PRODID", c.ProdID)
+	writeICalLine(bw, "CALSCALE", "GREGORIAN")
+	writeICalLine(bw, "METHOD", "PUBLISH")
+	if c.Name != "" {
+		writeICalLine(bw, "X-WR-CALNAME", ICalText(c.Name))
+	}
+	for _, component := range c.Components {
+		writeICalLine(bw, "BEGIN", component.Name)
+		for _, property := range component.Properties {
+			writeICalLine(bw, property.Name, property.Value)
+		}
+		writeICalLine(bw, "END", component.Name)
+	}
+	writeICalLine(bw, "END", "VCALENDAR")
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// writeICalLine переносит строку длиннее 75 октетов: продолжение начинается
+// с пробела, многобайтные символы UTF-8 не разрываются.
+func writeICalLine(w *bufio.Writer, name string, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Пробел в начале строки продолжения тоже занимает октет.
+		limit = 74
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,57 @@
+package lib
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var languageRegex = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// NormalizeLanguage приводит языковой тег к нижнему регистру ("en-US" -> "en-us").
+// Возвращает пустую строку, если тег некорректен.
+func NormalizeLanguage(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	if !languageRegex.MatchString(tag) {
+		return ""
+	}
+	return tag
+}
+
+// ParseAcceptLanguage разбирает заголовок Accept-Language и возвращает
+// языки в порядке убывания приоритета (q).
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := NormalizeLanguage(fields[0])
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		result = append(result, t.tag)
+	}
+	return result
+}
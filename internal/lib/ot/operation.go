@@ -0,0 +1,295 @@
+package ot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+var (
+	ErrBaseLength = errors.New("operation base length does not match document length")
+	ErrMismatch   = errors.New("operations were not applied to the same document")
+)
+
+// Component — один шаг операции: пропуск (Retain), вставка (Insert) или удаление (Delete).
+// Длины считаются в рунах.
+type Component struct {
+	Retain int
+	Insert string
+	Delete int
+}
+
+// Operation — операция над текстом в формате ot.js:
+// положительное число — retain, отрицательное — delete, строка — insert.
+type Operation struct {
+	Ops       []Component
+	BaseLen   int
+	TargetLen int
+}
+
+func (o *Operation) Retain(n int) *Operation {
+	if n <= 0 {
+		return o
+	}
+	o.BaseLen += n
+	o.TargetLen += n
+	if last := len(o.Ops) - 1; last >= 0 && o.Ops[last].Retain > 0 {
+		o.Ops[last].Retain += n
+		return o
+	}
+	o.Ops = append(o.Ops, Component{Retain: n})
+	return o
+}
+
+func (o *Operation) Insert(s string) *Operation {
+	if s == "" {
+		return o
+	}
+	o.TargetLen += utf8.RuneCountInString(s)
+	last := len(o.Ops) - 1
+	switch {
+	case last >= 0 && o.Ops[last].Insert != "":
+		o.Ops[last].Insert += s
+	case last >= 0 && o.Ops[last].Delete > 0:
+		// Вставка всегда идёт перед удалением — так у операций одна каноническая форма.
+		if last > 0 && o.Ops[last-1].Insert != "" {
+			o.Ops[last-1].Insert += s
+			return o
+		}
+		o.Ops = append(o.Ops, o.Ops[last])
+		o.Ops[last] = Component{Insert: s}
+	default:
+		o.Ops = append(o.Ops, Component{Insert: s})
+	}
+	return o
+}
+
+func (o *Operation) Delete(n int) *Operation {
+	if n <= 0 {
+		return o
+	}
+	o.BaseLen += n
+	if last := len(o.Ops) - 1; last >= 0 && o.Ops[last].Delete > 0 {
+		o.Ops[last].Delete += n
+		return o
+	}
+	o.Ops = append(o.Ops, Component{Delete: n})
+	return o
+}
+
+// IsNoop сообщает, что операция не меняет документ.
+func (o Operation) IsNoop() bool {
+	return len(o.Ops) == 0 || (len(o.Ops) == 1 && o.Ops[0].Retain > 0)
+}
+
+// Apply применяет операцию к документу.
+func (o Operation) Apply(doc string) (string, error) {
+	runes := []rune(doc)
+	if len(runes) != o.BaseLen {
+		return "", ErrBaseLength
+	}
+	result := make([]rune, 0, o.TargetLen)
+	pos := 0
+	for _, c := range o.Ops {
+		switch {
+		case c.Retain > 0:
+			result = append(result, runes[pos:pos+c.Retain]...)
+			pos += c.Retain
+		case c.Insert != "":
+			result = append(result, []rune(c.Insert)...)
+		default:
+			pos += c.Delete
+		}
+	}
+	return string(result), nil
+}
+
+// Transform принимает две параллельные операции a и b над одним документом
+// и возвращает a' и b' такие, что apply(apply(doc, a), b') == apply(apply(doc, b), a').
+// При одновременной вставке в одну позицию первой идёт вставка из a.
+func Transform(a, b Operation) (Operation, Operation, error) {
+	if a.BaseLen != b.BaseLen {
+		return Operation{}, Operation{}, ErrMismatch
+	}
+	var a1, b1 Operation
+	ops1, ops2 := a.Ops, b.Ops
+	i1, i2 := 0, 0
+	op1, op2 := next(ops1, &i1), next(ops2, &i2)
+	for op1 != nil || op2 != nil {
+		if op1 != nil && op1.Insert != "" {
+			a1.Insert(op1.Insert)
+			b1.Retain(utf8.RuneCountInString(op1.Insert))
+			op1 = next(ops1, &i1)
+			continue
+		}
+		if op2 != nil && op2.Insert != "" {
+			a1.Retain(utf8.RuneCountInString(op2.Insert))
+			b1.Insert(op2.Insert)
+			op2 = next(ops2, &i2)
+			continue
+		}
+		if op1 == nil || op2 == nil {
+			return Operation{}, Operation{}, ErrMismatch
+		}
+
+		len1, len2 := op1.Retain+op1.Delete, op2.Retain+op2.Delete
+		n := min(len1, len2)
+		switch {
+		case op1.Retain > 0 && op2.Retain > 0:
+			a1.Retain(n)
+			b1.Retain(n)
+		case op1.Delete > 0 && op2.Delete > 0:
+			// Оба удалили один и тот же фрагмент — ничего делать не нужно.
+		case op1.Delete > 0:
+			a1.Delete(n)
+		default:
+			b1.Delete(n)
+		}
+		op1 = shrink(op1, n, ops1, &i1)
+		op2 = shrink(op2, n, ops2, &i2)
+	}
+	return a1, b1, nil
+}
+
+// Compose склеивает последовательные операции: a применяется к документу,
+// b — к результату a. apply(doc, Compose(a, b)) == apply(apply(doc, a), b).
+func Compose(a, b Operation) (Operation, error) {
+	if a.TargetLen != b.BaseLen {
+		return Operation{}, ErrMismatch
+	}
+	var result Operation
+	ops1, ops2 := a.Ops, b.Ops
+	i1, i2 := 0, 0
+	op1, op2 := next(ops1, &i1), next(ops2, &i2)
+	for op1 != nil || op2 != nil {
+		if op1 != nil && op1.Delete > 0 {
+			result.Delete(op1.Delete)
+			op1 = next(ops1, &i1)
+			continue
+		}
+		if op2 != nil && op2.Insert != "" {
+			result.Insert(op2.Insert)
+			op2 = next(ops2, &i2)
+			continue
+		}
+		if op1 == nil || op2 == nil {
+			return Operation{}, ErrMismatch
+		}
+
+		len1 := op1.Retain
+		if op1.Insert != "" {
+			len1 = utf8.RuneCountInString(op1.Insert)
+		}
+		n := min(len1, op2.Retain+op2.Delete)
+		switch {
+		case op1.Insert != "" && op2.Delete > 0:
+			// Вставленное в a и удалённое в b в итог не попадает.
+		case op1.Insert != "":
+			result.Insert(string([]rune(op1.Insert)[:n]))
+		case op2.Retain > 0:
+			result.Retain(n)
+		default:
+			result.Delete(n)
+		}
+		op1 = shrink(op1, n, ops1, &i1)
+		op2 = shrink(op2, n, ops2, &i2)
+	}
+	return result, nil
+}
+
+func next(ops []Component, i *int) *Component {
+	if *i >= len(ops) {
+		return nil
+	}
+	c := ops[*i]
+	*i++
+	return &c
+}
+
+// shrink отрезает от компонента первые n символов и, если он кончился,
+// возвращает следующий.
+func shrink(c *Component, n int, ops []Component, i *int) *Component {
+	switch {
+	case c.Retain > 0:
+		c.Retain -= n
+		if c.Retain > 0 {
+			return c
+		}
+	case c.Insert != "":
+		if rest := []rune(c.Insert)[n:]; len(rest) > 0 {
+			c.Insert = string(rest)
+			return c
+		}
+	default:
+		c.Delete -= n
+		if c.Delete > 0 {
+			return c
+		}
+	}
+	return next(ops, i)
+}
+
+// TransformIndex сдвигает позицию курсора с учётом операции.
+func (o Operation) TransformIndex(index int) int {
+	newIndex := index
+	for _, c := range o.Ops {
+		switch {
+		case c.Retain > 0:
+			index -= c.Retain
+		case c.Insert != "":
+			newIndex += utf8.RuneCountInString(c.Insert)
+		default:
+			newIndex -= min(index, c.Delete)
+			index -= c.Delete
+		}
+		if index < 0 {
+			break
+		}
+	}
+	return newIndex
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	ops := make([]any, 0, len(o.Ops))
+	for _, c := range o.Ops {
+		switch {
+		case c.Retain > 0:
+			ops = append(ops, c.Retain)
+		case c.Insert != "":
+			ops = append(ops, c.Insert)
+		default:
+			ops = append(ops, -c.Delete)
+		}
+	}
+	return json.Marshal(ops)
+}
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var op Operation
+	for _, item := range raw {
+		var n int
+		if err := json.Unmarshal(item, &n); err == nil {
+			if n == 0 {
+				return fmt.Errorf("invalid operation component: %s", item)
+			}
+			if n > 0 {
+				op.Retain(n)
+			} else {
+				op.Delete(-n)
+			}
+			continue
+		}
+		var s string
+		if err := json.Unmarshal(item, &s); err != nil || s == "" {
+			return fmt.Errorf("invalid operation component: %s", item)
+		}
+		op.Insert(s)
+	}
+	*o = op
+	return nil
+}
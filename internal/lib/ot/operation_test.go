@@ -0,0 +1,143 @@
+package ot
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// parse собирает операцию из записи ot.js: [retain, "insert", -delete].
+func parse(t *testing.T, raw string) Operation {
+	t.Helper()
+	var op Operation
+	if err := json.Unmarshal([]byte(raw), &op); err != nil {
+		t.Fatalf("parse %s: %v", raw, err)
+	}
+	return op
+}
+
+func apply(t *testing.T, op Operation, doc string) string {
+	t.Helper()
+	result, err := op.Apply(doc)
+	if err != nil {
+		t.Fatalf("apply %v to %q: %v", op.Ops, doc, err)
+	}
+	return result
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		op   string
+		want string
+	}{
+		{"insert", "hello", `[5, " world"]`, "hello world"},
+		{"delete", "hello world", `[5, -6]`, "hello"},
+		{"replace", "hello", `["J", -1, 4]`, "Jello"},
+		{"runes", "привет", `[2, "ы", -4]`, "пры"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apply(t, parse(t, tt.op), tt.doc); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := parse(t, `[3]`).Apply("hello"); !errors.Is(err, ErrBaseLength) {
+		t.Errorf("base length: got %v, want %v", err, ErrBaseLength)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		a    string
+		b    string
+		want string
+	}{
+		{"inserts at different positions", "abc", `["x", 3]`, `[3, "y"]`, "xabcy"},
+		{"inserts at one position, a first", "abc", `[1, "x", 2]`, `[1, "y", 2]`, "axybc"},
+		{"insert inside deleted range", "abcdef", `[2, "x", 4]`, `[1, -4, 1]`, "axf"},
+		{"overlapping deletes", "abcdef", `[1, -3, 2]`, `[2, -3, 1]`, "af"},
+		{"same delete", "abcdef", `[2, -2, 2]`, `[2, -2, 2]`, "abef"},
+		{"delete and retain", "hello world", `[5, -6]`, `["Oh, ", 11]`, "Oh, hello"},
+		{"runes", "привет", `[6, "!"]`, `[-1, "П", 5]`, "Привет!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := parse(t, tt.a), parse(t, tt.b)
+			a1, b1, err := Transform(a, b)
+			if err != nil {
+				t.Fatalf("transform: %v", err)
+			}
+			left := apply(t, b1, apply(t, a, tt.doc))
+			right := apply(t, a1, apply(t, b, tt.doc))
+			if left != right {
+				t.Fatalf("documents diverged: a then b' = %q, b then a' = %q", left, right)
+			}
+			if left != tt.want {
+				t.Errorf("got %q, want %q", left, tt.want)
+			}
+		})
+	}
+	if _, _, err := Transform(parse(t, `[3]`), parse(t, `[4]`)); !errors.Is(err, ErrMismatch) {
+		t.Errorf("mismatch: got %v, want %v", err, ErrMismatch)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		a    string
+		b    string
+		want string
+	}{
+		{"two inserts", "abc", `[3, "d"]`, `[4, "e"]`, "abcde"},
+		{"delete what was inserted", "abc", `[1, "xyz", 2]`, `[2, -1, 3]`, "axzbc"},
+		{"insert then delete original", "abc", `["x", 3]`, `[1, -2, 1]`, "xc"},
+		{"delete then insert", "abcdef", `[-3, 3]`, `["xy", 3]`, "xydef"},
+		{"runes", "мир", `["новый ", 3]`, `[6, -3, "дом"]`, "новый дом"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := parse(t, tt.a), parse(t, tt.b)
+			ab, err := Compose(a, b)
+			if err != nil {
+				t.Fatalf("compose: %v", err)
+			}
+			if got, seq := apply(t, ab, tt.doc), apply(t, b, apply(t, a, tt.doc)); got != seq {
+				t.Fatalf("composed %q, sequential %q", got, seq)
+			}
+			if got := apply(t, ab, tt.doc); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := Compose(parse(t, `[3, "x"]`), parse(t, `[3]`)); !errors.Is(err, ErrMismatch) {
+		t.Errorf("mismatch: got %v, want %v", err, ErrMismatch)
+	}
+}
+
+func TestTransformIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    string
+		index int
+		want  int
+	}{
+		{"insert before", `["xy", 5]`, 3, 5},
+		{"insert after", `[4, "xy", 1]`, 3, 3},
+		{"delete before", `[-2, 3]`, 3, 1},
+		{"delete around", `[1, -3, 1]`, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parse(t, tt.op).TransformIndex(tt.index); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
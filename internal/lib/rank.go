@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"errors"
+	"strings"
+)
+
+// Ранги — дробные индексы в системе счисления по основанию 36: строка "abc"
+// соответствует числу 0.abc. Между любыми двумя рангами всегда есть третий,
+// поэтому перестановка элемента меняет только его собственный ранг.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var ErrInvalidRank = errors.New("invalid rank interval")
+
+// RankBetween возвращает ранг строго между a и b. Пустой a — начало списка,
+// пустой b — конец.
+func RankBetween(a, b string) (string, error) {
+	if !validRank(a) || !validRank(b) || (b != "" && a >= b) {
+		return "", ErrInvalidRank
+	}
+	return rankMidpoint(a, b, b == ""), nil
+}
+
+// SpreadRanks возвращает n равномерно распределённых возрастающих рангов.
+func SpreadRanks(n int) []string {
+	width, capacity := 1, len(rankDigits)
+	for capacity <= n {
+		width++
+		capacity *= len(rankDigits)
+	}
+	ranks := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		value := i * capacity / (n + 1)
+		key := make([]byte, width)
+		for pos := width - 1; pos >= 0; pos-- {
+			key[pos] = rankDigits[value%len(rankDigits)]
+			value /= len(rankDigits)
+		}
+		ranks = append(ranks, strings.TrimRight(string(key), "0"))
+	}
+	return ranks
+}
+
+func rankMidpoint(a, b string, open bool) string {
+	if !open {
+		n := 0
+		for n < len(b) && rankDigit(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + rankMidpoint(a[min(n, len(a)):], b[n:], false)
+		}
+	}
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(rankDigits, a[0])
+	}
+	digitB := len(rankDigits)
+	if !open {
+		digitB = strings.IndexByte(rankDigits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(rankDigits[(digitA+digitB+1)/2])
+	}
+	if !open && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(rankDigits[digitA]) + rankMidpoint(rest, "", true)
+}
+
+func rankDigit(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return '0'
+}
+
+func validRank(s string) bool {
+	if strings.HasSuffix(s, "0") {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(rankDigits, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
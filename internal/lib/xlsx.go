@@ -0,0 +1,387 @@
+package lib
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Минимальная поддержка XLSX (Office Open XML) без внешних зависимостей:
+// запись одного листа построчно и чтение значений первого листа.
+
+var ErrInvalidXLSX = errors.New("invalid xlsx file")
+
+const xlsxMain = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+
+// xlsxMaxPartSize ограничивает распакованный размер одной части книги при чтении.
+const xlsxMaxPartSize = 32 << 20
+
+var xlsxStatic = []struct {
+	name string
+	body string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// XLSXWriter пишет книгу с одним листом в w по мере добавления строк.
+// Все значения записываются как текст.
+type XLSXWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, file := range xlsxStatic {
+		if err := writeZipFile(zw, file.name, file.body); err != nil {
+			return nil, err
+		}
+	}
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="` + xlsxMain + `" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writeZipFile(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+	// лист записывается последним, чтобы строки шли в архив потоком
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x := &XLSXWriter{zip: zw, sheet: bufio.NewWriter(sheet)}
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="` + xlsxMain + `"><sheetData>`)
+	return x, nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, body string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, body)
+	return err
+}
+
+func (x *XLSXWriter) WriteRow(values []string) error {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		fmt.Fprintf(x.sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, XLSXColumn(i), x.row)
+		if err := xml.EscapeText(x.sheet, []byte(value)); err != nil {
+			return err
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Flush отправляет накопленные строки в поток.
+func (x *XLSXWriter) Flush() error {
+	return x.sheet.Flush()
+}
+
+// Close дописывает лист и оглавление архива.
+func (x *XLSXWriter) Close() error {
+	if _, err := x.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// XLSXColumn переводит индекс столбца с нуля в буквенное обозначение: 0 → A, 26 → AA.
+func XLSXColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxColumnIndex разбирает ссылку на ячейку (B12) и возвращает индекс столбца с нуля.
+func xlsxColumnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+		letters++
+	}
+	return index - 1, letters > 0
+}
+
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+type xlsxSharedString struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// ReadXLSX возвращает значения ячеек первого листа по строкам; индекс строки
+// совпадает с её номером в листе минус один, пропущенные строки пусты.
+// Числа и даты отдаются в записанном виде (даты — порядковым номером дня, см. XLSXDate).
+// Строки после maxRows и столбцы после maxColumns считаются ошибкой: номера
+// из файла проверяются до того, как под них выделяется память.
+func ReadXLSX(r io.ReaderAt, size int64, maxRows int, maxColumns int) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+	sheetPath, err := xlsxFirstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := xlsxSharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+	sheet, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: sheet %s not found", ErrInvalidXLSX, sheetPath)
+	}
+	rc, err := xlsxOpen(sheet)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rows [][]string
+	decoder := xml.NewDecoder(rc)
+	var row []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = nil
+				number := len(rows) + 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local != "r" {
+						continue
+					}
+					if n, err := strconv.Atoi(attr.Value); err == nil && n > number {
+						number = n
+					}
+				}
+				if number > maxRows {
+					return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidXLSX, maxRows)
+				}
+				for len(rows) < number-1 {
+					rows = append(rows, nil)
+				}
+			case "c":
+				var cell xlsxCell
+				if err := decoder.DecodeElement(&cell, &t); err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrInvalidXLSX, err)
+				}
+				index, ok := xlsxColumnIndex(cell.Ref)
+				if !ok {
+					index = len(row)
+				}
+				if index >= maxColumns {
+					return nil, fmt.Errorf("%w: more than %d columns", ErrInvalidXLSX, maxColumns)
+				}
+				for len(row) <= index {
+					row = append(row, "")
+				}
+				row[index], err = cell.value(shared)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "row" {
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (c *xlsxCell) value(shared []string) (string, error) {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(c.Value)
+		if err != nil || i < 0 || i >= len(shared) {
+			return "", fmt.Errorf("%w: shared string %q", ErrInvalidXLSX, c.Value)
+		}
+		return shared[i], nil
+	case "inlineStr":
+		text := c.Inline.Text
+		for _, run := range c.Inline.Runs {
+			text += run.Text
+		}
+		return text, nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	}
+	return c.Value, nil
+}
+
+// xlsxFirstSheet находит путь к первому листу по workbook.xml и его связям.
+func xlsxFirstSheet(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xlsxDecode(files["xl/workbook.xml"], &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("%w: workbook has no sheets", ErrInvalidXLSX)
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xlsxDecode(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("%w: first sheet relationship not found", ErrInvalidXLSX)
+}
+
+func xlsxSharedStrings(file *zip.File) ([]string, error) {
+	if file == nil {
+		return nil, nil
+	}
+	var sst struct {
+		Items []xlsxSharedString `xml:"si"`
+	}
+	if err := xlsxDecode(file, &sst); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		strs[i] = item.Text
+		for _, run := range item.Runs {
+			strs[i] += run.Text
+		}
+	}
+	return strs, nil
+}
+
+func xlsxDecode(file *zip.File, v any) error {
+	if file == nil {
+		return fmt.Errorf("%w: missing part", ErrInvalidXLSX)
+	}
+	rc, err := xlsxOpen(file)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidXLSX, file.Name, err)
+	}
+	return nil
+}
+
+// xlsxOpen открывает часть книги не больше xlsxMaxPartSize. Размер из
+// заголовка архива проверяется сразу, а фактический — по мере чтения.
+func xlsxOpen(file *zip.File) (io.ReadCloser, error) {
+	if file.UncompressedSize64 > xlsxMaxPartSize {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidXLSX, file.Name, xlsxMaxPartSize)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxPart{ReadCloser: rc, name: file.Name, left: xlsxMaxPartSize}, nil
+}
+
+type xlsxPart struct {
+	io.ReadCloser
+	name string
+	left int64
+}
+
+func (p *xlsxPart) Read(b []byte) (int, error) {
+	if p.left <= 0 {
+		return 0, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidXLSX, p.name, xlsxMaxPartSize)
+	}
+	if int64(len(b)) > p.left {
+		b = b[:p.left]
+	}
+	n, err := p.ReadCloser.Read(b)
+	p.left -= int64(n)
+	return n, err
+}
+
+// XLSXDate переводит порядковый номер дня Excel (система 1900) во время UTC.
+func XLSXDate(serial float64) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return epoch.Add(time.Duration(serial * float64(24*time.Hour))).Round(time.Second)
+}
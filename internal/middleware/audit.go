@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern — какие id запроса от клиента или прокси принимаются как есть.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID берёт id запроса из заголовка X-Request-ID или выдаёт новый,
+// возвращает его в ответе и кладёт в контекст как requestID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			b := make([]byte, 16)
+			rand.Read(b)
+			requestID = hex.EncodeToString(b)
+		}
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// Audit записывает в журнал успешные изменяющие запросы (POST, PUT, PATCH, DELETE).
+// Обработчик может описать изменения, положив в контекст под ключом audit
+// []*domain.AuditEntry; иначе пишется одна запись с сущностью entity, id из
+// параметра :id и действием по методу запроса. Незаданные в записях поля
+// заполняются так же, автор и метаданные запроса — всегда.
+// Подключается после AuthMiddleware, чтобы знать автора.
+func Audit(audit domain.AuditInteractor, entity domain.AuditEntity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		action, ok := auditActions[c.Request.Method]
+		if !ok || c.Writer.Status() >= http.StatusBadRequest || c.IsAborted() {
+			return
+		}
+		// пустой список — обработчик отметил, что ничего не изменилось
+		value, described := c.Get("audit")
+		entries, _ := value.([]*domain.AuditEntry)
+		if !described {
+			entries = []*domain.AuditEntry{{}}
+		}
+		// POST к существующей сущности (/task/:id/comments) изменяет её, а не создаёт
+		if action == domain.AuditCreate && c.Param("id") != "" {
+			action = domain.AuditUpdate
+		}
+		actorID, _ := c.Keys["userID"].(string)
+		requestID, _ := c.Keys["requestID"].(string)
+		for _, entry := range entries {
+			if entry.EntityType == "" {
+				entry.EntityType = entity
+			}
+			if entry.EntityID == "" {
+				entry.EntityID = c.Param("id")
+			}
+			if entry.Action == "" {
+				entry.Action = action
+			}
+			if entry.ActorID == "" {
+				entry.ActorID = actorID
+			}
+			entry.Route = c.Request.Method + " " + c.FullPath()
+			entry.RequestID = requestID
+			entry.IP = c.ClientIP()
+			entry.UserAgent = c.Request.UserAgent()
+			audit.Record(c.Request.Context(), entry)
+		}
+	}
+}
+
+var auditActions = map[string]domain.AuditAction{
+	http.MethodPost:   domain.AuditCreate,
+	http.MethodPut:    domain.AuditUpdate,
+	http.MethodPatch:  domain.AuditUpdate,
+	http.MethodDelete: domain.AuditDelete,
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -9,9 +10,19 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// queryTokenPath — единственный маршрут, где токен принимается параметром
+// token: браузерный WebSocket не умеет передавать заголовки. На остальных
+// маршрутах токен из URL попадал бы в журналы доступа и Referer.
+const queryTokenPath = "/api/v1/article/:id/ws"
+
 func AuthMiddleware(appSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && c.Request.Method == http.MethodGet && c.FullPath() == queryTokenPath {
+			if token := c.Query("token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(401, gin.H{"error": "Authorization header required"})
 			return
@@ -4,19 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
 	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
 )
 
 type ArticleInteractor struct {
-	articleRepo domain.ArticleRepository
+	articleRepo     domain.ArticleRepository
+	defaultLanguage string
 }
 
-func NewArticleInteractor(articleRepo domain.ArticleRepository) domain.ArticleInteractor {
-	return &ArticleInteractor{articleRepo: articleRepo}
+func NewArticleInteractor(articleRepo domain.ArticleRepository, defaultLanguage string) domain.ArticleInteractor {
+	return &ArticleInteractor{articleRepo: articleRepo, defaultLanguage: lib.NormalizeLanguage(defaultLanguage)}
 }
 
+// Article возвращает статью; у перевода отмечается, устарел ли он относительно исходной.
 func (ai *ArticleInteractor) Article(ctx context.Context, id string) (*domain.Article, error) {
 	const op = "uc.article.get"
 
@@ -28,8 +33,33 @@ func (ai *ArticleInteractor) Article(ctx context.Context, id string) (*domain.Ar
 		return nil, fmt.Errorf("%s: %w", op, err)
 
 	}
+	if article.SourceID != "" {
+		source, err := ai.articleRepo.Article(ctx, article.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: source: %w", op, err)
+		}
+		article.Outdated = article.SourceRevision < source.Revision
+	}
 	return article, nil
 }
+
+// ArticleVariant возвращает языковой вариант логической статьи.
+// Языки перебираются в порядке предпочтения, затем язык по умолчанию;
+// если подходящего перевода нет, возвращается исходная статья.
+func (ai *ArticleInteractor) ArticleVariant(ctx context.Context, id string, languages []string) (*domain.Article, error) {
+	const op = "uc.article.variant"
+	variants, err := ai.variants(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, language := range append(slices.Clone(languages), ai.defaultLanguage) {
+		if variant := matchLanguage(variants, language); variant != nil {
+			return variant, nil
+		}
+	}
+	return variants[0], nil
+}
+
 func (ai *ArticleInteractor) Articles(ctx context.Context, page, limit int) ([]*domain.Article, error) {
 	const op = "uc.article.get.all"
 	articles, err := ai.articleRepo.Articles(ctx, page, limit)
@@ -39,14 +69,31 @@ func (ai *ArticleInteractor) Articles(ctx context.Context, page, limit int) ([]*
 	return articles, nil
 }
 
-func (ai *ArticleInteractor) CreateArticle(ctx context.Context, title string, image string, content string, creatorName string) (*domain.Article, error) {
+func (ai *ArticleInteractor) Translations(ctx context.Context, id string) ([]*domain.Article, error) {
+	const op = "uc.article.translations"
+	variants, err := ai.variants(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return variants, nil
+}
+
+func (ai *ArticleInteractor) CreateArticle(ctx context.Context, title string, image string, content string, creatorName string, language string) (*domain.Article, error) {
 	const op = "uc.article.create"
+	if language == "" {
+		language = ai.defaultLanguage
+	}
+	language = lib.NormalizeLanguage(language)
+	if language == "" {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidLanguage)
+	}
 	article := domain.Article{
 		Title:      title,
 		Image:      image,
 		Content:    content,
 		Creator:    creatorName,
 		LastEditor: creatorName,
+		Language:   language,
 	}
 	articleID, err := ai.articleRepo.CreateArticle(ctx, &article)
 	if err != nil {
@@ -60,8 +107,49 @@ func (ai *ArticleInteractor) CreateArticle(ctx context.Context, title string, im
 	return articleDB, nil
 }
 
+func (ai *ArticleInteractor) CreateTranslation(ctx context.Context, sourceID string, language string, title string, image string, content string, creatorName string) (*domain.Article, error) {
+	const op = "uc.article.translation.create"
+	language = lib.NormalizeLanguage(language)
+	if language == "" {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidLanguage)
+	}
+	variants, err := ai.variants(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, variant := range variants {
+		if variant.Language == language {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrTranslationExists)
+		}
+	}
+	source := variants[0]
+	article := domain.Article{
+		Title:          title,
+		Image:          image,
+		Content:        content,
+		Creator:        creatorName,
+		LastEditor:     creatorName,
+		Language:       language,
+		SourceID:       source.ID,
+		SourceRevision: source.Revision,
+	}
+	articleID, err := ai.articleRepo.CreateArticle(ctx, &article)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	articleDB, err := ai.articleRepo.Article(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return articleDB, nil
+}
+
 func (ai *ArticleInteractor) UpdateArticle(ctx context.Context, id string, title string, image string, content string, editorName string) (*domain.Article, error) {
 	const op = "uc.article.update"
+	current, err := ai.articleRepo.Article(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 	article := domain.Article{
 		ID:         id,
 		Title:      title,
@@ -69,6 +157,14 @@ func (ai *ArticleInteractor) UpdateArticle(ctx context.Context, id string, title
 		Content:    content,
 		LastEditor: editorName,
 	}
+	// Правка перевода синхронизирует его с текущей ревизией исходной статьи.
+	if current.SourceID != "" {
+		source, err := ai.articleRepo.Article(ctx, current.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		article.SourceRevision = source.Revision
+	}
 	result, err := ai.articleRepo.UpdateArticle(ctx, &article)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -91,3 +187,45 @@ func (ai *ArticleInteractor) DeteleArticle(ctx context.Context, id string, userI
 	}
 	return nil
 }
+
+// variants возвращает исходную статью (первой) и все её переводы
+// с отметкой об устаревании.
+func (ai *ArticleInteractor) variants(ctx context.Context, id string) ([]*domain.Article, error) {
+	source, err := ai.articleRepo.Article(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source.SourceID != "" {
+		source, err = ai.articleRepo.Article(ctx, source.SourceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	translations, err := ai.articleRepo.Translations(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	variants := append([]*domain.Article{source}, translations...)
+	for _, variant := range translations {
+		variant.Outdated = variant.SourceRevision < source.Revision
+	}
+	return variants, nil
+}
+
+func matchLanguage(variants []*domain.Article, language string) *domain.Article {
+	if language == "" {
+		return nil
+	}
+	for _, variant := range variants {
+		if variant.Language == language {
+			return variant
+		}
+	}
+	primary, _, _ := strings.Cut(language, "-")
+	for _, variant := range variants {
+		if variantPrimary, _, _ := strings.Cut(variant.Language, "-"); variantPrimary == primary {
+			return variant
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package articlelink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type ArticleLinkInteractor struct {
+	linkRepo domain.ArticleLinkRepository
+}
+
+func NewArticleLinkInteractor(linkRepo domain.ArticleLinkRepository) domain.ArticleLinkInteractor {
+	return &ArticleLinkInteractor{linkRepo: linkRepo}
+}
+
+func (li *ArticleLinkInteractor) LinkArticle(ctx context.Context, taskID string, articleID string, kind domain.ArticleLinkKind, actorID string) (*domain.TaskArticleLink, error) {
+	const op = "uc.article_link.link"
+	kind, err := domain.ParseArticleLinkKind(string(kind))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	task, err := li.checkTask(ctx, taskID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	articles, err := li.linkRepo.ArticlesByIDs(ctx, []string{articleID})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(articles) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrArticleNotFound)
+	}
+	article := articles[0]
+	link := domain.TaskArticleLink{
+		TaskID:          taskID,
+		TaskTitle:       task.Title,
+		ArticleID:       articleID,
+		ArticleTitle:    article.Title,
+		Kind:            kind,
+		LinkedRevision:  article.Revision,
+		ArticleRevision: article.Revision,
+		CreatedByID:     actorID,
+	}
+	if err := li.linkRepo.SetArticleLink(ctx, &link); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &link, nil
+}
+
+func (li *ArticleLinkInteractor) UnlinkArticle(ctx context.Context, taskID string, articleID string, actorID string) error {
+	const op = "uc.article_link.unlink"
+	if _, err := li.checkTask(ctx, taskID, actorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ok, err := li.linkRepo.DeleteArticleLink(ctx, taskID, articleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", op, domain.ErrArticleLinkNotFound)
+	}
+	return nil
+}
+
+// TaskArticles возвращает статьи задачи с предупреждениями об изменённых и удалённых статьях.
+func (li *ArticleLinkInteractor) TaskArticles(ctx context.Context, taskID string) ([]*domain.TaskArticleLink, error) {
+	const op = "uc.article_link.task"
+	links, err := li.linkRepo.TaskArticleLinks(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, link := range links {
+		link.Check()
+	}
+	return links, nil
+}
+
+func (li *ArticleLinkInteractor) ArticleTasks(ctx context.Context, articleID string) ([]*domain.TaskArticleLink, error) {
+	const op = "uc.article_link.article"
+	links, err := li.linkRepo.ArticleTaskLinks(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, link := range links {
+		link.Check()
+	}
+	return links, nil
+}
+
+// checkTask проверяет, что пользователь может менять связи задачи проекта.
+func (li *ArticleLinkInteractor) checkTask(ctx context.Context, taskID string, actorID string) (*domain.Task, error) {
+	task, err := li.linkRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID == "" {
+		return task, nil
+	}
+	member, err := li.linkRepo.ProjectMember(ctx, task.ProjectID, actorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrNotProjectMember
+		}
+		return nil, err
+	}
+	if !member.Role.CanEdit() {
+		return nil, domain.ErrProjectRole
+	}
+	return task, nil
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type AuditInteractor struct {
+	auditRepo domain.AuditRepository
+	log       *slog.Logger
+}
+
+func NewAuditInteractor(auditRepo domain.AuditRepository, log *slog.Logger) domain.AuditInteractor {
+	return &AuditInteractor{auditRepo: auditRepo, log: log}
+}
+
+func (ai *AuditInteractor) Record(ctx context.Context, entry *domain.AuditEntry) {
+	entry.Before, entry.After = domain.AuditDiff(entry.Before, entry.After)
+	// изменение, после которого снимки совпали, не записывается
+	if entry.Before != nil && entry.After != nil && len(entry.Before)+len(entry.After) == 0 {
+		return
+	}
+	// запрос мог уже завершиться, а изменение сохранено: запись не должна пропасть
+	if err := ai.auditRepo.CreateAuditEntry(context.WithoutCancel(ctx), entry); err != nil {
+		ai.log.Error("failed to record audit entry",
+			slog.String("entity", string(entry.EntityType)),
+			slog.String("id", entry.EntityID),
+			slog.String("action", string(entry.Action)),
+			slog.String("request", entry.RequestID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (ai *AuditInteractor) Entries(ctx context.Context, actorID string, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	const op = "uc.audit.entries"
+	actor, err := ai.auditRepo.User(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if actor.IsAdmin != domain.AdminRole {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrAdminRequired)
+	}
+	if err := filter.Normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	entries, err := ai.auditRepo.AuditEntries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,211 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type BoardInteractor struct {
+	boardRepo   domain.BoardRepository
+	userRepo    domain.UserRepository
+	transitions *domain.TransitionGraph
+	wipLimits   map[domain.Status]int
+	columnLimit int
+	watchers    domain.WatchInteractor
+}
+
+func NewBoardInteractor(boardRepo domain.BoardRepository, userRepo domain.UserRepository, transitions *domain.TransitionGraph, wipLimits map[domain.Status]int, columnLimit int, watchers domain.WatchInteractor) domain.BoardInteractor {
+	return &BoardInteractor{
+		boardRepo:   boardRepo,
+		userRepo:    userRepo,
+		transitions: transitions,
+		wipLimits:   wipLimits,
+		columnLimit: columnLimit,
+		watchers:    watchers,
+	}
+}
+
+// Board возвращает задачи, сгруппированные по статусам, в ручном порядке.
+// Фильтр по статусам ограничивает набор колонок, остальные параметры применяются к каждой колонке.
+// Задачи проектов видны только их участникам.
+func (bi *BoardInteractor) Board(ctx context.Context, actorID string, filter domain.TaskFilter) ([]*domain.BoardColumn, error) {
+	const op = "uc.board.get"
+	filter.ViewerID = actorID
+	filter.SortBy = domain.SortRank
+	filter.Desc = false
+	filter.Page = 1
+	filter.Limit = bi.columnLimit
+	if err := filter.Normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	columns := make([]*domain.BoardColumn, 0, len(domain.BoardStatuses))
+	for _, status := range domain.BoardStatuses {
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, status) {
+			continue
+		}
+		columnFilter := filter
+		columnFilter.Statuses = []domain.Status{status}
+		tasks, total, err := bi.boardRepo.Tasks(ctx, columnFilter)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		columns = append(columns, &domain.BoardColumn{
+			Status:   status,
+			Count:    total,
+			WIPLimit: bi.wipLimits[status],
+			Tasks:    tasks,
+		})
+	}
+	return columns, nil
+}
+
+// MoveTask переносит задачу в колонку status между afterID (выше) и beforeID (ниже).
+// Смена статуса проверяется по графу переходов и WIP-лимиту колонки.
+func (bi *BoardInteractor) MoveTask(ctx context.Context, id string, status domain.Status, afterID string, beforeID string, actorID string) (*domain.Task, error) {
+	const op = "uc.board.move"
+	if !status.Valid() {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidStatus)
+	}
+	task, err := bi.boardRepo.Task(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := bi.checkEdit(ctx, task, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if task.Status != status {
+		actor, err := bi.userRepo.User(ctx, actorID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !bi.transitions.Allowed(task.Status, status, actor.IsAdmin) {
+			return nil, fmt.Errorf("%s: %s -> %s: %w", op, task.Status, status, domain.ErrTransitionNotAllowed)
+		}
+		if err := bi.transitions.CheckGuards(ctx, bi.boardRepo, id, status); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	rank, err := bi.rank(ctx, task, status, afterID, beforeID)
+	if errors.Is(err, lib.ErrInvalidRank) {
+		// Соседи без корректных рангов (например, старые задачи) — перенумеровываем колонку один раз.
+		if err = bi.rebalance(ctx, task.ProjectID, status); err == nil {
+			rank, err = bi.rank(ctx, task, status, afterID, beforeID)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	move := domain.TaskMove{
+		TaskID:    id,
+		ProjectID: task.ProjectID,
+		From:      task.Status,
+		To:        status,
+		Rank:      rank,
+		UserID:    actorID,
+		WIPLimit:  bi.wipLimits[status],
+	}
+	moved, err := bi.boardRepo.MoveTask(ctx, &move)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !moved {
+		current, err := bi.boardRepo.Task(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if current.Status != task.Status {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrTaskChanged)
+		}
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrWIPLimit)
+	}
+	if move.From != move.To {
+		bi.watchers.Publish(ctx, &domain.TaskEvent{
+			TaskID:  id,
+			ActorID: actorID,
+			Kind:    domain.NotifyStatusChanged,
+			Message: fmt.Sprintf("Статус задачи «%s» изменён: %s → %s.", task.Title, move.From, move.To),
+		})
+	}
+	task, err = bi.boardRepo.Task(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return task, nil
+}
+
+// checkEdit проверяет, что пользователь может менять задачу проекта.
+func (bi *BoardInteractor) checkEdit(ctx context.Context, task *domain.Task, actorID string) error {
+	if task.ProjectID == "" {
+		return nil
+	}
+	member, err := bi.boardRepo.ProjectMember(ctx, task.ProjectID, actorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
+
+func (bi *BoardInteractor) rank(ctx context.Context, task *domain.Task, status domain.Status, afterID string, beforeID string) (string, error) {
+	after, before := "", ""
+	if afterID != "" {
+		neighbor, err := bi.neighbor(ctx, task, afterID, status)
+		if err != nil {
+			return "", err
+		}
+		after = neighbor.Rank
+	}
+	if beforeID != "" {
+		neighbor, err := bi.neighbor(ctx, task, beforeID, status)
+		if err != nil {
+			return "", err
+		}
+		before = neighbor.Rank
+	}
+	// Пустой ранг у указанного соседа не задаёт позицию — колонку нужно перенумеровать.
+	if (afterID != "" && after == "") || (beforeID != "" && before == "") {
+		return "", lib.ErrInvalidRank
+	}
+	return lib.RankBetween(after, before)
+}
+
+// neighbor загружает соседа по колонке: он должен стоять в колонке status доски того же проекта.
+func (bi *BoardInteractor) neighbor(ctx context.Context, task *domain.Task, neighborID string, status domain.Status) (*domain.Task, error) {
+	if neighborID == task.ID {
+		return nil, domain.ErrInvalidNeighbor
+	}
+	neighbor, err := bi.boardRepo.Task(ctx, neighborID)
+	if err != nil {
+		return nil, err
+	}
+	if neighbor.Status != status || neighbor.ProjectID != task.ProjectID {
+		return nil, domain.ErrInvalidNeighbor
+	}
+	return neighbor, nil
+}
+
+func (bi *BoardInteractor) rebalance(ctx context.Context, projectID string, status domain.Status) error {
+	ids, err := bi.boardRepo.ColumnTaskIDs(ctx, projectID, status)
+	if err != nil {
+		return err
+	}
+	ranks := make(map[string]string, len(ids))
+	for i, rank := range lib.SpreadRanks(len(ids)) {
+		ranks[ids[i]] = rank
+	}
+	return bi.boardRepo.RankTasks(ctx, ranks)
+}
@@ -0,0 +1,161 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+const (
+	prodID = "-//TTK//Tasks//RU"
+	// eventDuration — длительность события в режиме VEVENT: у задачи есть только срок.
+	eventDuration = time.Hour
+)
+
+type CalendarInteractor struct {
+	calendarRepo    domain.CalendarRepository
+	completedWindow time.Duration
+}
+
+// NewCalendarInteractor: completedWindow — сколько завершённые задачи остаются в ленте.
+func NewCalendarInteractor(calendarRepo domain.CalendarRepository, completedWindow time.Duration) *CalendarInteractor {
+	return &CalendarInteractor{calendarRepo: calendarRepo, completedWindow: completedWindow}
+}
+
+func (ci *CalendarInteractor) Feed(ctx context.Context, token string, kind domain.CalendarKind) ([]byte, error) {
+	const op = "uc.calendar.feed"
+	if kind == "" {
+		kind = domain.CalendarTodo
+	}
+	if kind != domain.CalendarTodo && kind != domain.CalendarEvent {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidCalendarKind)
+	}
+	user, err := ci.calendarRepo.UserByFeedToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrFeedNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	now := time.Now()
+	tasks, err := ci.calendarRepo.CalendarTasks(ctx, user.ID, now.Add(-ci.completedWindow))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	calendar := lib.ICalendar{ProdID: prodID, Name: "Задачи: " + user.Name}
+	for _, task := range tasks {
+		if kind == domain.CalendarEvent {
+			calendar.Components = append(calendar.Components, taskEvent(task, now))
+		} else {
+			calendar.Components = append(calendar.Components, taskTodo(task, now))
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := calendar.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RotateToken генерирует новый токен. В базе хранится только его хеш,
+// поэтому токен показывается один раз.
+func (ci *CalendarInteractor) RotateToken(ctx context.Context, userID string) (string, error) {
+	const op = "uc.calendar.rotate"
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	token := hex.EncodeToString(raw)
+	if err := ci.calendarRepo.SetFeedToken(ctx, userID, hashToken(token)); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return token, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// taskProperties — общие свойства VTODO и VEVENT. UID не зависит от содержимого,
+// поэтому клиент заменяет прежнюю запись при изменении задачи.
+func taskProperties(task *domain.Task, now time.Time) []lib.ICalProperty {
+	properties := []lib.ICalProperty{
+		{Name: "UID", Value: task.ID + "@ttk"},
+		{Name: "DTSTAMP", Value: lib.ICalTime(now)},
+		{Name: "LAST-MODIFIED", Value: lib.ICalTime(task.UpdatedAt)},
+		{Name: "CREATED", Value: lib.ICalTime(task.CreatedAt)},
+		{Name: "SUMMARY", Value: lib.ICalText(task.Title)},
+		{Name: "PRIORITY", Value: icalPriority(task.Priority)},
+	}
+	if task.Content != "" {
+		properties = append(properties, lib.ICalProperty{Name: "DESCRIPTION", Value: lib.ICalText(task.Content)})
+	}
+	// метки задачи выгружаются категориями, ICalText экранирует запятые в именах
+	if len(task.Labels) > 0 {
+		names := make([]string, 0, len(task.Labels))
+		for _, label := range task.Labels {
+			names = append(names, lib.ICalText(label.Name))
+		}
+		properties = append(properties, lib.ICalProperty{Name: "CATEGORIES", Value: strings.Join(names, ",")})
+	}
+	return properties
+}
+
+func taskTodo(task *domain.Task, now time.Time) lib.ICalComponent {
+	properties := append(taskProperties(task, now),
+		lib.ICalProperty{Name: "DUE", Value: lib.ICalTime(task.PlannedAt)},
+	)
+	switch task.Status {
+	case domain.Completed:
+		properties = append(properties,
+			lib.ICalProperty{Name: "STATUS", Value: "COMPLETED"},
+			lib.ICalProperty{Name: "PERCENT-COMPLETE", Value: "100"},
+		)
+		if task.CompletedAt != nil {
+			properties = append(properties, lib.ICalProperty{Name: "COMPLETED", Value: lib.ICalTime(*task.CompletedAt)})
+		}
+	case domain.Current:
+		properties = append(properties, lib.ICalProperty{Name: "STATUS", Value: "IN-PROCESS"})
+	default:
+		properties = append(properties, lib.ICalProperty{Name: "STATUS", Value: "NEEDS-ACTION"})
+	}
+	if task.StartedAt != nil && task.StartedAt.Before(task.PlannedAt) {
+		properties = append(properties, lib.ICalProperty{Name: "DTSTART", Value: lib.ICalTime(*task.StartedAt)})
+	}
+	return lib.ICalComponent{Name: "VTODO", Properties: properties}
+}
+
+func taskEvent(task *domain.Task, now time.Time) lib.ICalComponent {
+	properties := append(taskProperties(task, now),
+		lib.ICalProperty{Name: "DTSTART", Value: lib.ICalTime(task.PlannedAt)},
+		lib.ICalProperty{Name: "DTEND", Value: lib.ICalTime(task.PlannedAt.Add(eventDuration))},
+		lib.ICalProperty{Name: "STATUS", Value: "CONFIRMED"},
+		lib.ICalProperty{Name: "TRANSP", Value: "TRANSPARENT"},
+		lib.ICalProperty{Name: "X-TTK-STATUS", Value: string(task.Status)},
+	)
+	return lib.ICalComponent{Name: "VEVENT", Properties: properties}
+}
+
+// icalPriority: 1 — наивысший приоритет, 9 — наименьший.
+func icalPriority(priority domain.Priority) string {
+	switch priority {
+	case domain.High:
+		return "1"
+	case domain.Low:
+		return "9"
+	default:
+		return "5"
+	}
+}
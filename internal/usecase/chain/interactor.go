@@ -0,0 +1,93 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+// batchSize — сколько записей читать из базы за раз при проверке.
+const batchSize = 500
+
+type ChainInteractor struct {
+	chainRepo domain.ChainRepository
+}
+
+func NewChainInteractor(chainRepo domain.ChainRepository) *ChainInteractor {
+	return &ChainInteractor{chainRepo: chainRepo}
+}
+
+func (ci *ChainInteractor) Verify(ctx context.Context, actorID string, chain domain.Chain) (*domain.ChainReport, error) {
+	const op = "uc.chain.verify"
+	actor, err := ci.chainRepo.User(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if actor.IsAdmin != domain.AdminRole {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrAdminRequired)
+	}
+	report, err := ci.Walk(ctx, chain)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return report, nil
+}
+
+// Walk проходит цепочку от первой записи и останавливается на первом звене,
+// которое не сходится: пропущен номер, не совпал хеш предыдущей записи или
+// хеш содержимого. Затем ищет записи без номера, добавленные после начала
+// цепочки. Проверок доступа нет — используется и из cmd/chainverify.
+func (ci *ChainInteractor) Walk(ctx context.Context, chain domain.Chain) (*domain.ChainReport, error) {
+	const op = "uc.chain.walk"
+	if !chain.Valid() {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrUnknownChain)
+	}
+	report := &domain.ChainReport{Chain: chain}
+	if err := ci.walkLinks(ctx, report); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	unchained, err := ci.chainRepo.UnchainedRecords(ctx, chain, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	report.Unchained = unchained
+	report.Valid = report.Break == nil && len(report.Unchained) == 0
+	return report, nil
+}
+
+// walkLinks проверяет звенья по порядку до первого расхождения.
+func (ci *ChainInteractor) walkLinks(ctx context.Context, report *domain.ChainReport) error {
+	for {
+		records, err := ci.chainRepo.ChainRecords(ctx, report.Chain, report.Head.Seq, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if reason := linkError(report.Head, record); reason != "" {
+				report.Break = &domain.ChainBreak{Seq: record.Seq, RecordID: record.ID, Reason: reason}
+				return nil
+			}
+			report.Head = record.ChainLink
+			report.Records++
+		}
+		if len(records) < batchSize {
+			return nil
+		}
+	}
+}
+
+// linkError проверяет запись после звена prev и возвращает причину расхождения.
+func linkError(prev domain.ChainLink, record *domain.ChainRecord) string {
+	switch {
+	case record.Seq == prev.Seq+2:
+		return fmt.Sprintf("record %d is missing", prev.Seq+1)
+	case record.Seq != prev.Seq+1:
+		return fmt.Sprintf("records %d..%d are missing", prev.Seq+1, record.Seq-1)
+	case record.PrevHash != prev.Hash:
+		return "previous hash does not match the previous record"
+	case domain.ChainHash(record.PrevHash, record.Content) != record.Hash:
+		return "hash does not match the record contents"
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package checklist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type ChecklistInteractor struct {
+	checklistRepo domain.ChecklistRepository
+	taskRepo      domain.TaskRepository
+}
+
+func NewChecklistInteractor(checklistRepo domain.ChecklistRepository, taskRepo domain.TaskRepository) domain.ChecklistInteractor {
+	return &ChecklistInteractor{checklistRepo: checklistRepo, taskRepo: taskRepo}
+}
+
+func (ci *ChecklistInteractor) Checklist(ctx context.Context, taskID string, userID string) ([]*domain.ChecklistItem, error) {
+	const op = "uc.checklist.get"
+	if err := ci.access(ctx, taskID, userID, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	items, err := ci.checklistRepo.ChecklistItems(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return items, nil
+}
+
+// AddItem добавляет пункт в конец чек-листа.
+func (ci *ChecklistInteractor) AddItem(ctx context.Context, taskID string, text string, actorID string) (*domain.ChecklistItem, error) {
+	const op = "uc.checklist.add"
+	if err := ci.access(ctx, taskID, actorID, true); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	items, err := ci.checklistRepo.ChecklistItems(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	position := 0
+	if len(items) > 0 {
+		position = items[len(items)-1].Position + 1
+	}
+	item, err := ci.checklistRepo.CreateChecklistItem(ctx, &domain.ChecklistItem{
+		TaskID:   taskID,
+		Text:     strings.TrimSpace(text),
+		Position: position,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return item, nil
+}
+
+// UpdateItem меняет текст и/или отметку выполнения; nil — поле не меняется.
+func (ci *ChecklistInteractor) UpdateItem(ctx context.Context, taskID string, id string, text *string, done *bool, actorID string) (*domain.ChecklistItem, error) {
+	const op = "uc.checklist.update"
+	if err := ci.access(ctx, taskID, actorID, true); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	item, err := ci.item(ctx, taskID, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if text != nil {
+		item.Text = strings.TrimSpace(*text)
+	}
+	if done != nil {
+		item.Done = *done
+	}
+	if err := ci.checklistRepo.UpdateChecklistItem(ctx, item); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return item, nil
+}
+
+// ReorderItems задаёт новый порядок пунктов; ids должны перечислять все пункты чек-листа.
+func (ci *ChecklistInteractor) ReorderItems(ctx context.Context, taskID string, ids []string, actorID string) ([]*domain.ChecklistItem, error) {
+	const op = "uc.checklist.reorder"
+	if err := ci.access(ctx, taskID, actorID, true); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	items, err := ci.checklistRepo.ChecklistItems(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(ids) != len(items) {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrChecklistOrder)
+	}
+	byID := make(map[string]*domain.ChecklistItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	positions := make(map[string]int, len(ids))
+	ordered := make([]*domain.ChecklistItem, 0, len(ids))
+	for i, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrChecklistOrder)
+		}
+		delete(byID, id)
+		item.Position = i
+		positions[id] = i
+		ordered = append(ordered, item)
+	}
+	if err := ci.checklistRepo.PositionChecklistItems(ctx, positions); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ordered, nil
+}
+
+func (ci *ChecklistInteractor) DeleteItem(ctx context.Context, taskID string, id string, actorID string) error {
+	const op = "uc.checklist.delete"
+	if err := ci.access(ctx, taskID, actorID, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := ci.item(ctx, taskID, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ci.checklistRepo.DeleteChecklistItem(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// access проверяет, что пользователь видит задачу проекта, а для edit — может её менять.
+func (ci *ChecklistInteractor) access(ctx context.Context, taskID string, userID string, edit bool) error {
+	task, err := ci.taskRepo.Task(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.ProjectID == "" {
+		return nil
+	}
+	member, err := ci.taskRepo.ProjectMember(ctx, task.ProjectID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if edit && !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
+
+// item ищет пункт в чек-листе задачи, чтобы нельзя было изменить пункт чужой задачи.
+func (ci *ChecklistInteractor) item(ctx context.Context, taskID string, id string) (*domain.ChecklistItem, error) {
+	items, err := ci.checklistRepo.ChecklistItems(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, domain.ErrChecklistItemNotFound
+}
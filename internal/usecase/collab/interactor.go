@@ -0,0 +1,361 @@
+package collab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib/ot"
+)
+
+var ErrNotJoined = errors.New("client has not joined the article")
+
+type participant struct {
+	client domain.CollabClient
+	cursor *domain.CursorPosition
+}
+
+// session — открытый для совместного редактирования документ статьи.
+type session struct {
+	mu         sync.Mutex
+	articleID  string
+	document   string
+	revision   int
+	history    []ot.Operation
+	clients    map[string]*participant
+	dirty      bool
+	editorID   string
+	editorName string
+	// saving не даёт двум сохранениям одной сессии идти одновременно.
+	saving sync.Mutex
+}
+
+// CollabInteractor держит в памяти документы, которые сейчас редактируются,
+// сводит параллельные правки через operational transform и периодически
+// сохраняет результат новой ревизией статьи.
+type CollabInteractor struct {
+	articles        domain.ArticleInteractor
+	locks           domain.ArticleLockInteractor
+	history         domain.HistoryInteractor
+	audit           domain.AuditInteractor
+	log             *slog.Logger
+	persistInterval time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	// releases — число закрытых сессий: по нему Join замечает, что пока статья
+	// читалась из базы, сессию могли закрыть, сохранив в статью новые правки.
+	releases int
+}
+
+func NewCollabInteractor(articles domain.ArticleInteractor, locks domain.ArticleLockInteractor, history domain.HistoryInteractor, audit domain.AuditInteractor, log *slog.Logger, persistInterval time.Duration) *CollabInteractor {
+	return &CollabInteractor{
+		articles:        articles,
+		locks:           locks,
+		history:         history,
+		audit:           audit,
+		log:             log,
+		persistInterval: persistInterval,
+		sessions:        make(map[string]*session),
+	}
+}
+
+func (ci *CollabInteractor) Join(ctx context.Context, articleID string, client domain.CollabClient) error {
+	const op = "uc.collab.join"
+	// Статью, заблокированную другим пользователем, нельзя править и через сессию.
+	if err := ci.locks.CheckWrite(ctx, articleID, client.UserID()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ci.mu.Lock()
+	s := ci.sessions[articleID]
+	for s == nil {
+		// Статья читается вне ci.mu, чтобы медленный запрос не задерживал остальные сессии.
+		releases := ci.releases
+		ci.mu.Unlock()
+		article, err := ci.articles.Article(ctx, articleID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		ci.mu.Lock()
+		// Сессию мог открыть другой участник — присоединяемся к ней; если же
+		// какую-то сессию успели закрыть, прочитанный текст мог устареть — читаем заново.
+		if s = ci.sessions[articleID]; s == nil && ci.releases == releases {
+			s = &session{
+				articleID: articleID,
+				document:  article.Content,
+				clients:   make(map[string]*participant),
+			}
+			ci.sessions[articleID] = s
+		}
+	}
+	s.mu.Lock()
+	ci.mu.Unlock()
+	defer s.mu.Unlock()
+
+	peers := make([]domain.CollabPeer, 0, len(s.clients))
+	for _, p := range s.clients {
+		peers = append(peers, peerOf(p))
+	}
+	s.clients[client.ID()] = &participant{client: client}
+	ci.send(client, domain.CollabMessage{
+		Type:     domain.CollabInit,
+		Revision: s.revision,
+		Document: s.document,
+		ClientID: client.ID(),
+		Peers:    peers,
+	})
+	ci.broadcast(s, client.ID(), domain.CollabMessage{
+		Type:     domain.CollabJoin,
+		Revision: s.revision,
+		ClientID: client.ID(),
+		UserID:   client.UserID(),
+		UserName: client.UserName(),
+	})
+	return nil
+}
+
+func (ci *CollabInteractor) Leave(ctx context.Context, articleID string, clientID string) {
+	ci.mu.Lock()
+	s, ok := ci.sessions[articleID]
+	if !ok {
+		ci.mu.Unlock()
+		return
+	}
+	s.mu.Lock()
+	ci.mu.Unlock()
+	delete(s.clients, clientID)
+	ci.broadcast(s, clientID, domain.CollabMessage{
+		Type:     domain.CollabLeave,
+		Revision: s.revision,
+		ClientID: clientID,
+	})
+	empty := len(s.clients) == 0
+	s.mu.Unlock()
+
+	// Последний редактор ушёл: сохраняем документ и закрываем сессию. Пока идёт
+	// сохранение, сессия остаётся в хабе, и новый редактор получит текст из
+	// памяти, а не устаревший из базы. Если сохранить не удалось, сессия
+	// остаётся до следующей попытки в Run.
+	if empty && ci.persist(context.WithoutCancel(ctx), s) {
+		ci.release(s)
+	}
+}
+
+// Submit принимает операцию клиента, построенную от ревизии revision,
+// преобразует её относительно уже применённых правок и рассылает остальным.
+func (ci *CollabInteractor) Submit(ctx context.Context, articleID string, clientID string, revision int, op ot.Operation) error {
+	const opName = "uc.collab.submit"
+	s, err := ci.session(articleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", opName, err)
+	}
+	s.mu.Lock()
+	author, ok := s.clients[clientID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%s: %w", opName, ErrNotJoined)
+	}
+	// Блокировку могли взять уже после входа в сессию.
+	if err := ci.locks.CheckWrite(ctx, articleID, author.client.UserID()); err != nil {
+		return fmt.Errorf("%s: %w", opName, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[clientID]; !ok {
+		return fmt.Errorf("%s: %w", opName, ErrNotJoined)
+	}
+	if revision < 0 || revision > s.revision {
+		return fmt.Errorf("%s: %w", opName, domain.ErrStaleRevision)
+	}
+	for _, concurrent := range s.history[revision:] {
+		op, _, err = ot.Transform(op, concurrent)
+		if err != nil {
+			return fmt.Errorf("%s: %w", opName, err)
+		}
+	}
+	document, err := op.Apply(s.document)
+	if err != nil {
+		return fmt.Errorf("%s: %w", opName, err)
+	}
+
+	s.document = document
+	s.history = append(s.history, op)
+	s.revision++
+	s.dirty = true
+	s.editorID = author.client.UserID()
+	s.editorName = author.client.UserName()
+
+	for id, p := range s.clients {
+		if id != clientID && p.cursor != nil {
+			p.cursor.Position = op.TransformIndex(p.cursor.Position)
+			p.cursor.SelectionEnd = op.TransformIndex(p.cursor.SelectionEnd)
+		}
+	}
+	ci.send(author.client, domain.CollabMessage{Type: domain.CollabAck, Revision: s.revision})
+	ci.broadcast(s, clientID, domain.CollabMessage{
+		Type:      domain.CollabOperation,
+		Revision:  s.revision,
+		ClientID:  clientID,
+		UserID:    author.client.UserID(),
+		UserName:  author.client.UserName(),
+		Operation: &op,
+	})
+	return nil
+}
+
+func (ci *CollabInteractor) UpdateCursor(ctx context.Context, articleID string, clientID string, cursor domain.CursorPosition) error {
+	const op = "uc.collab.cursor"
+	s, err := ci.session(articleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.clients[clientID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, ErrNotJoined)
+	}
+	stored := cursor
+	p.cursor = &stored
+	ci.broadcast(s, clientID, domain.CollabMessage{
+		Type:     domain.CollabCursor,
+		Revision: s.revision,
+		ClientID: clientID,
+		UserID:   p.client.UserID(),
+		UserName: p.client.UserName(),
+		Cursor:   &cursor,
+	})
+	return nil
+}
+
+// Run периодически сохраняет изменённые документы, пока не отменён ctx.
+func (ci *CollabInteractor) Run(ctx context.Context) {
+	ticker := time.NewTicker(ci.persistInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ci.mu.Lock()
+			sessions := make([]*session, 0, len(ci.sessions))
+			for _, s := range ci.sessions {
+				sessions = append(sessions, s)
+			}
+			ci.mu.Unlock()
+			for _, s := range sessions {
+				if ci.persist(ctx, s) {
+					ci.release(s)
+				}
+			}
+		}
+	}
+}
+
+// persist сохраняет изменённый документ новой ревизией статьи. При ошибке
+// документ снова помечается изменённым и возвращается false.
+func (ci *CollabInteractor) persist(ctx context.Context, s *session) bool {
+	s.saving.Lock()
+	defer s.saving.Unlock()
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return true
+	}
+	document, editorID, editorName := s.document, s.editorID, s.editorName
+	s.dirty = false
+	s.mu.Unlock()
+
+	markDirty := func(err error) bool {
+		ci.log.Error("failed to persist collaborative document", slog.String("article", s.articleID), slog.String("error", err.Error()))
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+		return false
+	}
+	// Пока статью держит другой пользователь, правки остаются в сессии
+	// и сохранятся после снятия блокировки.
+	if err := ci.locks.CheckWrite(ctx, s.articleID, editorID); err != nil {
+		return markDirty(err)
+	}
+	// Заголовок и обложку берём из базы: их могли поменять в обход сессии.
+	current, err := ci.articles.Article(ctx, s.articleID)
+	if err != nil {
+		return markDirty(err)
+	}
+	article, err := ci.articles.UpdateArticle(ctx, s.articleID, current.Title, current.Image, document, editorName)
+	if err != nil {
+		return markDirty(err)
+	}
+	if err := ci.history.UpdateHistory(ctx, article.ID, editorID, domain.EventType("UPDATED"), article.Title); err != nil {
+		ci.log.Error("failed to create history", slog.String("article", s.articleID), slog.String("error", err.Error()))
+	}
+	// правки приходят по websocket, поэтому пишем в журнал здесь, а не в middleware
+	ci.audit.Record(ctx, &domain.AuditEntry{
+		ActorID:    editorID,
+		EntityType: domain.AuditArticle,
+		EntityID:   article.ID,
+		Action:     domain.AuditUpdate,
+		Before:     current.AuditFields(),
+		After:      article.AuditFields(),
+		Route:      "WS /api/v1/article/:id/ws",
+	})
+	return true
+}
+
+// release закрывает сессию, если в ней не осталось участников и несохранённых правок.
+func (ci *CollabInteractor) release(s *session) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.clients) == 0 && !s.dirty && ci.sessions[s.articleID] == s {
+		delete(ci.sessions, s.articleID)
+		ci.releases++
+	}
+}
+
+func (ci *CollabInteractor) session(articleID string) (*session, error) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	s, ok := ci.sessions[articleID]
+	if !ok {
+		return nil, ErrNotJoined
+	}
+	return s, nil
+}
+
+// broadcast рассылает сообщение всем участникам, кроме except. Вызывается под s.mu.
+func (ci *CollabInteractor) broadcast(s *session, except string, msg domain.CollabMessage) {
+	for id, p := range s.clients {
+		if id != except {
+			ci.send(p.client, msg)
+		}
+	}
+}
+
+func (ci *CollabInteractor) send(client domain.CollabClient, msg domain.CollabMessage) {
+	if err := client.Send(msg); err != nil {
+		ci.log.Warn("failed to deliver collab message", slog.String("client", client.ID()), slog.String("error", err.Error()))
+	}
+}
+
+// peerOf копирует курсор: сообщение может сериализоваться уже после того,
+// как следующая правка сдвинет позицию участника.
+func peerOf(p *participant) domain.CollabPeer {
+	peer := domain.CollabPeer{
+		ClientID: p.client.ID(),
+		UserID:   p.client.UserID(),
+		UserName: p.client.UserName(),
+	}
+	if p.cursor != nil {
+		cursor := *p.cursor
+		peer.Cursor = &cursor
+	}
+	return peer
+}
@@ -0,0 +1,272 @@
+package collab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib/ot"
+)
+
+const articleID = "article-1"
+
+// articles хранит одну статью в памяти.
+type articles struct {
+	domain.ArticleInteractor
+	mu      sync.Mutex
+	article domain.Article
+	saves   int
+	fail    error
+}
+
+func (a *articles) Article(ctx context.Context, id string) (*domain.Article, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if id != a.article.ID {
+		return nil, domain.ErrArticleNotFound
+	}
+	article := a.article
+	return &article, nil
+}
+
+func (a *articles) UpdateArticle(ctx context.Context, id string, title string, image string, content string, editorName string) (*domain.Article, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.fail != nil {
+		return nil, a.fail
+	}
+	a.article.Title, a.article.Image, a.article.Content = title, image, content
+	a.article.LastEditor = editorName
+	a.article.Revision++
+	a.saves++
+	article := a.article
+	return &article, nil
+}
+
+func (a *articles) content() (string, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.article.Content, a.saves
+}
+
+// locks — блокировка статьи пользователем holder, пустой — статья свободна.
+type locks struct {
+	domain.ArticleLockInteractor
+	holder string
+}
+
+func (l *locks) CheckWrite(ctx context.Context, articleID string, userID string) error {
+	if l.holder != "" && l.holder != userID {
+		return domain.ErrArticleLocked
+	}
+	return nil
+}
+
+type history struct {
+	domain.HistoryInteractor
+}
+
+func (history) UpdateHistory(ctx context.Context, articleID string, userID string, eventType domain.EventType, articleTitle string) error {
+	return nil
+}
+
+type audit struct {
+	domain.AuditInteractor
+}
+
+func (audit) Record(ctx context.Context, entry *domain.AuditEntry) {}
+
+// client — in-process редактор, запоминает полученные сообщения.
+type client struct {
+	id   string
+	user string
+	mu   sync.Mutex
+	msgs []domain.CollabMessage
+}
+
+func (c *client) ID() string       { return c.id }
+func (c *client) UserID() string   { return c.user }
+func (c *client) UserName() string { return c.user }
+
+func (c *client) Send(msg domain.CollabMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs = append(c.msgs, msg)
+	return nil
+}
+
+func (c *client) received(kind string) []domain.CollabMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var msgs []domain.CollabMessage
+	for _, msg := range c.msgs {
+		if msg.Type == kind {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+func newCollab(content string) (*CollabInteractor, *articles, *locks) {
+	store := &articles{article: domain.Article{ID: articleID, Title: "Title", Content: content}}
+	lock := &locks{}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewCollabInteractor(store, lock, history{}, audit{}, log, time.Hour), store, lock
+}
+
+func parse(t *testing.T, raw string) ot.Operation {
+	t.Helper()
+	var op ot.Operation
+	if err := json.Unmarshal([]byte(raw), &op); err != nil {
+		t.Fatalf("parse %s: %v", raw, err)
+	}
+	return op
+}
+
+func join(t *testing.T, ci *CollabInteractor, c *client) {
+	t.Helper()
+	if err := ci.Join(context.Background(), articleID, c); err != nil {
+		t.Fatalf("join %s: %v", c.id, err)
+	}
+}
+
+func TestConcurrentEdits(t *testing.T) {
+	ci, store, _ := newCollab("hello")
+	ctx := context.Background()
+	alice := &client{id: "c1", user: "alice"}
+	bob := &client{id: "c2", user: "bob"}
+	join(t, ci, alice)
+	join(t, ci, bob)
+
+	if init := bob.received(domain.CollabInit); len(init) != 1 || init[0].Document != "hello" || len(init[0].Peers) != 1 {
+		t.Fatalf("bob init: %+v", init)
+	}
+	if joined := alice.received(domain.CollabJoin); len(joined) != 1 || joined[0].ClientID != bob.id {
+		t.Fatalf("alice join notifications: %+v", joined)
+	}
+
+	// обе правки построены от ревизии 0: правка Боба преобразуется относительно правки Алисы
+	if err := ci.Submit(ctx, articleID, alice.id, 0, parse(t, `[5, " world"]`)); err != nil {
+		t.Fatalf("alice submit: %v", err)
+	}
+	if err := ci.Submit(ctx, articleID, bob.id, 0, parse(t, `["Oh, ", 5]`)); err != nil {
+		t.Fatalf("bob submit: %v", err)
+	}
+	if err := ci.Submit(ctx, articleID, bob.id, 3, parse(t, `[15]`)); !errors.Is(err, domain.ErrStaleRevision) {
+		t.Fatalf("submit ahead of the document: got %v, want %v", err, domain.ErrStaleRevision)
+	}
+
+	// Алиса применяет у себя свою правку и полученную от сервера правку Боба
+	document := "hello world"
+	ops := alice.received(domain.CollabOperation)
+	if len(ops) != 1 || ops[0].Revision != 2 {
+		t.Fatalf("alice operations: %+v", ops)
+	}
+	document, err := ops[0].Operation.Apply(document)
+	if err != nil {
+		t.Fatalf("alice apply: %v", err)
+	}
+	if document != "Oh, hello world" {
+		t.Fatalf("alice document %q", document)
+	}
+	if acks := bob.received(domain.CollabAck); len(acks) != 1 || acks[0].Revision != 2 {
+		t.Fatalf("bob acks: %+v", acks)
+	}
+
+	ci.Leave(ctx, articleID, alice.id)
+	if _, saves := store.content(); saves != 0 {
+		t.Fatalf("saved while bob is still editing")
+	}
+	ci.Leave(ctx, articleID, bob.id)
+	if content, saves := store.content(); content != "Oh, hello world" || saves != 1 {
+		t.Fatalf("saved %q %d times", content, saves)
+	}
+	if err := ci.Submit(ctx, articleID, bob.id, 2, parse(t, `[15]`)); !errors.Is(err, ErrNotJoined) {
+		t.Fatalf("session is still open after everyone left: %v", err)
+	}
+}
+
+func TestParallelSubmits(t *testing.T) {
+	ci, store, _ := newCollab("")
+	ctx := context.Background()
+	const editors = 8
+	clients := make([]*client, editors)
+	for i := range clients {
+		clients[i] = &client{id: fmt.Sprintf("c%d", i), user: fmt.Sprintf("user%d", i)}
+		join(t, ci, clients[i])
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ci.Submit(ctx, articleID, c.id, 0, parse(t, `["x"]`)); err != nil {
+				t.Errorf("%s submit: %v", c.id, err)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, c := range clients {
+		ci.Leave(ctx, articleID, c.id)
+	}
+	if content, _ := store.content(); content != "xxxxxxxx" {
+		t.Fatalf("saved %q, want one insert per editor", content)
+	}
+}
+
+func TestLeaveKeepsUnsavedSession(t *testing.T) {
+	ci, store, _ := newCollab("draft")
+	ctx := context.Background()
+	alice := &client{id: "c1", user: "alice"}
+	join(t, ci, alice)
+	if err := ci.Submit(ctx, articleID, alice.id, 0, parse(t, `[5, "!"]`)); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	store.fail = errors.New("database is down")
+	ci.Leave(ctx, articleID, alice.id)
+	if content, _ := store.content(); content != "draft" {
+		t.Fatalf("saved %q despite the failure", content)
+	}
+
+	// правка не потерялась: новый редактор получает текст из памяти, а не из базы
+	store.fail = nil
+	bob := &client{id: "c2", user: "bob"}
+	join(t, ci, bob)
+	if init := bob.received(domain.CollabInit); len(init) != 1 || init[0].Document != "draft!" || init[0].Revision != 1 {
+		t.Fatalf("bob init: %+v", init)
+	}
+	ci.Leave(ctx, articleID, bob.id)
+	if content, saves := store.content(); content != "draft!" || saves != 1 {
+		t.Fatalf("saved %q %d times", content, saves)
+	}
+}
+
+func TestLockedArticle(t *testing.T) {
+	ci, store, lock := newCollab("text")
+	ctx := context.Background()
+	alice := &client{id: "c1", user: "alice"}
+	lock.holder = "bob"
+	if err := ci.Join(ctx, articleID, alice); !errors.Is(err, domain.ErrArticleLocked) {
+		t.Fatalf("join locked article: got %v, want %v", err, domain.ErrArticleLocked)
+	}
+
+	lock.holder = ""
+	join(t, ci, alice)
+	lock.holder = "bob"
+	if err := ci.Submit(ctx, articleID, alice.id, 0, parse(t, `[4, "!"]`)); !errors.Is(err, domain.ErrArticleLocked) {
+		t.Fatalf("submit to locked article: got %v, want %v", err, domain.ErrArticleLocked)
+	}
+	ci.Leave(ctx, articleID, alice.id)
+	if content, saves := store.content(); content != "text" || saves != 0 {
+		t.Fatalf("saved %q %d times", content, saves)
+	}
+}
@@ -0,0 +1,142 @@
+package customfield
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type CustomFieldInteractor struct {
+	fieldRepo domain.CustomFieldRepository
+}
+
+func NewCustomFieldInteractor(fieldRepo domain.CustomFieldRepository) domain.CustomFieldInteractor {
+	return &CustomFieldInteractor{fieldRepo: fieldRepo}
+}
+
+func (fi *CustomFieldInteractor) CreateField(ctx context.Context, actorID string, field *domain.CustomField) (*domain.CustomField, error) {
+	const op = "uc.custom_field.create"
+	if err := fi.checkManager(ctx, field.ProjectID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := field.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := fi.fieldRepo.FieldByName(ctx, field.ProjectID, field.Name); err == nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrFieldExists)
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created, err := fi.fieldRepo.CreateField(ctx, field)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+func (fi *CustomFieldInteractor) Fields(ctx context.Context, projectID string) ([]*domain.CustomField, error) {
+	const op = "uc.custom_field.all"
+	fields, err := fi.fieldRepo.Fields(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return fields, nil
+}
+
+// DeleteField удаляет поле вместе со всеми его значениями у задач.
+func (fi *CustomFieldInteractor) DeleteField(ctx context.Context, actorID string, id string) error {
+	const op = "uc.custom_field.delete"
+	field, err := fi.field(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := fi.checkManager(ctx, field.ProjectID, actorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := fi.fieldRepo.DeleteField(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SetTaskFields проверяет все значения по типам полей до записи: при ошибке
+// ни одно значение не меняется.
+func (fi *CustomFieldInteractor) SetTaskFields(ctx context.Context, taskID string, values map[string]string) ([]*domain.FieldValue, error) {
+	const op = "uc.custom_field.set_task"
+	task, err := fi.fieldRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	normalized := make(map[string]string, len(values))
+	for fieldID, value := range values {
+		field, err := fi.field(ctx, fieldID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if field.ProjectID != "" && field.ProjectID != task.ProjectID {
+			return nil, fmt.Errorf("%s: %w: %s belongs to another project", op, domain.ErrInvalidField, field.Name)
+		}
+		if value == "" {
+			normalized[fieldID] = ""
+			continue
+		}
+		if normalized[fieldID], err = field.Normalize(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if field.Type == domain.FieldUser {
+			if _, err := fi.fieldRepo.User(ctx, normalized[fieldID]); err != nil {
+				if errors.Is(err, db.ErrNotFound) {
+					return nil, fmt.Errorf("%s: %w: %s: user not found", op, domain.ErrInvalidFieldValue, field.Name)
+				}
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+	if err := fi.fieldRepo.SetTaskFields(ctx, taskID, normalized); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	fields, err := fi.fieldRepo.TaskFields(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return fields, nil
+}
+
+func (fi *CustomFieldInteractor) field(ctx context.Context, id string) (*domain.CustomField, error) {
+	field, err := fi.fieldRepo.Field(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrFieldNotFound
+		}
+		return nil, err
+	}
+	return field, nil
+}
+
+// checkManager пропускает администраторов, а для полей проекта — и его владельцев.
+func (fi *CustomFieldInteractor) checkManager(ctx context.Context, projectID string, actorID string) error {
+	actor, err := fi.fieldRepo.User(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if actor.IsAdmin == domain.AdminRole {
+		return nil
+	}
+	if projectID == "" {
+		return domain.ErrAdminRequired
+	}
+	member, err := fi.fieldRepo.ProjectMember(ctx, projectID, actorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if member.Role != domain.ProjectOwner {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type DependencyInteractor struct {
+	depRepo domain.DependencyRepository
+}
+
+func NewDependencyInteractor(depRepo domain.DependencyRepository) domain.DependencyInteractor {
+	return &DependencyInteractor{depRepo: depRepo}
+}
+
+// AddDependency отмечает, что blockerID блокирует blockedID.
+// Связь, замыкающая цикл, и связь задач разных проектов отклоняются.
+func (di *DependencyInteractor) AddDependency(ctx context.Context, blockerID string, blockedID string, actorID string) (*domain.TaskDependency, error) {
+	const op = "uc.dependency.add"
+	if blockerID == blockedID {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrDependencyCycle)
+	}
+	blocker, blocked, err := di.tasks(ctx, blockerID, blockedID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if blocker.ProjectID != blocked.ProjectID {
+		return nil, fmt.Errorf("%s: %w: tasks belong to different projects", op, domain.ErrInvalidProject)
+	}
+	if _, err := di.depRepo.Dependency(ctx, blockerID, blockedID); err == nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrDependencyExists)
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	dependency := domain.TaskDependency{
+		BlockerID:   blockerID,
+		BlockedID:   blockedID,
+		CreatedByID: actorID,
+	}
+	ok, err := di.depRepo.AddDependency(ctx, &dependency)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrDependencyCycle)
+	}
+	return &dependency, nil
+}
+
+func (di *DependencyInteractor) RemoveDependency(ctx context.Context, blockerID string, blockedID string, actorID string) error {
+	const op = "uc.dependency.remove"
+	if _, _, err := di.tasks(ctx, blockerID, blockedID, actorID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, domain.ErrDependencyNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	dependency, err := di.depRepo.Dependency(ctx, blockerID, blockedID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, domain.ErrDependencyNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := di.depRepo.DeleteDependency(ctx, dependency.ID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// CriticalPath считает критический путь для набора задач ids.
+// Все задачи проектов в наборе должны быть видны пользователю.
+func (di *DependencyInteractor) CriticalPath(ctx context.Context, ids []string, userID string) (*domain.CriticalPath, error) {
+	const op = "uc.dependency.critical_path"
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	tasks, err := di.depRepo.TasksByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	checked := make(map[string]bool)
+	for _, task := range tasks {
+		if task.ProjectID == "" || checked[task.ProjectID] {
+			continue
+		}
+		if err := di.access(ctx, task, userID, false); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		checked[task.ProjectID] = true
+	}
+	dependencies, err := di.depRepo.Dependencies(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	path, err := domain.CalculateCriticalPath(tasks, dependencies)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return path, nil
+}
+
+// tasks загружает обе задачи связи и проверяет, что пользователь может их менять.
+func (di *DependencyInteractor) tasks(ctx context.Context, blockerID string, blockedID string, actorID string) (*domain.Task, *domain.Task, error) {
+	blocker, err := di.depRepo.Task(ctx, blockerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	blocked, err := di.depRepo.Task(ctx, blockedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, task := range []*domain.Task{blocker, blocked} {
+		if err := di.access(ctx, task, actorID, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	return blocker, blocked, nil
+}
+
+// access проверяет, что пользователь видит задачу проекта, а для edit — может её менять.
+func (di *DependencyInteractor) access(ctx context.Context, task *domain.Task, userID string, edit bool) error {
+	if task.ProjectID == "" {
+		return nil
+	}
+	member, err := di.depRepo.ProjectMember(ctx, task.ProjectID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if edit && !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
@@ -22,7 +22,8 @@ func (hi *HistoryInteractor) InitHistory(ctx context.Context, articleID string,
 		UserId:       userID,
 		ArticleTitle: articleTitle,
 	}
-	err := hi.historyRepo.InitHistory(ctx, history)
+	// событие пишется после изменения статьи и не должно пропасть при отмене запроса
+	err := hi.historyRepo.InitHistory(context.WithoutCancel(ctx), history)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -38,7 +39,7 @@ func (hi *HistoryInteractor) UpdateHistory(ctx context.Context, articleID string
 		ArticleTitle: articleTitle,
 		EventType:    eventType,
 	}
-	err := hi.historyRepo.UpdateHistory(ctx, history)
+	err := hi.historyRepo.UpdateHistory(context.WithoutCancel(ctx), history)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -46,9 +47,22 @@ func (hi *HistoryInteractor) UpdateHistory(ctx context.Context, articleID string
 
 }
 
-func (hi *HistoryInteractor) Histories(ctx context.Context, page, limit int) ([]*domain.History, error) {
+func (hi *HistoryInteractor) Histories(ctx context.Context, filter domain.HistoryFilter) ([]*domain.History, error) {
 	const op = "uc.history.all"
-	histories, err := hi.historyRepo.Histories(ctx, page, limit)
+	if err := filter.Normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	histories, err := hi.historyRepo.Histories(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return histories, nil
+}
+
+func (hi *HistoryInteractor) ArticleHistory(ctx context.Context, articleID string, filter domain.HistoryFilter) ([]*domain.History, error) {
+	const op = "uc.history.article"
+	filter.ArticleID = articleID
+	histories, err := hi.Histories(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
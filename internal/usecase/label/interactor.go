@@ -0,0 +1,156 @@
+package label
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type LabelInteractor struct {
+	labelRepo domain.LabelRepository
+}
+
+func NewLabelInteractor(labelRepo domain.LabelRepository) domain.LabelInteractor {
+	return &LabelInteractor{labelRepo: labelRepo}
+}
+
+func (li *LabelInteractor) CreateLabel(ctx context.Context, actorID string, label *domain.Label) (*domain.Label, error) {
+	const op = "uc.label.create"
+	if err := label.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := li.checkProject(ctx, label.ProjectID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := li.checkName(ctx, label); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created, err := li.labelRepo.CreateLabel(ctx, label)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+// UpdateLabel меняет имя и цвет; проект метки не меняется.
+func (li *LabelInteractor) UpdateLabel(ctx context.Context, actorID string, label *domain.Label) (*domain.Label, error) {
+	const op = "uc.label.update"
+	current, err := li.label(ctx, label.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	label.ProjectID = current.ProjectID
+	if err := li.checkProject(ctx, label.ProjectID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := label.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := li.checkName(ctx, label); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := li.labelRepo.UpdateLabel(ctx, label); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return li.label(ctx, label.ID)
+}
+
+func (li *LabelInteractor) Labels(ctx context.Context, projectID string) ([]*domain.Label, error) {
+	const op = "uc.label.all"
+	labels, err := li.labelRepo.Labels(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return labels, nil
+}
+
+// DeleteLabel удаляет метку и снимает её со всех задач.
+func (li *LabelInteractor) DeleteLabel(ctx context.Context, actorID string, id string) error {
+	const op = "uc.label.delete"
+	label, err := li.label(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := li.checkProject(ctx, label.ProjectID, actorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := li.labelRepo.DeleteLabel(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SetTaskLabels принимает общие метки и метки проекта задачи.
+func (li *LabelInteractor) SetTaskLabels(ctx context.Context, taskID string, labelIDs []string) ([]*domain.Label, error) {
+	const op = "uc.label.set_task"
+	task, err := li.labelRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	slices.Sort(labelIDs)
+	labelIDs = slices.Compact(labelIDs)
+	labels, err := li.labelRepo.LabelsByIDs(ctx, labelIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(labels) != len(labelIDs) {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrLabelNotFound)
+	}
+	for _, label := range labels {
+		if label.ProjectID != "" && label.ProjectID != task.ProjectID {
+			return nil, fmt.Errorf("%s: %w: %s belongs to another project", op, domain.ErrInvalidLabel, label.Name)
+		}
+	}
+	if err := li.labelRepo.SetTaskLabels(ctx, taskID, labelIDs); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return labels, nil
+}
+
+func (li *LabelInteractor) label(ctx context.Context, id string) (*domain.Label, error) {
+	label, err := li.labelRepo.Label(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrLabelNotFound
+		}
+		return nil, err
+	}
+	return label, nil
+}
+
+// checkName не даёт завести две метки с одним именем в одной области.
+func (li *LabelInteractor) checkName(ctx context.Context, label *domain.Label) error {
+	existing, err := li.labelRepo.LabelByName(ctx, label.ProjectID, label.Name)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != label.ID {
+		return domain.ErrLabelExists
+	}
+	return nil
+}
+
+// checkProject проверяет право редактировать метки проекта; общие метки доступны всем.
+func (li *LabelInteractor) checkProject(ctx context.Context, projectID string, actorID string) error {
+	if projectID == "" {
+		return nil
+	}
+	member, err := li.labelRepo.ProjectMember(ctx, projectID, actorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type ArticleLockInteractor struct {
+	lockRepo     domain.ArticleLockRepository
+	userRepo     domain.UserRepository
+	log          *slog.Logger
+	defaultLease time.Duration
+	maxLease     time.Duration
+	reapInterval time.Duration
+}
+
+func NewArticleLockInteractor(lockRepo domain.ArticleLockRepository, userRepo domain.UserRepository, log *slog.Logger, defaultLease, maxLease, reapInterval time.Duration) *ArticleLockInteractor {
+	return &ArticleLockInteractor{
+		lockRepo:     lockRepo,
+		userRepo:     userRepo,
+		log:          log,
+		defaultLease: defaultLease,
+		maxLease:     maxLease,
+		reapInterval: reapInterval,
+	}
+}
+
+// Acquire выдаёт аренду статьи. Повторный вызов владельцем продлевает её,
+// истёкшая чужая аренда перехватывается. Если статья занята, вместе с
+// ErrArticleLocked возвращается текущая блокировка.
+func (li *ArticleLockInteractor) Acquire(ctx context.Context, articleID string, userID string, userName string, lease time.Duration) (*domain.ArticleLock, error) {
+	const op = "uc.lock.acquire"
+	if lease <= 0 {
+		lease = li.defaultLease
+	}
+	lease = min(lease, li.maxLease)
+	now := time.Now()
+	acquired, err := li.lockRepo.AcquireLock(ctx, &domain.ArticleLock{
+		ArticleID:  articleID,
+		UserID:     userID,
+		UserName:   userName,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(lease),
+		Lease:      lease,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	lock, err := li.lockRepo.ArticleLock(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !acquired {
+		return lock, fmt.Errorf("%s: %w", op, domain.ErrArticleLocked)
+	}
+	return lock, nil
+}
+
+func (li *ArticleLockInteractor) Heartbeat(ctx context.Context, articleID string, userID string) (*domain.ArticleLock, error) {
+	const op = "uc.lock.heartbeat"
+	lock, err := li.Lock(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if lock == nil || lock.UserID != userID {
+		return lock, fmt.Errorf("%s: %w", op, domain.ErrLockNotHeld)
+	}
+	extended, err := li.lockRepo.ExtendLock(ctx, articleID, userID, time.Now().Add(lock.Lease))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	// Аренда могла истечь и перейти к другому между чтением и продлением.
+	if !extended {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrLockNotHeld)
+	}
+	lock, err = li.lockRepo.ArticleLock(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return lock, nil
+}
+
+func (li *ArticleLockInteractor) Release(ctx context.Context, articleID string, userID string) error {
+	const op = "uc.lock.release"
+	released, err := li.lockRepo.DeleteUserLock(ctx, articleID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !released {
+		return fmt.Errorf("%s: %w", op, domain.ErrLockNotHeld)
+	}
+	return nil
+}
+
+// Break снимает чужую блокировку. Доступно только администраторам.
+func (li *ArticleLockInteractor) Break(ctx context.Context, articleID string, userID string) error {
+	const op = "uc.lock.break"
+	user, err := li.userRepo.User(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if user.IsAdmin != domain.AdminRole {
+		return fmt.Errorf("%s: %w", op, domain.ErrAdminRequired)
+	}
+	if err := li.lockRepo.DeleteLock(ctx, articleID); err != nil && !errors.Is(err, db.ErrNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Lock возвращает действующую блокировку статьи или nil, если её нет.
+func (li *ArticleLockInteractor) Lock(ctx context.Context, articleID string) (*domain.ArticleLock, error) {
+	const op = "uc.lock.get"
+	lock, err := li.lockRepo.ArticleLock(ctx, articleID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if lock.ExpiresAt.Before(time.Now()) {
+		return nil, nil
+	}
+	return lock, nil
+}
+
+// CheckWrite разрешает запись, если статья не заблокирована или заблокирована самим пользователем.
+func (li *ArticleLockInteractor) CheckWrite(ctx context.Context, articleID string, userID string) error {
+	const op = "uc.lock.check"
+	lock, err := li.Lock(ctx, articleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if lock != nil && lock.UserID != userID {
+		return fmt.Errorf("%s: %w", op, domain.ErrArticleLocked)
+	}
+	return nil
+}
+
+// Run периодически удаляет истёкшие блокировки, пока не отменён ctx.
+func (li *ArticleLockInteractor) Run(ctx context.Context) {
+	ticker := time.NewTicker(li.reapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := li.lockRepo.DeleteExpiredLocks(ctx, time.Now())
+			if err != nil {
+				li.log.Error("failed to reap article locks", slog.String("error", err.Error()))
+				continue
+			}
+			if count > 0 {
+				li.log.Info("reaped expired article locks", slog.Int("count", count))
+			}
+		}
+	}
+}
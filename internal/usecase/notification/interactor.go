@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type NotificationInteractor struct {
+	notificationRepo domain.NotificationRepository
+	channels         []domain.Notifier
+	log              *slog.Logger
+}
+
+// NewNotificationInteractor: уведомления всегда сохраняются в приложении,
+// channels — дополнительные каналы доставки.
+func NewNotificationInteractor(notificationRepo domain.NotificationRepository, log *slog.Logger, channels ...domain.Notifier) *NotificationInteractor {
+	return &NotificationInteractor{
+		notificationRepo: notificationRepo,
+		channels:         channels,
+		log:              log,
+	}
+}
+
+// Send сохраняет уведомление и рассылает его по каналам. Сбой внешнего
+// канала не отменяет доставку в приложении и только логируется.
+func (ni *NotificationInteractor) Send(ctx context.Context, notification *domain.Notification) error {
+	const op = "uc.notification.send"
+	if err := ni.notificationRepo.CreateNotification(ctx, notification); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, channel := range ni.channels {
+		if err := channel.Notify(ctx, notification); err != nil {
+			ni.log.Error("failed to deliver notification",
+				slog.String("notification", notification.ID),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	return nil
+}
+
+func (ni *NotificationInteractor) Notifications(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]*domain.Notification, error) {
+	const op = "uc.notification.all"
+	notifications, err := ni.notificationRepo.Notifications(ctx, userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return notifications, nil
+}
+
+func (ni *NotificationInteractor) MarkRead(ctx context.Context, userID string, id string) error {
+	const op = "uc.notification.read"
+	ok, err := ni.notificationRepo.MarkNotificationRead(ctx, userID, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", op, domain.ErrNotificationNotFound)
+	}
+	return nil
+}
+
+func (ni *NotificationInteractor) MarkAllRead(ctx context.Context, userID string) error {
+	const op = "uc.notification.read_all"
+	if err := ni.notificationRepo.MarkAllNotificationsRead(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+// LogNotifier пишет уведомления в лог приложения.
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification *domain.Notification) error {
+	n.log.Info("notification",
+		slog.String("user", notification.UserID),
+		slog.String("kind", string(notification.Kind)),
+		slog.String("task", notification.TaskID),
+		slog.String("message", notification.Message),
+	)
+	return nil
+}
@@ -0,0 +1,185 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type ProjectInteractor struct {
+	projectRepo domain.ProjectRepository
+}
+
+func NewProjectInteractor(projectRepo domain.ProjectRepository) domain.ProjectInteractor {
+	return &ProjectInteractor{projectRepo: projectRepo}
+}
+
+func (pi *ProjectInteractor) CreateProject(ctx context.Context, actorID string, project *domain.Project) (*domain.Project, error) {
+	const op = "uc.project.create"
+	if err := project.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := pi.projectRepo.ProjectByKey(ctx, project.Key); err == nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrProjectKeyExists)
+	} else if !errors.Is(err, db.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	project.CreatedByID = actorID
+	created, err := pi.projectRepo.CreateProject(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return pi.Project(ctx, created.ID, actorID)
+}
+
+func (pi *ProjectInteractor) Projects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	const op = "uc.project.all"
+	projects, err := pi.projectRepo.UserProjects(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return projects, nil
+}
+
+// Project возвращает проект с участниками; доступен только участникам.
+func (pi *ProjectInteractor) Project(ctx context.Context, id string, actorID string) (*domain.Project, error) {
+	const op = "uc.project.get"
+	project, err := pi.project(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := pi.member(ctx, id, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if project.Members, err = pi.projectRepo.ProjectMembers(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return project, nil
+}
+
+// SetMember добавляет участника или меняет его роль. Доступно владельцам проекта;
+// последний владелец не может понизить себя.
+func (pi *ProjectInteractor) SetMember(ctx context.Context, projectID string, actorID string, userID string, role domain.ProjectRole) (*domain.ProjectMembership, error) {
+	const op = "uc.project.set_member"
+	if !role.Valid() {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidProjectRole)
+	}
+	if err := pi.checkOwner(ctx, projectID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := pi.projectRepo.User(ctx, userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	member := &domain.ProjectMembership{ProjectID: projectID, UserID: userID, Role: role}
+	current, err := pi.projectRepo.ProjectMember(ctx, projectID, userID)
+	switch {
+	case err == nil && current.Role == domain.ProjectOwner && role != domain.ProjectOwner:
+		ok, err := pi.projectRepo.DemoteProjectOwner(ctx, member)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrLastProjectOwner)
+		}
+	case err == nil || errors.Is(err, db.ErrNotFound):
+		if err := pi.projectRepo.SetProjectMember(ctx, member); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	member, err = pi.projectRepo.ProjectMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return member, nil
+}
+
+// RemoveMember исключает участника. Владелец может исключить любого,
+// остальные — только выйти сами.
+func (pi *ProjectInteractor) RemoveMember(ctx context.Context, projectID string, actorID string, userID string) error {
+	const op = "uc.project.remove_member"
+	if actorID != userID {
+		if err := pi.checkOwner(ctx, projectID, actorID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if _, err := pi.member(ctx, projectID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ok, err := pi.projectRepo.RemoveProjectMember(ctx, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", op, domain.ErrLastProjectOwner)
+	}
+	return nil
+}
+
+func (pi *ProjectInteractor) Tasks(ctx context.Context, projectID string, actorID string, filter domain.TaskFilter) (*domain.TaskPage, error) {
+	const op = "uc.project.tasks"
+	if _, err := pi.project(ctx, projectID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := pi.member(ctx, projectID, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	filter.ProjectID = projectID
+	if err := filter.Normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	tasks, total, err := pi.projectRepo.Tasks(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &domain.TaskPage{
+		Tasks: tasks,
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Pages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+func (pi *ProjectInteractor) project(ctx context.Context, id string) (*domain.Project, error) {
+	project, err := pi.projectRepo.Project(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+func (pi *ProjectInteractor) member(ctx context.Context, projectID string, userID string) (*domain.ProjectMembership, error) {
+	member, err := pi.projectRepo.ProjectMember(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrNotProjectMember
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (pi *ProjectInteractor) checkOwner(ctx context.Context, projectID string, actorID string) error {
+	if _, err := pi.project(ctx, projectID); err != nil {
+		return err
+	}
+	actor, err := pi.member(ctx, projectID, actorID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != domain.ProjectOwner {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package recurrence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+// maxCatchUp ограничивает число вхождений, создаваемых для одного правила за проход.
+const maxCatchUp = 100
+
+type RecurrenceInteractor struct {
+	recurrenceRepo domain.RecurrenceRepository
+	userRepo       domain.UserRepository
+	log            *slog.Logger
+	interval       time.Duration
+	lead           time.Duration
+}
+
+// NewRecurrenceInteractor: interval — период проверки правил, lead — за сколько
+// до запланированной даты создаётся вхождение по расписанию.
+func NewRecurrenceInteractor(recurrenceRepo domain.RecurrenceRepository, userRepo domain.UserRepository, log *slog.Logger, interval, lead time.Duration) *RecurrenceInteractor {
+	return &RecurrenceInteractor{
+		recurrenceRepo: recurrenceRepo,
+		userRepo:       userRepo,
+		log:            log,
+		interval:       interval,
+		lead:           lead,
+	}
+}
+
+// CreateRecurrence сохраняет правило и сразу создаёт первое вхождение.
+// Непустой rrule задаёт частоту, интервал и ограничения вместо полей правила.
+func (ri *RecurrenceInteractor) CreateRecurrence(ctx context.Context, recurrence *domain.Recurrence, rrule string) (*domain.Recurrence, error) {
+	const op = "uc.recurrence.create"
+	if rrule != "" {
+		if err := recurrence.ApplyRRule(rrule); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if recurrence.Interval == 0 {
+		recurrence.Interval = 1
+	}
+	if recurrence.Mode == "" {
+		recurrence.Mode = domain.OnSchedule
+	}
+	if recurrence.StartAt.IsZero() {
+		recurrence.StartAt = time.Now()
+	}
+	if recurrence.UserID == "" {
+		recurrence.UserID = recurrence.CreatorID
+	}
+	if err := recurrence.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err := ri.userRepo.User(ctx, recurrence.UserID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrUserNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if recurrence.ProjectID != "" {
+		if err := ri.access(ctx, recurrence, recurrence.CreatorID, true); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if err := ri.access(ctx, recurrence, recurrence.UserID, false); err != nil {
+			return nil, fmt.Errorf("%s: assignee: %w", op, err)
+		}
+	}
+	recurrence.Slot = 0
+	recurrence.NextAt = recurrence.StartAt
+	recurrence.Occurrences = 0
+	recurrence.Active = true
+
+	id, err := ri.recurrenceRepo.CreateRecurrence(ctx, recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	recurrence.ID = id
+	if _, err := ri.materialize(ctx, recurrence, time.Now()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return recurrence, nil
+}
+
+func (ri *RecurrenceInteractor) Recurrence(ctx context.Context, id string, userID string) (*domain.Recurrence, error) {
+	const op = "uc.recurrence.get"
+	recurrence, err := ri.recurrenceRepo.Recurrence(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ri.access(ctx, recurrence, userID, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return recurrence, nil
+}
+
+// Recurrences возвращает правила без проекта и правила проектов, где пользователь состоит.
+func (ri *RecurrenceInteractor) Recurrences(ctx context.Context, userID string) ([]*domain.Recurrence, error) {
+	const op = "uc.recurrence.all"
+	recurrences, err := ri.recurrenceRepo.Recurrences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	visible := make(map[string]bool)
+	filtered := recurrences[:0]
+	for _, recurrence := range recurrences {
+		if recurrence.ProjectID != "" {
+			ok, checked := visible[recurrence.ProjectID]
+			if !checked {
+				err := ri.access(ctx, recurrence, userID, false)
+				if err != nil && !errors.Is(err, domain.ErrNotProjectMember) {
+					return nil, fmt.Errorf("%s: %w", op, err)
+				}
+				ok = err == nil
+				visible[recurrence.ProjectID] = ok
+			}
+			if !ok {
+				continue
+			}
+		}
+		filtered = append(filtered, recurrence)
+	}
+	return filtered, nil
+}
+
+// StopRecurrence прекращает создание новых вхождений; созданные задачи остаются.
+func (ri *RecurrenceInteractor) StopRecurrence(ctx context.Context, id string, actorID string) error {
+	const op = "uc.recurrence.stop"
+	recurrence, err := ri.recurrenceRepo.Recurrence(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ri.access(ctx, recurrence, actorID, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ri.recurrenceRepo.StopRecurrence(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Run периодически создаёт вхождения, которым подошёл срок, до отмены ctx.
+func (ri *RecurrenceInteractor) Run(ctx context.Context) {
+	ticker := time.NewTicker(ri.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ri.process(ctx, time.Now())
+		}
+	}
+}
+
+func (ri *RecurrenceInteractor) process(ctx context.Context, now time.Time) {
+	recurrences, err := ri.recurrenceRepo.DueRecurrences(ctx, now.Add(ri.lead))
+	if err != nil {
+		ri.log.Error("failed to get due recurrences", slog.String("error", err.Error()))
+		return
+	}
+	for _, recurrence := range recurrences {
+		// По расписанию догоняем все пропущенные даты, по завершению создаём одно вхождение.
+		for i := 0; i < maxCatchUp; i++ {
+			created, err := ri.materialize(ctx, recurrence, now)
+			if err != nil {
+				ri.log.Error("failed to materialize recurrence",
+					slog.String("recurrence", recurrence.ID),
+					slog.String("error", err.Error()),
+				)
+				break
+			}
+			if !created || recurrence.Mode != domain.OnSchedule || recurrence.NextAt.After(now.Add(ri.lead)) {
+				break
+			}
+		}
+	}
+}
+
+// materialize создаёт следующее вхождение правила и продвигает его на слот вперёд.
+// Исчерпанное правило деактивируется.
+func (ri *RecurrenceInteractor) materialize(ctx context.Context, recurrence *domain.Recurrence, now time.Time) (bool, error) {
+	slot := recurrence.Slot
+	at := recurrence.OccurrenceAt(slot)
+	if recurrence.Mode == domain.OnCompletion && recurrence.Occurrences > 0 {
+		// Предыдущее вхождение завершено поздно: пропущенные даты не создаём.
+		for at.Before(now) && !recurrence.Exhausted(at) {
+			slot++
+			at = recurrence.OccurrenceAt(slot)
+		}
+	}
+	if recurrence.Exhausted(at) {
+		recurrence.Active = false
+		return false, ri.recurrenceRepo.StopRecurrence(ctx, recurrence.ID)
+	}
+
+	lastRank, err := ri.recurrenceRepo.LastRank(ctx, recurrence.ProjectID, domain.Pending)
+	if err != nil {
+		return false, err
+	}
+	rank, err := lib.RankBetween(lastRank, "")
+	if err != nil {
+		return false, err
+	}
+	prev := recurrence.Occurrences
+	recurrence.Occurrences++
+	recurrence.Slot = slot + 1
+	recurrence.NextAt = recurrence.OccurrenceAt(recurrence.Slot)
+	task := domain.Task{
+		Title:        recurrence.Title,
+		Content:      recurrence.Content,
+		Image:        recurrence.Image,
+		PlannedAt:    at,
+		UserID:       recurrence.UserID,
+		CreatorID:    recurrence.CreatorID,
+		Priority:     recurrence.Priority,
+		Status:       domain.Pending,
+		Rank:         rank,
+		RecurrenceID: recurrence.ID,
+		Occurrence:   recurrence.Occurrences,
+	}
+	return ri.recurrenceRepo.MaterializeOccurrence(ctx, recurrence, prev, &task)
+}
+
+// access проверяет, что пользователь состоит в проекте правила, а для edit —
+// может создавать в нём задачи. Правила без проекта доступны всем.
+func (ri *RecurrenceInteractor) access(ctx context.Context, recurrence *domain.Recurrence, userID string, edit bool) error {
+	if recurrence.ProjectID == "" {
+		return nil
+	}
+	member, err := ri.recurrenceRepo.ProjectMember(ctx, recurrence.ProjectID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if edit && !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
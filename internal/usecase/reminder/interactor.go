@@ -0,0 +1,172 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+type ReminderInteractor struct {
+	reminderRepo    domain.ReminderRepository
+	notifications   domain.NotificationInteractor
+	log             *slog.Logger
+	interval        time.Duration
+	defaultOffsets  []int
+	maxOffset       int
+	escalationDelay time.Duration
+}
+
+// NewReminderInteractor: defaultOffsets — смещения напоминаний в часах для пользователей
+// без своих настроек, maxOffset — наибольшее допустимое смещение, escalationDelay —
+// через сколько после просрочки задача HIGH эскалируется.
+func NewReminderInteractor(reminderRepo domain.ReminderRepository, notifications domain.NotificationInteractor, log *slog.Logger, interval time.Duration, defaultOffsets []int, maxOffset int, escalationDelay time.Duration) *ReminderInteractor {
+	return &ReminderInteractor{
+		reminderRepo:    reminderRepo,
+		notifications:   notifications,
+		log:             log,
+		interval:        interval,
+		defaultOffsets:  defaultOffsets,
+		maxOffset:       maxOffset,
+		escalationDelay: escalationDelay,
+	}
+}
+
+// Run периодически рассылает напоминания, отмечает просрочку и эскалирует задачи до отмены ctx.
+func (ri *ReminderInteractor) Run(ctx context.Context) {
+	ticker := time.NewTicker(ri.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			for _, step := range []func(context.Context, time.Time) error{ri.remind, ri.markOverdue, ri.escalate} {
+				if err := step(ctx, now); err != nil {
+					ri.log.Error("reminder worker failed", slog.String("error", err.Error()))
+				}
+			}
+		}
+	}
+}
+
+// remind отправляет одно напоминание на задачу, даже если после простоя наступило
+// сразу несколько смещений: все наступившие отмечаются отправленными.
+func (ri *ReminderInteractor) remind(ctx context.Context, now time.Time) error {
+	const op = "uc.reminder.remind"
+	tasks, err := ri.reminderRepo.UpcomingTasks(ctx, now, now.Add(time.Duration(ri.maxOffset)*time.Hour))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, task := range tasks {
+		offsets := task.ReminderOffsets
+		if len(offsets) == 0 {
+			offsets = ri.defaultOffsets
+		}
+		due := false
+		for _, offset := range offsets {
+			if slices.Contains(task.RemindedOffsets, offset) || task.PlannedAt.Add(-time.Duration(offset)*time.Hour).After(now) {
+				continue
+			}
+			marked, err := ri.reminderRepo.MarkReminded(ctx, task.ID, offset)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			due = due || marked
+		}
+		if !due {
+			continue
+		}
+		hours := int(task.PlannedAt.Sub(now).Round(time.Hour) / time.Hour)
+		ri.send(ctx, task.UserID, domain.NotifyReminder, task,
+			fmt.Sprintf("Срок задачи «%s» наступит через %d ч.", task.Title, max(hours, 1)))
+	}
+	return nil
+}
+
+func (ri *ReminderInteractor) markOverdue(ctx context.Context, now time.Time) error {
+	const op = "uc.reminder.overdue"
+	tasks, err := ri.reminderRepo.MarkOverdue(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	for _, task := range tasks {
+		ri.send(ctx, task.UserID, domain.NotifyOverdue, task,
+			fmt.Sprintf("Задача «%s» просрочена.", task.Title))
+	}
+	return nil
+}
+
+// escalate сообщает о просроченных задачах HIGH тому, кто их назначил: автору
+// последнего переназначения, а без переназначений — создателю задачи.
+// Если задачу исполнитель назначил себе сам — администраторам.
+func (ri *ReminderInteractor) escalate(ctx context.Context, now time.Time) error {
+	const op = "uc.reminder.escalate"
+	tasks, err := ri.reminderRepo.MarkEscalated(ctx, now.Add(-ri.escalationDelay), now)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	var admins []*domain.User
+	for _, task := range tasks {
+		assigner := ri.assigner(ctx, task)
+		recipients := []string{assigner}
+		if assigner == "" || assigner == task.UserID {
+			if admins == nil {
+				if admins, err = ri.reminderRepo.Admins(ctx); err != nil {
+					return fmt.Errorf("%s: %w", op, err)
+				}
+			}
+			recipients = recipients[:0]
+			for _, admin := range admins {
+				if admin.ID != task.UserID {
+					recipients = append(recipients, admin.ID)
+				}
+			}
+		}
+		for _, userID := range recipients {
+			ri.send(ctx, userID, domain.NotifyEscalation, task,
+				fmt.Sprintf("Задача «%s» с высоким приоритетом просрочена и не выполнена.", task.Title))
+		}
+	}
+	return nil
+}
+
+// assigner возвращает того, кто назначил задачу текущему исполнителю. Задача уже
+// отмечена эскалированной, поэтому при ошибке чтения журнала эскалация уходит создателю.
+func (ri *ReminderInteractor) assigner(ctx context.Context, task *domain.DeadlineTask) string {
+	assignments, err := ri.reminderRepo.TaskAssignments(ctx, task.ID)
+	if err != nil {
+		ri.log.Error("failed to get task assignments",
+			slog.String("task", task.ID),
+			slog.String("error", err.Error()),
+		)
+		return task.CreatorID
+	}
+	if len(assignments) > 0 {
+		return assignments[0].ChangedByID
+	}
+	return task.CreatorID
+}
+
+func (ri *ReminderInteractor) send(ctx context.Context, userID string, kind domain.NotificationKind, task *domain.DeadlineTask, message string) {
+	err := ri.notifications.Send(ctx, &domain.Notification{
+		UserID:  userID,
+		Kind:    kind,
+		TaskID:  task.ID,
+		Message: message,
+	})
+	if err != nil {
+		ri.log.Error("failed to send notification",
+			slog.String("task", task.ID),
+			slog.String("kind", string(kind)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
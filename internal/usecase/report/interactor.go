@@ -0,0 +1,88 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type ReportInteractor struct {
+	reportRepo domain.ReportRepository
+}
+
+func NewReportInteractor(reportRepo domain.ReportRepository) domain.ReportInteractor {
+	return &ReportInteractor{reportRepo: reportRepo}
+}
+
+func (ri *ReportInteractor) Workload(ctx context.Context, actorID string, filter domain.ReportFilter) (*domain.WorkloadReport, error) {
+	const op = "uc.report.workload"
+	now := time.Now()
+	if err := filter.Normalize(now); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ri.checkAccess(ctx, actorID, filter); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if filter.UserID != "" {
+		if _, err := ri.reportRepo.User(ctx, filter.UserID); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return nil, fmt.Errorf("%s: %w", op, domain.ErrUserNotFound)
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	stats, err := ri.reportRepo.WorkloadStats(ctx, filter, now)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	report := &domain.WorkloadReport{
+		From:      filter.From,
+		To:        filter.To,
+		ProjectID: filter.ProjectID,
+		Users:     make([]*domain.Workload, 0, len(stats)),
+	}
+	var total domain.WorkloadStats
+	for _, userStats := range stats {
+		total.Add(userStats)
+		report.Users = append(report.Users, userStats.Workload())
+	}
+	report.Total = total.Workload()
+	return report, nil
+}
+
+// checkAccess: отчёт по себе — любому пользователю, по команде — участнику
+// проекта, в остальных случаях нужен администратор.
+func (ri *ReportInteractor) checkAccess(ctx context.Context, actorID string, filter domain.ReportFilter) error {
+	if filter.ProjectID != "" {
+		if _, err := ri.reportRepo.Project(ctx, filter.ProjectID); err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return domain.ErrProjectNotFound
+			}
+			return err
+		}
+		_, err := ri.reportRepo.ProjectMember(ctx, filter.ProjectID, actorID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, db.ErrNotFound) {
+			return err
+		}
+	} else if filter.UserID == actorID {
+		return nil
+	}
+	actor, err := ri.reportRepo.User(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if actor.IsAdmin != domain.AdminRole {
+		if filter.ProjectID != "" {
+			return domain.ErrNotProjectMember
+		}
+		return domain.ErrAdminRequired
+	}
+	return nil
+}
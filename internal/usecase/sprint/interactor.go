@@ -0,0 +1,257 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+const (
+	defaultVelocitySprints = 5
+	maxVelocitySprints     = 20
+)
+
+type SprintInteractor struct {
+	sprintRepo domain.SprintRepository
+	location   *time.Location
+}
+
+// NewSprintInteractor: location — часовой пояс, по которому ряд burndown делится на дни.
+func NewSprintInteractor(sprintRepo domain.SprintRepository, location *time.Location) domain.SprintInteractor {
+	return &SprintInteractor{sprintRepo: sprintRepo, location: location}
+}
+
+func (si *SprintInteractor) CreateSprint(ctx context.Context, sprint *domain.Sprint) (*domain.Sprint, error) {
+	const op = "uc.sprint.create"
+	if err := sprint.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created, err := si.sprintRepo.CreateSprint(ctx, sprint)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+func (si *SprintInteractor) UpdateSprint(ctx context.Context, sprint *domain.Sprint) (*domain.Sprint, error) {
+	const op = "uc.sprint.update"
+	if _, err := si.sprint(ctx, sprint.ID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := sprint.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := si.sprintRepo.UpdateSprint(ctx, sprint); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return si.Sprint(ctx, sprint.ID)
+}
+
+// Sprint возвращает спринт вместе с его задачами.
+func (si *SprintInteractor) Sprint(ctx context.Context, id string) (*domain.Sprint, error) {
+	const op = "uc.sprint.get"
+	sprint, err := si.sprint(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	sprint.Tasks, err = si.sprintRepo.SprintTasks(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return sprint, nil
+}
+
+func (si *SprintInteractor) Sprints(ctx context.Context) ([]*domain.Sprint, error) {
+	const op = "uc.sprint.list"
+	sprints, err := si.sprintRepo.Sprints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return sprints, nil
+}
+
+// DeleteSprint удаляет спринт; его задачи остаются без спринта.
+func (si *SprintInteractor) DeleteSprint(ctx context.Context, id string) error {
+	const op = "uc.sprint.delete"
+	if _, err := si.sprint(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := si.sprintRepo.DeleteSprint(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (si *SprintInteractor) PlanTask(ctx context.Context, taskID string, sprintID string, estimate *float64, actorID string) (*domain.Task, error) {
+	const op = "uc.sprint.plan_task"
+	if estimate != nil && *estimate < 0 {
+		return nil, fmt.Errorf("%s: %w: estimate must not be negative", op, domain.ErrInvalidEstimate)
+	}
+	task, err := si.sprintRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := si.checkEdit(ctx, task, actorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if sprintID != "" {
+		if _, err := si.sprint(ctx, sprintID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := si.sprintRepo.PlanTask(ctx, taskID, sprintID, estimate); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	task, err = si.sprintRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return task, nil
+}
+
+// checkEdit проверяет, что пользователь может менять задачу проекта.
+func (si *SprintInteractor) checkEdit(ctx context.Context, task *domain.Task, actorID string) error {
+	if task.ProjectID == "" {
+		return nil
+	}
+	member, err := si.sprintRepo.ProjectMember(ctx, task.ProjectID, actorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
+
+// Burndown строит ряд по дням спринта от его начала до конца или текущего момента.
+// Состояние задач на конец каждого дня восстанавливается по истории переходов.
+func (si *SprintInteractor) Burndown(ctx context.Context, id string) (*domain.SprintBurn, error) {
+	const op = "uc.sprint.burndown"
+	sprint, err := si.Sprint(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	transitions, err := si.transitions(ctx, sprint.Tasks)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	days := si.dayEnds(sprint.StartAt, sprint.EndAt, time.Now())
+	return &domain.SprintBurn{
+		Sprint: sprint,
+		Points: domain.CalculateBurn(sprint.Tasks, transitions, sprint.StartAt, sprint.EndAt, days),
+	}, nil
+}
+
+// Velocity считает для завершившихся спринтов взятый объём и объём,
+// завершённый к концу спринта; Average — среднее завершённого.
+func (si *SprintInteractor) Velocity(ctx context.Context, limit int) (*domain.Velocity, error) {
+	const op = "uc.sprint.velocity"
+	if limit <= 0 {
+		limit = defaultVelocitySprints
+	}
+	limit = min(limit, maxVelocitySprints)
+	sprints, err := si.sprintRepo.FinishedSprints(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	velocity := &domain.Velocity{}
+	if len(sprints) == 0 {
+		return velocity, nil
+	}
+
+	ids := make([]string, 0, len(sprints))
+	for _, sprint := range sprints {
+		ids = append(ids, sprint.ID)
+	}
+	tasks, err := si.sprintRepo.SprintTasks(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	transitions, err := si.transitions(ctx, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, sprint := range sprints {
+		item := &domain.SprintVelocity{
+			SprintID: sprint.ID,
+			Name:     sprint.Name,
+			StartAt:  sprint.StartAt,
+			EndAt:    sprint.EndAt,
+			Unit:     sprint.Unit,
+		}
+		for _, task := range tasks {
+			if task.SprintID != sprint.ID {
+				continue
+			}
+			item.Committed += task.EstimateValue()
+			if domain.CompletedBy(task, transitions[task.ID], sprint.EndAt) {
+				item.Completed += task.EstimateValue()
+			}
+		}
+		velocity.Sprints = append(velocity.Sprints, item)
+		velocity.Average += item.Completed
+	}
+	velocity.Average /= float64(len(velocity.Sprints))
+	return velocity, nil
+}
+
+func (si *SprintInteractor) sprint(ctx context.Context, id string) (*domain.Sprint, error) {
+	sprint, err := si.sprintRepo.Sprint(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrSprintNotFound
+		}
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// transitions группирует переходы задач по id задачи, по возрастанию времени.
+func (si *SprintInteractor) transitions(ctx context.Context, tasks []*domain.Task) (map[string][]*domain.TaskTransition, error) {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	result := make(map[string][]*domain.TaskTransition, len(tasks))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	transitions, err := si.sprintRepo.TasksTransitions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, transition := range transitions {
+		result[transition.TaskID] = append(result[transition.TaskID], transition)
+	}
+	return result, nil
+}
+
+// dayEnds возвращает концы дней спринта; последний обрезается концом спринта,
+// дни после now не включаются.
+func (si *SprintInteractor) dayEnds(start time.Time, end time.Time, now time.Time) []time.Time {
+	local := start.In(si.location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, si.location)
+	var days []time.Time
+	for day.Before(end) && !day.After(now) {
+		next := day.AddDate(0, 0, 1)
+		point := next
+		if point.After(end) {
+			point = end
+		}
+		if point.After(now) {
+			point = now
+		}
+		days = append(days, point)
+		day = next
+	}
+	return days
+}
@@ -4,21 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
 	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
 )
 
 type TaskInteractor struct {
-	taskRepo domain.TaskRepository
+	taskRepo    domain.TaskRepository
+	userRepo    domain.UserRepository
+	transitions *domain.TransitionGraph
+	watchers    domain.WatchInteractor
 }
 
-func NewTaskInteractor(taskRepo domain.TaskRepository) domain.TaskInteractor {
-	return &TaskInteractor{taskRepo: taskRepo}
+func NewTaskInteractor(taskRepo domain.TaskRepository, userRepo domain.UserRepository, transitions *domain.TransitionGraph, watchers domain.WatchInteractor) domain.TaskInteractor {
+	return &TaskInteractor{taskRepo: taskRepo, userRepo: userRepo, transitions: transitions, watchers: watchers}
 }
 
-func (ai *TaskInteractor) Task(ctx context.Context, id string) (*domain.Task, error) {
+// Task возвращает задачу с прогрессом, связями и статьями. Задачу проекта
+// видят только его участники.
+func (ai *TaskInteractor) Task(ctx context.Context, id string, actorID string) (*domain.Task, error) {
 	const op = "uc.task.get"
 
 	task, err := ai.taskRepo.Task(ctx, id)
@@ -29,46 +36,406 @@ func (ai *TaskInteractor) Task(ctx context.Context, id string) (*domain.Task, er
 		return nil, fmt.Errorf("%s: %w", op, err)
 
 	}
+	if err := ai.access(ctx, task, actorID, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	task.Progress, err = ai.taskRepo.TaskProgress(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	task.Progress.Calculate(task.Status)
+	if task.BlockedBy, err = ai.taskRepo.TaskBlockers(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if task.Blocking, err = ai.taskRepo.TaskBlocking(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, blocker := range task.BlockedBy {
+		if blocker.Status != domain.Completed {
+			task.Blocked = true
+		}
+	}
+	if task.Articles, err = ai.taskRepo.TaskArticleLinks(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, link := range task.Articles {
+		link.Check()
+	}
 	return task, nil
 }
 
-func (ai *TaskInteractor) Tasks(ctx context.Context, page, limit int) ([]*domain.Task, error) {
+func (ai *TaskInteractor) TaskByKey(ctx context.Context, key string, actorID string) (*domain.Task, error) {
+	const op = "uc.task.by_key"
+	projectKey, number, err := domain.ParseTaskKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	task, err := ai.taskRepo.TaskByKey(ctx, projectKey, number)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ai.Task(ctx, task.ID, actorID)
+}
+
+// Tasks возвращает задачи без проекта и задачи проектов, в которых состоит actorID.
+func (ai *TaskInteractor) Tasks(ctx context.Context, actorID string, filter domain.TaskFilter) (*domain.TaskPage, error) {
 	const op = "uc.tast.all"
-	tasks, err := ai.taskRepo.Tasks(ctx, page, limit)
+	filter.ViewerID = actorID
+	if err := filter.Normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	// значения полей сравниваются в канонической форме, в которой они хранятся
+	for fieldID, value := range filter.Fields {
+		field, err := ai.taskRepo.Field(ctx, fieldID)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return nil, fmt.Errorf("%s: %w", op, domain.ErrFieldNotFound)
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if filter.Fields[fieldID], err = field.Normalize(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	tasks, total, err := ai.taskRepo.Tasks(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return &domain.TaskPage{
+		Tasks: tasks,
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Pages: (total + filter.Limit - 1) / filter.Limit,
+	}, nil
+}
+
+// MyTasks возвращает задачи, назначенные пользователю.
+func (ai *TaskInteractor) MyTasks(ctx context.Context, userID string, page, limit int) ([]*domain.Task, error) {
+	const op = "uc.task.mine"
+	tasks, err := ai.taskRepo.TasksByAssignee(ctx, userID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, nil
+}
+
+// AssignedByMe возвращает задачи, которые пользователь создал и назначил другим.
+func (ai *TaskInteractor) AssignedByMe(ctx context.Context, userID string, page, limit int) ([]*domain.Task, error) {
+	const op = "uc.task.assigned_by_me"
+	tasks, err := ai.taskRepo.TasksByCreator(ctx, userID, page, limit)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	return tasks, nil
 }
 
-func (ai *TaskInteractor) UpdateTask(ctx context.Context, id string, title string, image string, content string, userID string, priority domain.Priority, status domain.Status) error {
+// UpdateTask меняет поля задачи. Исполнитель и статус меняются только явно
+// (непустые значения) и с записью в соответствующие журналы.
+// Для вхождения повторяющейся задачи scope=future переносит изменения
+// в шаблон правила и на следующие открытые вхождения.
+func (ai *TaskInteractor) UpdateTask(ctx context.Context, id string, title string, image string, content string, planned_at time.Time, userID string, actorID string, priority domain.Priority, status domain.Status, scope domain.EditScope) error {
 	const op = "uc.task.update"
+	if !priority.Valid() {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidPriority)
+	}
+	if scope == "" {
+		scope = domain.ScopeThis
+	}
+	if !scope.Valid() {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidEditScope)
+	}
+	if status != "" && !status.Valid() {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidStatus)
+	}
+	current, err := ai.taskRepo.Task(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ai.access(ctx, current, actorID, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if scope == domain.ScopeFuture && current.RecurrenceID == "" {
+		return fmt.Errorf("%s: %w", op, domain.ErrNotRecurring)
+	}
+	if status != "" && status != current.Status {
+		if _, err := ai.TransitionTask(ctx, id, status, actorID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if userID != "" && userID != current.UserID {
+		if err := ai.AssignTask(ctx, id, userID, actorID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if planned_at.IsZero() {
+		planned_at = current.PlannedAt
+	}
 	task := domain.Task{
-		ID:       id,
-		Title:    title,
-		Image:    image,
-		Content:  content,
-		UserID:   userID,
-		Priority: priority,
-		Status:   status,
+		ID:        id,
+		Title:     title,
+		Image:     image,
+		Content:   content,
+		PlannedAt: planned_at,
+		Priority:  priority,
 	}
-	err := ai.taskRepo.UpdateTask(ctx, &task)
+	err = ai.taskRepo.UpdateTask(ctx, &task)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	if !planned_at.Equal(current.PlannedAt) {
+		ai.watchers.Publish(ctx, &domain.TaskEvent{
+			TaskID:  id,
+			ActorID: actorID,
+			Kind:    domain.NotifyDeadlineChanged,
+			Message: fmt.Sprintf("Срок задачи «%s» перенесён на %s.", task.Title, planned_at.Format("02.01.2006 15:04")),
+		})
+	}
+	if scope == domain.ScopeFuture {
+		if err := ai.updateFuture(ctx, current, &task, userID, actorID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
 	return nil
 }
 
-func (ai *TaskInteractor) DeleteTask(ctx context.Context, id string) error {
+// updateFuture переносит изменения вхождения в правило повторения и на уже
+// созданные следующие вхождения. Если сдвинута дата, расписание отсчитывается
+// от новой даты этого вхождения: оно становится слотом 0, уже созданные
+// вхождения после него — слотами 1, 2, ..., а следующее создаваемое — слотом за ними.
+func (ai *TaskInteractor) updateFuture(ctx context.Context, current *domain.Task, task *domain.Task, userID string, actorID string) error {
+	recurrence, err := ai.taskRepo.Recurrence(ctx, current.RecurrenceID)
+	if err != nil {
+		return err
+	}
+	recurrence.Title = task.Title
+	recurrence.Content = task.Content
+	recurrence.Image = task.Image
+	recurrence.Priority = task.Priority
+	// исполнителя следующих вхождений меняем, только если его задали явно
+	reassignedBy := ""
+	if userID != "" {
+		recurrence.UserID = userID
+		reassignedBy = actorID
+	}
+	reschedule := !task.PlannedAt.Equal(current.PlannedAt)
+	if reschedule {
+		recurrence.StartAt = task.PlannedAt
+		recurrence.Slot = recurrence.Occurrences - current.Occurrence + 1
+		recurrence.NextAt = recurrence.OccurrenceAt(recurrence.Slot)
+	}
+	return ai.taskRepo.UpdateFutureOccurrences(ctx, recurrence, current.Occurrence, reassignedBy, reschedule)
+}
+
+func (ai *TaskInteractor) AssignTask(ctx context.Context, id string, userID string, actorID string) error {
+	const op = "uc.task.assign"
+	current, err := ai.taskRepo.Task(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ai.access(ctx, current, actorID, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if current.UserID == userID {
+		return nil
+	}
+	user, err := ai.user(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// задачу проекта можно назначить только его участнику
+	if err := ai.access(ctx, current, userID, false); err != nil {
+		return fmt.Errorf("%s: %w: assignee", op, err)
+	}
+	assignment := domain.TaskAssignment{
+		TaskID:      id,
+		FromUserID:  current.UserID,
+		ToUserID:    userID,
+		ChangedByID: actorID,
+	}
+	if err := ai.taskRepo.ReassignTask(ctx, &assignment); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ai.watchers.Subscribe(ctx, id, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	ai.watchers.Publish(ctx, &domain.TaskEvent{
+		TaskID:  id,
+		ActorID: actorID,
+		Kind:    domain.NotifyReassigned,
+		Message: fmt.Sprintf("Задача «%s» переназначена на %s.", current.Title, user.Name),
+	})
+	return nil
+}
+
+func (ai *TaskInteractor) Assignments(ctx context.Context, id string, userID string) ([]*domain.TaskAssignment, error) {
+	const op = "uc.task.assignments"
+	if _, err := ai.readable(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	assignments, err := ai.taskRepo.TaskAssignments(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return assignments, nil
+}
+
+// TransitionTask переводит задачу в новый статус по графу переходов
+// и записывает переход в журнал вместе с автором.
+func (ai *TaskInteractor) TransitionTask(ctx context.Context, id string, status domain.Status, actorID string) (*domain.Task, error) {
+	const op = "uc.task.transition"
+	if !status.Valid() {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidStatus)
+	}
+	task, err := ai.taskRepo.Task(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ai.access(ctx, task, actorID, true); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if task.Status == status {
+		return task, nil
+	}
+	actor, err := ai.userRepo.User(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !ai.transitions.Allowed(task.Status, status, actor.IsAdmin) {
+		return nil, fmt.Errorf("%s: %s -> %s: %w", op, task.Status, status, domain.ErrTransitionNotAllowed)
+	}
+	if err := ai.transitions.CheckGuards(ctx, ai.taskRepo, id, status); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	transition := domain.TaskTransition{
+		TaskID: id,
+		From:   task.Status,
+		To:     status,
+		UserID: actorID,
+	}
+	task.ApplyStatus(status, time.Now())
+	if err := ai.taskRepo.TransitionTask(ctx, task, &transition); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ai.watchers.Publish(ctx, &domain.TaskEvent{
+		TaskID:  id,
+		ActorID: actorID,
+		Kind:    domain.NotifyStatusChanged,
+		Message: fmt.Sprintf("Статус задачи «%s» изменён: %s → %s.", task.Title, transition.From, transition.To),
+	})
+	return task, nil
+}
+
+func (ai *TaskInteractor) Transitions(ctx context.Context, id string, userID string) ([]*domain.TaskTransition, error) {
+	const op = "uc.task.transitions"
+	if _, err := ai.readable(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	transitions, err := ai.taskRepo.TaskTransitions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return transitions, nil
+}
+
+// SetParent делает задачу подзадачей parentID. Пустой parentID выносит задачу на верхний уровень.
+// Вложить задачу в саму себя, в собственную подзадачу или в задачу другого проекта нельзя.
+func (ai *TaskInteractor) SetParent(ctx context.Context, id string, parentID string, actorID string) error {
+	const op = "uc.task.set_parent"
+	task, err := ai.taskRepo.Task(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ai.access(ctx, task, actorID, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if parentID != "" {
+		parent, err := ai.parent(ctx, parentID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err := ai.access(ctx, parent, actorID, true); err != nil {
+			return fmt.Errorf("%s: parent: %w", op, err)
+		}
+		if parent.ProjectID != task.ProjectID {
+			return fmt.Errorf("%s: %w: parent belongs to another project", op, domain.ErrInvalidProject)
+		}
+	}
+	ok, err := ai.taskRepo.SetTaskParent(ctx, id, parentID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s: %w", op, domain.ErrTaskCycle)
+	}
+	return nil
+}
+
+// Subtasks возвращает непосредственные подзадачи.
+func (ai *TaskInteractor) Subtasks(ctx context.Context, id string, userID string) ([]*domain.Task, error) {
+	const op = "uc.task.subtasks"
+	if _, err := ai.readable(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	tasks, err := ai.taskRepo.Subtasks(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, nil
+}
+
+func (ai *TaskInteractor) DeleteTask(ctx context.Context, id string, actorID string) error {
 	const op = "uc.task.delete"
-	err := ai.taskRepo.DeleteTask(ctx, id)
+	task, err := ai.taskRepo.Task(ctx, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	if err := ai.access(ctx, task, actorID, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := ai.taskRepo.DeleteTask(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 	return nil
 }
 
-func (ai *TaskInteractor) CreateTask(ctx context.Context, title string, image string, content string, planned_at time.Time, userID string, priority domain.Priority, status domain.Status) (string, error) {
+// access проверяет доступ пользователя к задаче: задачи без проекта доступны
+// всем, задачи проекта видят его участники, а менять (edit) — участники
+// с правом редактирования.
+func (ai *TaskInteractor) access(ctx context.Context, task *domain.Task, userID string, edit bool) error {
+	if task.ProjectID == "" {
+		return nil
+	}
+	member, err := ai.taskRepo.ProjectMember(ctx, task.ProjectID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if edit && !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
+
+// readable загружает задачу, которую пользователь может видеть.
+func (ai *TaskInteractor) readable(ctx context.Context, id string, userID string) (*domain.Task, error) {
+	task, err := ai.taskRepo.Task(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ai.access(ctx, task, userID, false); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// CreateTask создаёт задачу. Если исполнитель не указан, задача назначается создателю.
+// Создатель и исполнитель подписываются на задачу. Задача проекта (projectID или проект родителя)
+// создаётся участником с правом редактирования, назначается участнику проекта
+// и получает следующий номер проекта.
+func (ai *TaskInteractor) CreateTask(ctx context.Context, title string, image string, content string, planned_at time.Time, userID string, creatorID string, parentID string, projectID string, priority domain.Priority, status domain.Status) (string, error) {
 	const op = "uc.task.create"
 	task := domain.Task{
 		Title:     title,
@@ -76,14 +443,479 @@ func (ai *TaskInteractor) CreateTask(ctx context.Context, title string, image st
 		Content:   content,
 		PlannedAt: planned_at,
 		UserID:    userID,
+		CreatorID: creatorID,
+		ParentID:  parentID,
+		ProjectID: projectID,
 		Priority:  priority,
 		Status:    status,
 	}
+	if err := ai.prepare(ctx, &task); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if task.ProjectID != "" {
+		// номер резервируется до создания: при ошибке создания в нумерации останется пропуск
+		var err error
+		if task.Number, err = ai.taskRepo.NextTaskNumber(ctx, task.ProjectID); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	task.ApplyStatus(task.Status, time.Now())
+	// Новая задача встаёт в конец своей колонки на доске.
+	lastRank, err := ai.taskRepo.LastRank(ctx, task.ProjectID, task.Status)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if task.Rank, err = lib.RankBetween(lastRank, ""); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
 
 	taskID, err := ai.taskRepo.CreateTask(ctx, &task)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
+	if err := ai.watchers.Subscribe(ctx, taskID, task.CreatorID, task.UserID); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
 	return taskID, nil
 
 }
+
+// CreateTasks проверяет все задачи пакета до записи и создаёт их одной
+// транзакцией. Подзадача пакета наследует проект родителя.
+func (ai *TaskInteractor) CreateTasks(ctx context.Context, creatorID string, drafts []*domain.TaskDraft) ([]string, error) {
+	const op = "uc.task.create_batch"
+	now := time.Now()
+	// задачи встают в конец колонок своих досок в порядке пакета
+	type column struct {
+		projectID string
+		status    domain.Status
+	}
+	ranks := make(map[column]string)
+	for i, draft := range drafts {
+		task := &draft.Task
+		task.CreatorID = creatorID
+		if draft.Parent != nil {
+			if task.ProjectID == "" {
+				task.ProjectID = draft.Parent.Task.ProjectID
+			}
+			if task.ProjectID != draft.Parent.Task.ProjectID {
+				return nil, fmt.Errorf("%s: task %d: %w: parent belongs to another project", op, i+1, domain.ErrInvalidProject)
+			}
+		}
+		if err := ai.prepare(ctx, task); err != nil {
+			return nil, fmt.Errorf("%s: task %d: %w", op, i+1, err)
+		}
+		task.ApplyStatus(task.Status, now)
+		var err error
+		key := column{task.ProjectID, task.Status}
+		lastRank, ok := ranks[key]
+		if !ok {
+			if lastRank, err = ai.taskRepo.LastRank(ctx, task.ProjectID, task.Status); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		if task.Rank, err = lib.RankBetween(lastRank, ""); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		ranks[key] = task.Rank
+	}
+	ids, err := ai.taskRepo.CreateTasks(ctx, drafts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ids, nil
+}
+
+// prepare проверяет новую задачу и дополняет её значениями по умолчанию:
+// статусом PENDING, создателем в роли исполнителя и проектом родителя.
+// Другой начальный статус должен быть достижим из PENDING по графу переходов.
+func (ai *TaskInteractor) prepare(ctx context.Context, task *domain.Task) error {
+	if task.Status == "" {
+		task.Status = domain.Pending
+	}
+	if !task.Status.Valid() {
+		return domain.ErrInvalidStatus
+	}
+	if task.Status != domain.Pending {
+		creator, err := ai.user(ctx, task.CreatorID)
+		if err != nil {
+			return err
+		}
+		if !ai.transitions.Allowed(domain.Pending, task.Status, creator.IsAdmin) {
+			return fmt.Errorf("%s -> %s: %w", domain.Pending, task.Status, domain.ErrTransitionNotAllowed)
+		}
+	}
+	if !task.Priority.Valid() {
+		return domain.ErrInvalidPriority
+	}
+	if task.UserID == "" {
+		task.UserID = task.CreatorID
+	}
+	if _, err := ai.user(ctx, task.UserID); err != nil {
+		return err
+	}
+	if task.ParentID != "" {
+		parent, err := ai.parent(ctx, task.ParentID)
+		if err != nil {
+			return err
+		}
+		if task.ProjectID == "" {
+			task.ProjectID = parent.ProjectID
+		}
+		if parent.ProjectID != task.ProjectID {
+			return fmt.Errorf("%w: parent belongs to another project", domain.ErrInvalidProject)
+		}
+	}
+	if task.ProjectID != "" {
+		return ai.checkProject(ctx, task.ProjectID, task.CreatorID, task.UserID)
+	}
+	return nil
+}
+
+// CreateFromTemplate создаёт задачи по шаблону одной транзакцией. Срок основной
+// задачи — PlannedAt запроса или смещение шаблона от текущего момента, сроки
+// шагов отсчитываются от срока основной задачи.
+func (ai *TaskInteractor) CreateFromTemplate(ctx context.Context, req *domain.TemplateRequest) ([]string, error) {
+	const op = "uc.task.create_from_template"
+	template, err := ai.taskRepo.Template(ctx, req.TemplateID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrTemplateNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if req.ProjectID == "" {
+		req.ProjectID = template.ProjectID
+	}
+	if template.ProjectID != "" && template.ProjectID != req.ProjectID {
+		return nil, fmt.Errorf("%s: %w: template belongs to another project", op, domain.ErrInvalidTemplate)
+	}
+	now := time.Now()
+	plannedAt := req.PlannedAt
+	if plannedAt.IsZero() {
+		plannedAt = now.Add(time.Duration(template.DueOffsetHours) * time.Hour)
+	}
+	root, err := templateDraft(template, req, now, plannedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	root.Task.ParentID = req.ParentID
+	drafts := []*domain.TaskDraft{root}
+	for _, step := range template.Steps {
+		draft, err := templateDraft(step, req, now, plannedAt.Add(time.Duration(step.DueOffsetHours)*time.Hour))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		draft.Parent = root
+		drafts = append(drafts, draft)
+	}
+	ids, err := ai.CreateTasks(ctx, req.CreatorID, drafts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ids, nil
+}
+
+// templateDraft собирает задачу по шаблону с чек-листом.
+func templateDraft(template *domain.TaskTemplate, req *domain.TemplateRequest, now time.Time, plannedAt time.Time) (*domain.TaskDraft, error) {
+	title, err := template.Title(req.Params, now)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TaskDraft{
+		Task: domain.Task{
+			Title:     title,
+			Content:   template.Content,
+			PlannedAt: plannedAt,
+			UserID:    req.UserID,
+			ProjectID: req.ProjectID,
+			Priority:  template.Priority,
+			Status:    domain.Pending,
+		},
+		Checklist: template.Checklist,
+	}, nil
+}
+
+func (ai *TaskInteractor) user(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := ai.userRepo.User(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (ai *TaskInteractor) parent(ctx context.Context, parentID string) (*domain.Task, error) {
+	parent, err := ai.taskRepo.Task(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrParentNotFound
+		}
+		return nil, err
+	}
+	return parent, nil
+}
+
+// checkProject проверяет, что автор может создавать задачи в проекте,
+// а исполнитель состоит в нём.
+func (ai *TaskInteractor) checkProject(ctx context.Context, projectID string, creatorID string, userID string) error {
+	if _, err := ai.taskRepo.Project(ctx, projectID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrProjectNotFound
+		}
+		return err
+	}
+	creator, err := ai.taskRepo.ProjectMember(ctx, projectID, creatorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if !creator.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	if _, err := ai.taskRepo.ProjectMember(ctx, projectID, userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return fmt.Errorf("%w: assignee", domain.ErrNotProjectMember)
+		}
+		return err
+	}
+	return nil
+}
+
+// BulkUpdate применяет операцию к набору задач. Каждая задача проверяется
+// отдельно: не прошедшие проверку попадают в результат с ошибкой и пропускаются,
+// остальные изменяются одной транзакцией. Задача, статус которой успели сменить
+// после проверки, не меняется и получает ошибку ErrTaskChanged.
+func (ai *TaskInteractor) BulkUpdate(ctx context.Context, actorID string, req *domain.BulkRequest) (*domain.BulkResult, error) {
+	const op = "uc.task.bulk"
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ids, tasks, err := ai.bulkTasks(ctx, actorID, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	check, err := ai.bulkCheck(ctx, actorID, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := &domain.BulkResult{Operation: req.Operation}
+	var changes []*domain.BulkChange
+	var changed []*domain.BulkItemResult
+	for _, id := range ids {
+		item := &domain.BulkItemResult{TaskID: id}
+		result.Items = append(result.Items, item)
+		task, ok := tasks[id]
+		if !ok {
+			item.Error = "task not found"
+			result.Failed++
+			continue
+		}
+		change, err := check(ctx, task)
+		if err != nil {
+			item.Error = err.Error()
+			result.Failed++
+			continue
+		}
+		if change != nil {
+			changes = append(changes, change)
+			changed = append(changed, item)
+		}
+	}
+	var conflicts []string
+	if len(changes) > 0 {
+		if conflicts, err = ai.taskRepo.ApplyBulk(ctx, req.Operation, changes); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	for i, item := range changed {
+		if slices.Contains(conflicts, item.TaskID) {
+			item.Error = domain.ErrTaskChanged.Error()
+			result.Failed++
+			continue
+		}
+		item.Changed = true
+		result.Applied++
+		if event := changes[i].Event; event != nil {
+			ai.watchers.Publish(ctx, event)
+		}
+	}
+	return result, nil
+}
+
+// bulkTasks загружает задачи запроса по id или по фильтру и возвращает
+// порядок результатов: как в запросе или как в выборке по фильтру.
+func (ai *TaskInteractor) bulkTasks(ctx context.Context, actorID string, req *domain.BulkRequest) ([]string, map[string]*domain.Task, error) {
+	var ids []string
+	var tasks []*domain.Task
+	if len(req.IDs) > 0 {
+		seen := make(map[string]bool, len(req.IDs))
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		var err error
+		if tasks, err = ai.taskRepo.TasksByIDs(ctx, ids); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		filter := *req.Filter
+		filter.Page = 1
+		filter.Limit = domain.MaxBulkTasks
+		page, err := ai.Tasks(ctx, actorID, filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		if page.Total > domain.MaxBulkTasks {
+			return nil, nil, fmt.Errorf("%w: filter matches %d tasks, limit is %d", domain.ErrBulkTooLarge, page.Total, domain.MaxBulkTasks)
+		}
+		tasks = page.Tasks
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+	}
+	byID := make(map[string]*domain.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	return ids, byID, nil
+}
+
+// bulkCheck готовит проверку одной задачи для операции запроса. Проверка
+// возвращает nil без ошибки, если задача уже в нужном состоянии.
+func (ai *TaskInteractor) bulkCheck(ctx context.Context, actorID string, req *domain.BulkRequest) (func(context.Context, *domain.Task) (*domain.BulkChange, error), error) {
+	actor, err := ai.user(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	roles := map[string]*domain.ProjectMembership{}
+	// canEdit проверяет право автора менять задачи проекта; роли кешируются на время запроса.
+	canEdit := func(ctx context.Context, projectID string, userID string) (bool, error) {
+		if projectID == "" {
+			return true, nil
+		}
+		key := projectID + "/" + userID
+		member, ok := roles[key]
+		if !ok {
+			var err error
+			member, err = ai.taskRepo.ProjectMember(ctx, projectID, userID)
+			if err != nil && !errors.Is(err, db.ErrNotFound) {
+				return false, err
+			}
+			roles[key] = member
+		}
+		return member != nil && member.Role.CanEdit(), nil
+	}
+	editable := func(ctx context.Context, task *domain.Task) error {
+		ok, err := canEdit(ctx, task.ProjectID, actorID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrProjectRole
+		}
+		return nil
+	}
+
+	switch req.Operation {
+	case domain.BulkStatus:
+		return func(ctx context.Context, task *domain.Task) (*domain.BulkChange, error) {
+			if err := editable(ctx, task); err != nil {
+				return nil, err
+			}
+			if task.Status == req.Status {
+				return nil, nil
+			}
+			if !ai.transitions.Allowed(task.Status, req.Status, actor.IsAdmin) {
+				return nil, fmt.Errorf("%s -> %s: %w", task.Status, req.Status, domain.ErrTransitionNotAllowed)
+			}
+			if err := ai.transitions.CheckGuards(ctx, ai.taskRepo, task.ID, req.Status); err != nil {
+				return nil, err
+			}
+			transition := &domain.TaskTransition{TaskID: task.ID, From: task.Status, To: req.Status, UserID: actorID}
+			task.ApplyStatus(req.Status, time.Now())
+			return &domain.BulkChange{Task: task, Transition: transition, Event: &domain.TaskEvent{
+				TaskID:  task.ID,
+				ActorID: actorID,
+				Kind:    domain.NotifyStatusChanged,
+				Message: fmt.Sprintf("Статус задачи «%s» изменён: %s → %s.", task.Title, transition.From, transition.To),
+			}}, nil
+		}, nil
+	case domain.BulkPriority:
+		return func(ctx context.Context, task *domain.Task) (*domain.BulkChange, error) {
+			if err := editable(ctx, task); err != nil {
+				return nil, err
+			}
+			if task.Priority == req.Priority {
+				return nil, nil
+			}
+			task.Priority = req.Priority
+			return &domain.BulkChange{Task: task}, nil
+		}, nil
+	case domain.BulkAssign:
+		assignee, err := ai.user(ctx, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, task *domain.Task) (*domain.BulkChange, error) {
+			if err := editable(ctx, task); err != nil {
+				return nil, err
+			}
+			if task.UserID == req.UserID {
+				return nil, nil
+			}
+			if task.ProjectID != "" {
+				if _, err := ai.taskRepo.ProjectMember(ctx, task.ProjectID, req.UserID); err != nil {
+					if errors.Is(err, db.ErrNotFound) {
+						return nil, fmt.Errorf("%w: assignee", domain.ErrNotProjectMember)
+					}
+					return nil, err
+				}
+			}
+			assignment := &domain.TaskAssignment{TaskID: task.ID, FromUserID: task.UserID, ToUserID: req.UserID, ChangedByID: actorID}
+			task.UserID = req.UserID
+			return &domain.BulkChange{Task: task, Assignment: assignment, Event: &domain.TaskEvent{
+				TaskID:  task.ID,
+				ActorID: actorID,
+				Kind:    domain.NotifyReassigned,
+				Message: fmt.Sprintf("Задача «%s» переназначена на %s.", task.Title, assignee.Name),
+			}}, nil
+		}, nil
+	case domain.BulkAddLabel:
+		label, err := ai.taskRepo.Label(ctx, req.LabelID)
+		if err != nil {
+			if errors.Is(err, db.ErrNotFound) {
+				return nil, domain.ErrLabelNotFound
+			}
+			return nil, err
+		}
+		return func(ctx context.Context, task *domain.Task) (*domain.BulkChange, error) {
+			if err := editable(ctx, task); err != nil {
+				return nil, err
+			}
+			if label.ProjectID != "" && label.ProjectID != task.ProjectID {
+				return nil, fmt.Errorf("%w: label belongs to another project", domain.ErrInvalidLabel)
+			}
+			for _, current := range task.Labels {
+				if current.ID == label.ID {
+					return nil, nil
+				}
+			}
+			return &domain.BulkChange{Task: task, LabelID: label.ID}, nil
+		}, nil
+	default:
+		return func(ctx context.Context, task *domain.Task) (*domain.BulkChange, error) {
+			if err := editable(ctx, task); err != nil {
+				return nil, err
+			}
+			return &domain.BulkChange{Task: task}, nil
+		}, nil
+	}
+}
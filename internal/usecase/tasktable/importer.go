@@ -0,0 +1,366 @@
+package tasktable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+// tableColumns — индексы столбцов загружаемого файла.
+type tableColumns struct {
+	index map[string]int
+	// fields — имя пользовательского поля → индекс столбца.
+	fields map[string]int
+}
+
+// parseHeader разбирает заголовок. Неизвестный столбец — ошибка, столбцы
+// выгрузки, которые не загружаются (key, created_at и т. п.), пропускаются.
+func parseHeader(header []string) (*tableColumns, error) {
+	columns := &tableColumns{index: make(map[string]int), fields: make(map[string]int)}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if name == "" {
+			continue
+		}
+		if field, ok := strings.CutPrefix(name, domain.FieldColumnPrefix); ok {
+			field = strings.TrimSpace(field)
+			if _, dup := columns.fields[field]; dup {
+				return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidImport, name)
+			}
+			columns.fields[field] = i
+			continue
+		}
+		name = strings.ToLower(name)
+		switch {
+		case slices.Contains(domain.TaskImportColumns, name):
+			if _, dup := columns.index[name]; dup {
+				return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidImport, name)
+			}
+			columns.index[name] = i
+		case slices.Contains(domain.TaskExportColumns, name):
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrInvalidImport, name)
+		}
+	}
+	for _, name := range []string{"title", "planned_at"} {
+		if _, ok := columns.index[name]; !ok {
+			return nil, fmt.Errorf("%w: column %q is required", domain.ErrInvalidImport, name)
+		}
+	}
+	return columns, nil
+}
+
+func cell(values []string, index int) string {
+	if index >= len(values) {
+		return ""
+	}
+	return strings.TrimSpace(values[index])
+}
+
+func (c *tableColumns) value(values []string, name string) string {
+	index, ok := c.index[name]
+	if !ok {
+		return ""
+	}
+	return cell(values, index)
+}
+
+// importRow — проверенная строка файла.
+type importRow struct {
+	number   int
+	task     domain.Task
+	labelIDs []string
+	fields   map[string]string
+}
+
+// importer проверяет строки одного файла. Справочники кэшируются на время
+// загрузки; nil в кэше — значение не найдено.
+type importer struct {
+	repo     domain.TaskTableRepository
+	actorID  string
+	opts     domain.ImportOptions
+	columns  *tableColumns
+	users    map[string]*domain.User
+	projects map[string]*domain.Project
+	members  map[string]*domain.ProjectMembership
+	labels   map[string]*domain.Label
+	fields   map[string]*domain.CustomField
+}
+
+func newImporter(repo domain.TaskTableRepository, actorID string, opts domain.ImportOptions, columns *tableColumns) *importer {
+	return &importer{
+		repo:     repo,
+		actorID:  actorID,
+		opts:     opts,
+		columns:  columns,
+		users:    make(map[string]*domain.User),
+		projects: make(map[string]*domain.Project),
+		members:  make(map[string]*domain.ProjectMembership),
+		labels:   make(map[string]*domain.Label),
+		fields:   make(map[string]*domain.CustomField),
+	}
+}
+
+// row проверяет строку number и собирает по ней задачу. Ошибки значений
+// возвращаются списком, error — только сбой при обращении к хранилищу.
+func (im *importer) row(ctx context.Context, number int, values []string) (*importRow, []*domain.ImportRowError, error) {
+	var rowErrors []*domain.ImportRowError
+	fail := func(column string, format string, args ...any) {
+		rowErrors = append(rowErrors, &domain.ImportRowError{Row: number, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+	row := &importRow{number: number}
+	task := &row.task
+
+	task.Title = im.columns.value(values, "title")
+	if n := utf8.RuneCountInString(task.Title); n < 3 || n > 50 {
+		fail("title", "title must be 3-50 characters")
+	}
+	task.Content = im.columns.value(values, "content")
+	task.Priority = domain.Priority(strings.ToUpper(im.columns.value(values, "priority")))
+	if task.Priority == "" {
+		task.Priority = domain.Middle
+	}
+	if !task.Priority.Valid() {
+		fail("priority", "priority must be one of %s, %s, %s", domain.High, domain.Middle, domain.Low)
+	}
+	task.Status = domain.Status(strings.ToUpper(im.columns.value(values, "status")))
+	if task.Status == "" {
+		task.Status = domain.Pending
+	}
+	if !task.Status.Valid() {
+		fail("status", "status must be one of %s, %s, %s", domain.Pending, domain.Current, domain.Completed)
+	}
+	plannedAt, err := im.parseTime(im.columns.value(values, "planned_at"))
+	if err != nil {
+		fail("planned_at", "%v", err)
+	}
+	task.PlannedAt = plannedAt
+
+	task.ProjectID = im.opts.ProjectID
+	projectOK := true
+	if key := im.columns.value(values, "project"); key != "" {
+		project, err := im.project(ctx, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if project == nil {
+			fail("project", "project %q not found", key)
+			projectOK = false
+		} else {
+			task.ProjectID = project.ID
+		}
+	}
+	task.UserID = im.actorID
+	assigneeOK := true
+	if login := im.columns.value(values, "assignee"); login != "" {
+		user, err := im.user(ctx, login)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user == nil {
+			fail("assignee", "user %q not found", login)
+			assigneeOK = false
+		} else {
+			task.UserID = user.ID
+		}
+	}
+	if task.ProjectID != "" && projectOK {
+		actor, err := im.member(ctx, task.ProjectID, im.actorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case actor == nil:
+			fail("project", "you are not a member of the project")
+		case !actor.Role.CanEdit():
+			fail("project", "project role %s does not allow creating tasks", actor.Role)
+		}
+		if assigneeOK && task.UserID != im.actorID {
+			assignee, err := im.member(ctx, task.ProjectID, task.UserID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if assignee == nil {
+				fail("assignee", "assignee is not a project member")
+			}
+		}
+	}
+
+	for _, name := range strings.Split(im.columns.value(values, "labels"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		label, err := im.label(ctx, task.ProjectID, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if label == nil {
+			fail("labels", "label %q not found", name)
+			continue
+		}
+		if !slices.Contains(row.labelIDs, label.ID) {
+			row.labelIDs = append(row.labelIDs, label.ID)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(im.columns.fields)) {
+		raw := cell(values, im.columns.fields[name])
+		if raw == "" {
+			continue
+		}
+		column := domain.FieldColumnPrefix + name
+		field, err := im.field(ctx, task.ProjectID, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if field == nil {
+			fail(column, "field %q not found", name)
+			continue
+		}
+		switch field.Type {
+		case domain.FieldUser:
+			user, err := im.user(ctx, raw)
+			if err != nil {
+				return nil, nil, err
+			}
+			if user == nil {
+				fail(column, "user %q not found", raw)
+				continue
+			}
+			raw = user.ID
+		case domain.FieldDate:
+			if t, err := im.parseTime(raw); err == nil {
+				raw = t.Format(time.DateOnly)
+			}
+		}
+		value, err := field.Normalize(raw)
+		if err != nil {
+			fail(column, "%v", err)
+			continue
+		}
+		if row.fields == nil {
+			row.fields = make(map[string]string)
+		}
+		row.fields[field.ID] = value
+	}
+	return row, rowErrors, nil
+}
+
+var tableTimeLayouts = []string{time.RFC3339, time.DateTime, "2006-01-02 15:04", time.DateOnly}
+
+// parseTime принимает RFC3339, дату со временем, дату YYYY-MM-DD,
+// а в XLSX — и дату Excel, записанную числом.
+func (im *importer) parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("value is required")
+	}
+	for _, layout := range tableTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	if im.opts.Format == domain.FormatXLSX {
+		if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+			return lib.XLSXDate(serial), nil
+		}
+	}
+	return time.Time{}, errors.New("expected RFC3339 or YYYY-MM-DD")
+}
+
+func (im *importer) user(ctx context.Context, login string) (*domain.User, error) {
+	if user, ok := im.users[login]; ok {
+		return user, nil
+	}
+	user, err := im.repo.UserByLogin(ctx, login)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	im.users[login] = user
+	return user, nil
+}
+
+func (im *importer) project(ctx context.Context, key string) (*domain.Project, error) {
+	key = strings.ToUpper(key)
+	if project, ok := im.projects[key]; ok {
+		return project, nil
+	}
+	project, err := im.repo.ProjectByKey(ctx, key)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	im.projects[key] = project
+	return project, nil
+}
+
+func (im *importer) member(ctx context.Context, projectID string, userID string) (*domain.ProjectMembership, error) {
+	cacheKey := projectID + "/" + userID
+	if member, ok := im.members[cacheKey]; ok {
+		return member, nil
+	}
+	member, err := im.repo.ProjectMember(ctx, projectID, userID)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	im.members[cacheKey] = member
+	return member, nil
+}
+
+// label ищет метку сначала среди меток проекта, затем среди общих.
+func (im *importer) label(ctx context.Context, projectID string, name string) (*domain.Label, error) {
+	cacheKey := projectID + "/" + name
+	if label, ok := im.labels[cacheKey]; ok {
+		return label, nil
+	}
+	var label *domain.Label
+	for _, scope := range scopes(projectID) {
+		found, err := im.repo.LabelByName(ctx, scope, name)
+		if err == nil {
+			label = found
+			break
+		}
+		if !errors.Is(err, db.ErrNotFound) {
+			return nil, err
+		}
+	}
+	im.labels[cacheKey] = label
+	return label, nil
+}
+
+// field ищет поле сначала среди полей проекта, затем среди общих.
+func (im *importer) field(ctx context.Context, projectID string, name string) (*domain.CustomField, error) {
+	cacheKey := projectID + "/" + name
+	if field, ok := im.fields[cacheKey]; ok {
+		return field, nil
+	}
+	var field *domain.CustomField
+	for _, scope := range scopes(projectID) {
+		found, err := im.repo.FieldByName(ctx, scope, name)
+		if err == nil {
+			field = found
+			break
+		}
+		if !errors.Is(err, db.ErrNotFound) {
+			return nil, err
+		}
+	}
+	im.fields[cacheKey] = field
+	return field, nil
+}
+
+// scopes — области поиска меток и полей: проект, затем общие.
+func scopes(projectID string) []string {
+	if projectID == "" {
+		return []string{""}
+	}
+	return []string{projectID, ""}
+}
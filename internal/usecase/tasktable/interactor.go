@@ -0,0 +1,282 @@
+package tasktable
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/internal/lib"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type TaskTableInteractor struct {
+	tasks     domain.TaskInteractor
+	tableRepo domain.TaskTableRepository
+}
+
+func NewTaskTableInteractor(tasks domain.TaskInteractor, tableRepo domain.TaskTableRepository) domain.TaskTableInteractor {
+	return &TaskTableInteractor{tasks: tasks, tableRepo: tableRepo}
+}
+
+// tableWriter — построчная запись таблицы в одном из форматов.
+type tableWriter interface {
+	WriteRow(values []string) error
+	Flush() error
+	Close() error
+}
+
+type csvWriter struct {
+	*csv.Writer
+}
+
+func (w csvWriter) WriteRow(values []string) error {
+	return w.Write(values)
+}
+
+func (w csvWriter) Flush() error {
+	w.Writer.Flush()
+	return w.Error()
+}
+
+func (w csvWriter) Close() error {
+	return w.Flush()
+}
+
+func newTableWriter(format domain.TableFormat, w io.Writer) (tableWriter, error) {
+	if format == domain.FormatXLSX {
+		return lib.NewXLSXWriter(w, "Tasks")
+	}
+	return csvWriter{csv.NewWriter(w)}, nil
+}
+
+// ExportTasks выгружает задачи страницами по MaxPageLimit и сбрасывает каждую
+// страницу в w. Столбцы полей — общие поля и поля проекта из фильтра.
+func (ti *TaskTableInteractor) ExportTasks(ctx context.Context, actorID string, filter domain.TaskFilter, format domain.TableFormat, w io.Writer) error {
+	const op = "uc.task_table.export"
+	if !format.Valid() {
+		return fmt.Errorf("%s: %w", op, domain.ErrInvalidTableFormat)
+	}
+	if filter.ProjectID != "" {
+		member, err := ti.member(ctx, filter.ProjectID, actorID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if member == nil {
+			return fmt.Errorf("%s: %w", op, domain.ErrNotProjectMember)
+		}
+	}
+	fields, err := ti.tableRepo.Fields(ctx, filter.ProjectID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	// первая страница запрашивается до начала записи, чтобы ошибка вернулась вместо файла
+	filter.Page, filter.Limit = 1, domain.MaxPageLimit
+	page, err := ti.tasks.Tasks(ctx, actorID, filter)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tw, err := newTableWriter(format, w)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	header := slices.Clone(domain.TaskExportColumns)
+	for _, field := range fields {
+		header = append(header, domain.FieldColumnPrefix+field.Name)
+	}
+	if err := tw.WriteRow(header); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	logins := make(map[string]string)
+	for {
+		for _, task := range page.Tasks {
+			row, err := ti.exportRow(ctx, task, fields, logins)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+			if err := tw.WriteRow(row); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if flusher, ok := w.(interface{ Flush() }); ok {
+			flusher.Flush()
+		}
+		if page.Page >= page.Pages {
+			break
+		}
+		filter.Page++
+		if page, err = ti.tasks.Tasks(ctx, actorID, filter); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// exportRow собирает строку задачи в порядке TaskExportColumns и полей.
+// Значения полей USER выгружаются логинами, logins кэширует их по id.
+func (ti *TaskTableInteractor) exportRow(ctx context.Context, task *domain.Task, fields []*domain.CustomField, logins map[string]string) ([]string, error) {
+	labels := make([]string, 0, len(task.Labels))
+	for _, label := range task.Labels {
+		labels = append(labels, label.Name)
+	}
+	row := []string{
+		task.Key,
+		task.Title,
+		string(task.Status),
+		string(task.Priority),
+		task.UserLogin,
+		task.ReliableUserName,
+		task.CreatorName,
+		formatTime(&task.PlannedAt),
+		formatTime(&task.CreatedAt),
+		formatTime(task.CompletedAt),
+		task.ProjectKey,
+		strings.Join(labels, ", "),
+		task.Content,
+	}
+	values := make(map[string]*domain.FieldValue, len(task.Fields))
+	for _, value := range task.Fields {
+		values[value.FieldID] = value
+	}
+	for _, field := range fields {
+		value, ok := values[field.ID]
+		if !ok {
+			row = append(row, "")
+			continue
+		}
+		if value.Type != domain.FieldUser {
+			row = append(row, value.Value)
+			continue
+		}
+		login, ok := logins[value.Value]
+		if !ok {
+			user, err := ti.tableRepo.User(ctx, value.Value)
+			if err != nil && !errors.Is(err, db.ErrNotFound) {
+				return nil, err
+			}
+			if user != nil {
+				login = user.Login
+			}
+			logins[value.Value] = login
+		}
+		row = append(row, login)
+	}
+	return row, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ImportTasks проверяет все строки файла и создаёт задачи, только если ошибок
+// нет. Задачи создаются через TaskInteractor одной транзакцией, чтобы работали
+// нумерация проекта и подписка наблюдателей, а сбой не оставлял часть загрузки.
+func (ti *TaskTableInteractor) ImportTasks(ctx context.Context, actorID string, file io.ReaderAt, size int64, opts domain.ImportOptions) (*domain.ImportResult, error) {
+	const op = "uc.task_table.import"
+	rows, err := readTable(file, size, opts.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: %w: file is empty", op, domain.ErrInvalidImport)
+	}
+	columns, err := parseHeader(rows[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	rows = rows[1:]
+	if len(rows) > domain.MaxImportRows {
+		return nil, fmt.Errorf("%s: %w: at most %d rows", op, domain.ErrInvalidImport, domain.MaxImportRows)
+	}
+
+	im := newImporter(ti.tableRepo, actorID, opts, columns)
+	result := &domain.ImportResult{DryRun: opts.DryRun}
+	parsed := make([]*importRow, 0, len(rows))
+	for i, values := range rows {
+		if blankRow(values) {
+			continue
+		}
+		result.Rows++
+		// строка 1 — заголовок, данные начинаются со второй
+		row, rowErrors, err := im.row(ctx, i+2, values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		result.Errors = append(result.Errors, rowErrors...)
+		if len(rowErrors) == 0 {
+			parsed = append(parsed, row)
+		}
+	}
+	if result.Rows == 0 {
+		return nil, fmt.Errorf("%s: %w: no task rows", op, domain.ErrInvalidImport)
+	}
+	if len(result.Errors) > 0 || opts.DryRun {
+		return result, nil
+	}
+
+	drafts := make([]*domain.TaskDraft, len(parsed))
+	for i, row := range parsed {
+		drafts[i] = &domain.TaskDraft{Task: row.task, LabelIDs: row.labelIDs, Fields: row.fields}
+	}
+	ids, err := ti.tasks.CreateTasks(ctx, actorID, drafts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	result.Created = len(ids)
+	result.TaskIDs = ids
+	return result, nil
+}
+
+// member возвращает участие пользователя в проекте или nil, если он не участник.
+func (ti *TaskTableInteractor) member(ctx context.Context, projectID string, userID string) (*domain.ProjectMembership, error) {
+	member, err := ti.tableRepo.ProjectMember(ctx, projectID, userID)
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, nil
+	}
+	return member, err
+}
+
+func readTable(file io.ReaderAt, size int64, format domain.TableFormat) ([][]string, error) {
+	switch format {
+	case domain.FormatCSV:
+		reader := csv.NewReader(io.NewSectionReader(file, 0, size))
+		reader.FieldsPerRecord = -1
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+		}
+		return rows, nil
+	case domain.FormatXLSX:
+		// строка заголовка идёт сверх MaxImportRows
+		rows, err := lib.ReadXLSX(file, size, domain.MaxImportRows+1, domain.MaxImportColumns)
+		if errors.Is(err, lib.ErrInvalidXLSX) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImport, err)
+		}
+		return rows, err
+	}
+	return nil, domain.ErrInvalidTableFormat
+}
+
+func blankRow(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
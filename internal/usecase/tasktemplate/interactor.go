@@ -0,0 +1,108 @@
+package tasktemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type TaskTemplateInteractor struct {
+	templateRepo domain.TaskTemplateRepository
+}
+
+func NewTaskTemplateInteractor(templateRepo domain.TaskTemplateRepository) domain.TaskTemplateInteractor {
+	return &TaskTemplateInteractor{templateRepo: templateRepo}
+}
+
+// CreateTemplate сохраняет шаблон. Шаблон проекта создаёт участник
+// с правом редактирования, общий — любой пользователь.
+func (ti *TaskTemplateInteractor) CreateTemplate(ctx context.Context, actorID string, template *domain.TaskTemplate) (*domain.TaskTemplate, error) {
+	const op = "uc.task_template.create"
+	if err := template.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if template.ProjectID != "" {
+		if err := ti.checkProject(ctx, template.ProjectID, actorID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	template.CreatedByID = actorID
+	created, err := ti.templateRepo.CreateTemplate(ctx, template)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+func (ti *TaskTemplateInteractor) Template(ctx context.Context, id string) (*domain.TaskTemplate, error) {
+	const op = "uc.task_template.get"
+	template, err := ti.template(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return template, nil
+}
+
+func (ti *TaskTemplateInteractor) Templates(ctx context.Context, projectID string) ([]*domain.TaskTemplate, error) {
+	const op = "uc.task_template.all"
+	templates, err := ti.templateRepo.Templates(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return templates, nil
+}
+
+// DeleteTemplate удаляет шаблон вместе с шагами. Шаблон проекта удаляет
+// участник с правом редактирования, общий — автор или администратор.
+func (ti *TaskTemplateInteractor) DeleteTemplate(ctx context.Context, actorID string, id string) error {
+	const op = "uc.task_template.delete"
+	template, err := ti.template(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if template.ProjectID != "" {
+		if err := ti.checkProject(ctx, template.ProjectID, actorID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	} else if template.CreatedByID != actorID {
+		actor, err := ti.templateRepo.User(ctx, actorID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if actor.IsAdmin != domain.AdminRole {
+			return fmt.Errorf("%s: %w", op, domain.ErrAdminRequired)
+		}
+	}
+	if err := ti.templateRepo.DeleteTemplate(ctx, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (ti *TaskTemplateInteractor) template(ctx context.Context, id string) (*domain.TaskTemplate, error) {
+	template, err := ti.templateRepo.Template(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func (ti *TaskTemplateInteractor) checkProject(ctx context.Context, projectID string, actorID string) error {
+	member, err := ti.templateRepo.ProjectMember(ctx, projectID, actorID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return domain.ErrNotProjectMember
+		}
+		return err
+	}
+	if !member.Role.CanEdit() {
+		return domain.ErrProjectRole
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package timetrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+// maxEntry — наибольшая длительность ручной записи.
+const maxEntry = 24 * time.Hour
+
+type TimeEntryInteractor struct {
+	timeRepo domain.TimeEntryRepository
+	location *time.Location
+}
+
+// NewTimeEntryInteractor: location — часовой пояс, по которому табель делится на дни и недели.
+func NewTimeEntryInteractor(timeRepo domain.TimeEntryRepository, location *time.Location) *TimeEntryInteractor {
+	return &TimeEntryInteractor{timeRepo: timeRepo, location: location}
+}
+
+// StartTimer запускает таймер по задаче. Второй запущенный таймер у пользователя невозможен.
+func (ti *TimeEntryInteractor) StartTimer(ctx context.Context, taskID string, userID string, note string) (*domain.TimeEntry, error) {
+	const op = "uc.time.start"
+	task, err := ti.task(ctx, taskID, userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	entry := domain.TimeEntry{
+		TaskID:    taskID,
+		TaskTitle: task.Title,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      strings.TrimSpace(note),
+		Running:   true,
+	}
+	started, err := ti.timeRepo.StartTimer(ctx, &entry)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !started {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrTimerRunning)
+	}
+	return &entry, nil
+}
+
+func (ti *TimeEntryInteractor) StopTimer(ctx context.Context, userID string) (*domain.TimeEntry, error) {
+	const op = "uc.time.stop"
+	entry, err := ti.timeRepo.StopTimer(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrNoRunningTimer)
+	}
+	return entry, nil
+}
+
+func (ti *TimeEntryInteractor) RunningTimer(ctx context.Context, userID string) (*domain.TimeEntry, error) {
+	const op = "uc.time.running"
+	entry, err := ti.timeRepo.RunningTimer(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrNoRunningTimer)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	elapse(entry, time.Now())
+	return entry, nil
+}
+
+// AddEntry добавляет завершённую запись вручную.
+func (ti *TimeEntryInteractor) AddEntry(ctx context.Context, taskID string, userID string, startedAt time.Time, seconds int, note string) (*domain.TimeEntry, error) {
+	const op = "uc.time.add"
+	duration := time.Duration(seconds) * time.Second
+	if seconds <= 0 || duration > maxEntry {
+		return nil, fmt.Errorf("%s: %w: duration must be between 1 second and %s", op, domain.ErrInvalidTimeEntry, maxEntry)
+	}
+	endedAt := startedAt.Add(duration)
+	if startedAt.IsZero() || endedAt.After(time.Now()) {
+		return nil, fmt.Errorf("%s: %w: entry must end in the past", op, domain.ErrInvalidTimeEntry)
+	}
+	if _, err := ti.task(ctx, taskID, userID, true); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	entry, err := ti.timeRepo.CreateTimeEntry(ctx, &domain.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Seconds:   seconds,
+		Note:      strings.TrimSpace(note),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entry, nil
+}
+
+// DeleteEntry удаляет собственную запись пользователя.
+func (ti *TimeEntryInteractor) DeleteEntry(ctx context.Context, userID string, id string) (*domain.TimeEntry, error) {
+	const op = "uc.time.delete"
+	entry, err := ti.timeRepo.TimeEntry(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrTimeEntryNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if entry.UserID != userID {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrTimeEntryForbidden)
+	}
+	if err := ti.timeRepo.DeleteTimeEntry(ctx, id); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entry, nil
+}
+
+// TaskEntries возвращает записи по задаче и итог по пользователям.
+func (ti *TimeEntryInteractor) TaskEntries(ctx context.Context, taskID string, userID string) ([]*domain.TimeEntry, *domain.TaskTimeTotal, error) {
+	const op = "uc.time.task"
+	if _, err := ti.task(ctx, taskID, userID, false); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	entries, err := ti.timeRepo.TaskTimeEntries(ctx, taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+	now := time.Now()
+	total := &domain.TaskTimeTotal{TaskID: taskID}
+	byUser := make(map[string]*domain.UserTime)
+	for _, entry := range entries {
+		elapse(entry, now)
+		user, ok := byUser[entry.UserID]
+		if !ok {
+			user = &domain.UserTime{UserID: entry.UserID, UserName: entry.UserName}
+			byUser[entry.UserID] = user
+			total.Users = append(total.Users, user)
+		}
+		user.Seconds += entry.Seconds
+		total.Seconds += entry.Seconds
+	}
+	slices.SortFunc(total.Users, func(a, b *domain.UserTime) int { return b.Seconds - a.Seconds })
+	return entries, total, nil
+}
+
+// Timesheet собирает табель за неделю (понедельник–воскресенье), содержащую day.
+// Запись относится к дню, в который она началась.
+func (ti *TimeEntryInteractor) Timesheet(ctx context.Context, userID string, day time.Time) (*domain.Timesheet, error) {
+	const op = "uc.time.timesheet"
+	day = day.In(ti.location)
+	weekday := (int(day.Weekday()) + 6) % 7
+	start := time.Date(day.Year(), day.Month(), day.Day()-weekday, 0, 0, 0, 0, ti.location)
+	end := start.AddDate(0, 0, 7)
+	entries, err := ti.timeRepo.UserTimeEntries(ctx, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	sheet := &domain.Timesheet{UserID: userID, WeekStart: start, Entries: entries}
+	rows := make(map[string]*domain.TimesheetRow)
+	for _, entry := range entries {
+		elapse(entry, now)
+		row, ok := rows[entry.TaskID]
+		if !ok {
+			row = &domain.TimesheetRow{TaskID: entry.TaskID, TaskTitle: entry.TaskTitle}
+			rows[entry.TaskID] = row
+			sheet.Rows = append(sheet.Rows, row)
+		}
+		index := (int(entry.StartedAt.In(ti.location).Weekday()) + 6) % 7
+		row.Days[index] += entry.Seconds
+		row.Seconds += entry.Seconds
+		sheet.Days[index] += entry.Seconds
+		sheet.Seconds += entry.Seconds
+	}
+	slices.SortFunc(sheet.Rows, func(a, b *domain.TimesheetRow) int { return strings.Compare(a.TaskTitle, b.TaskTitle) })
+	return sheet, nil
+}
+
+// TimesheetCSV выгружает табель: строка на задачу, часы по дням недели и итог.
+func (ti *TimeEntryInteractor) TimesheetCSV(ctx context.Context, userID string, day time.Time) ([]byte, error) {
+	const op = "uc.time.timesheet_csv"
+	sheet, err := ti.Timesheet(ctx, userID, day)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"task_id", "task"}
+	for i := range 7 {
+		header = append(header, sheet.WeekStart.AddDate(0, 0, i).Format(time.DateOnly))
+	}
+	w.Write(append(header, "total"))
+	for _, row := range sheet.Rows {
+		w.Write(append([]string{row.TaskID, row.TaskTitle}, hoursRow(row.Days, row.Seconds)...))
+	}
+	w.Write(append([]string{"", "total"}, hoursRow(sheet.Days, sheet.Seconds)...))
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// task загружает задачу и проверяет, что пользователь видит её проект,
+// а для edit — может менять задачи проекта.
+func (ti *TimeEntryInteractor) task(ctx context.Context, taskID string, userID string, edit bool) (*domain.Task, error) {
+	task, err := ti.timeRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID == "" {
+		return task, nil
+	}
+	member, err := ti.timeRepo.ProjectMember(ctx, task.ProjectID, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrNotProjectMember
+		}
+		return nil, err
+	}
+	if edit && !member.Role.CanEdit() {
+		return nil, domain.ErrProjectRole
+	}
+	return task, nil
+}
+
+func hoursRow(days [7]int, total int) []string {
+	cells := make([]string, 0, len(days)+1)
+	for _, seconds := range days {
+		cells = append(cells, hours(seconds))
+	}
+	return append(cells, hours(total))
+}
+
+func hours(seconds int) string {
+	return fmt.Sprintf("%.2f", float64(seconds)/3600)
+}
+
+// elapse досчитывает длительность запущенного таймера на момент now.
+func elapse(entry *domain.TimeEntry, now time.Time) {
+	if entry.Running {
+		entry.Seconds = int(now.Sub(entry.StartedAt) / time.Second)
+	}
+}
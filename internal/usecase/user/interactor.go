@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
@@ -17,16 +18,18 @@ var (
 )
 
 type UserInteractor struct {
-	userRepo  domain.UserRepository
-	tokenTTL  time.Duration
-	appSecret string
+	userRepo          domain.UserRepository
+	tokenTTL          time.Duration
+	appSecret         string
+	maxReminderOffset int
 }
 
-func NewUserInteractor(userRepo domain.UserRepository, tokenTTL time.Duration, appSecret string) *UserInteractor {
+func NewUserInteractor(userRepo domain.UserRepository, tokenTTL time.Duration, appSecret string, maxReminderOffset int) *UserInteractor {
 	return &UserInteractor{
-		userRepo:  userRepo,
-		tokenTTL:  tokenTTL,
-		appSecret: appSecret,
+		userRepo:          userRepo,
+		tokenTTL:          tokenTTL,
+		appSecret:         appSecret,
+		maxReminderOffset: maxReminderOffset,
 	}
 }
 
@@ -42,11 +45,11 @@ func (ui *UserInteractor) Login(ctx context.Context, login string, passhash stri
 	return lib.NewToken(user, ui.tokenTTL, ui.appSecret)
 
 }
-func (ui *UserInteractor) CreateUser(ctx context.Context, login string, name string, pass string) error {
+func (ui *UserInteractor) CreateUser(ctx context.Context, login string, name string, pass string) (string, error) {
 	const op = "uc.user.create"
 	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return "", fmt.Errorf("%s: %w", op, err)
 	}
 	user := domain.User{
 		Login:    login,
@@ -54,9 +57,9 @@ func (ui *UserInteractor) CreateUser(ctx context.Context, login string, name str
 		PassHash: passHash,
 	}
 	if err := ui.userRepo.CreateUser(ctx, &user); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return "", fmt.Errorf("%s: %w", op, err)
 	}
-	return nil
+	return user.ID, nil
 }
 
 func (ui *UserInteractor) User(ctx context.Context, id string) (*domain.User, error) {
@@ -106,3 +109,22 @@ func (ui *UserInteractor) DeleteUser(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// SetReminderOffsets сохраняет смещения напоминаний в часах (от 1 до maxReminderOffset).
+// Пустой список возвращает настройки по умолчанию.
+func (ui *UserInteractor) SetReminderOffsets(ctx context.Context, id string, offsets []int) error {
+	const op = "uc.user.reminders"
+	for _, offset := range offsets {
+		if offset < 1 || offset > ui.maxReminderOffset {
+			return fmt.Errorf("%s: %w: offset %d is out of 1..%d hours", op, domain.ErrInvalidReminderOffsets, offset, ui.maxReminderOffset)
+		}
+	}
+	offsets = slices.Clone(offsets)
+	slices.Sort(offsets)
+	offsets = slices.Compact(offsets)
+	slices.Reverse(offsets)
+	if err := ui.userRepo.SetReminderOffsets(ctx, id, offsets); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
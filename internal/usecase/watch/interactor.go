@@ -0,0 +1,171 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
+)
+
+type WatchInteractor struct {
+	watchRepo     domain.WatchRepository
+	notifications domain.NotificationInteractor
+	log           *slog.Logger
+}
+
+func NewWatchInteractor(watchRepo domain.WatchRepository, notifications domain.NotificationInteractor, log *slog.Logger) *WatchInteractor {
+	return &WatchInteractor{
+		watchRepo:     watchRepo,
+		notifications: notifications,
+		log:           log,
+	}
+}
+
+// Watch подписывает пользователя на задачу. На задачу проекта может
+// подписаться только участник проекта.
+func (wi *WatchInteractor) Watch(ctx context.Context, taskID string, userID string) error {
+	const op = "uc.watch.watch"
+	if _, err := wi.task(ctx, taskID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := wi.watchRepo.AddWatchers(ctx, taskID, []string{userID}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (wi *WatchInteractor) Unwatch(ctx context.Context, taskID string, userID string) error {
+	const op = "uc.watch.unwatch"
+	if err := wi.watchRepo.RemoveWatcher(ctx, taskID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (wi *WatchInteractor) Watchers(ctx context.Context, taskID string) ([]*domain.TaskWatcher, error) {
+	const op = "uc.watch.watchers"
+	watchers, err := wi.watchRepo.Watchers(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return watchers, nil
+}
+
+func (wi *WatchInteractor) AddComment(ctx context.Context, taskID string, authorID string, body string) (*domain.TaskComment, error) {
+	const op = "uc.watch.comment"
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > domain.MaxCommentLength {
+		return nil, fmt.Errorf("%s: %w: body must be 1-%d characters", op, domain.ErrInvalidComment, domain.MaxCommentLength)
+	}
+	task, err := wi.task(ctx, taskID, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	comment, err := wi.watchRepo.CreateComment(ctx, &domain.TaskComment{
+		TaskID:   taskID,
+		AuthorID: authorID,
+		Body:     body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := wi.Subscribe(ctx, taskID, authorID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	wi.Publish(ctx, &domain.TaskEvent{
+		TaskID:  taskID,
+		ActorID: authorID,
+		Kind:    domain.NotifyComment,
+		Message: fmt.Sprintf("%s прокомментировал задачу «%s»: %s", comment.AuthorName, task.Title, preview(body)),
+	})
+	return comment, nil
+}
+
+func (wi *WatchInteractor) Comments(ctx context.Context, taskID string) ([]*domain.TaskComment, error) {
+	const op = "uc.watch.comments"
+	comments, err := wi.watchRepo.Comments(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return comments, nil
+}
+
+func (wi *WatchInteractor) Subscribe(ctx context.Context, taskID string, userIDs ...string) error {
+	const op = "uc.watch.subscribe"
+	var ids []string
+	for _, userID := range userIDs {
+		if userID != "" {
+			ids = append(ids, userID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := wi.watchRepo.AddWatchers(ctx, taskID, ids); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Publish не возвращает ошибку: изменение задачи уже сохранено,
+// и сбой рассылки не должен его отменять.
+func (wi *WatchInteractor) Publish(ctx context.Context, event *domain.TaskEvent) {
+	watchers, err := wi.watchRepo.Watchers(ctx, event.TaskID)
+	if err != nil {
+		wi.log.Error("failed to get task watchers",
+			slog.String("task", event.TaskID),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	for _, watcher := range watchers {
+		if watcher.UserID == event.ActorID {
+			continue
+		}
+		err := wi.notifications.Send(ctx, &domain.Notification{
+			UserID:  watcher.UserID,
+			Kind:    event.Kind,
+			TaskID:  event.TaskID,
+			Message: event.Message,
+		})
+		if err != nil {
+			wi.log.Error("failed to send notification",
+				slog.String("task", event.TaskID),
+				slog.String("kind", string(event.Kind)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// task загружает задачу и проверяет, что пользователь может её видеть.
+func (wi *WatchInteractor) task(ctx context.Context, taskID string, userID string) (*domain.Task, error) {
+	task, err := wi.watchRepo.Task(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ProjectID == "" {
+		return task, nil
+	}
+	if _, err := wi.watchRepo.ProjectMember(ctx, task.ProjectID, userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, domain.ErrNotProjectMember
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+// preview обрезает комментарий для текста уведомления.
+func preview(body string) string {
+	const limit = 200
+	if utf8.RuneCountInString(body) <= limit {
+		return body
+	}
+	return string([]rune(body)[:limit]) + "…"
+}
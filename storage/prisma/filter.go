@@ -0,0 +1,111 @@
+package prisma
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/immxrtalbeast/TTK_backend/internal/domain"
+)
+
+// sqlBuilder собирает WHERE-условие с позиционными параметрами Postgres.
+type sqlBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg добавляет параметр и возвращает его плейсхолдер ($n).
+func (b *sqlBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *sqlBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+func (b *sqlBuilder) whereSQL() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+var taskSortColumns = map[domain.TaskSort]string{
+	domain.SortCreatedAt: `t."createdAt"`,
+	domain.SortPlannedAt: `t."plannedAt"`,
+	domain.SortPriority:  `t."priority"`,
+	// Ранги сравниваются побайтно, независимо от локали базы.
+	domain.SortRank: `t."rank" COLLATE "C"`,
+}
+
+// taskFilterSQL переводит фильтр задач в условие над таблицей "Task" с алиасом t.
+func taskFilterSQL(filter domain.TaskFilter) *sqlBuilder {
+	b := &sqlBuilder{}
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			placeholders = append(placeholders, b.arg(string(status))+`::"Status"`)
+		}
+		b.where(`t."status" IN (` + strings.Join(placeholders, ", ") + `)`)
+	}
+	if len(filter.Priorities) > 0 {
+		placeholders := make([]string, 0, len(filter.Priorities))
+		for _, priority := range filter.Priorities {
+			placeholders = append(placeholders, b.arg(string(priority))+`::"Priority"`)
+		}
+		b.where(`t."priority" IN (` + strings.Join(placeholders, ", ") + `)`)
+	}
+	if filter.ProjectID != "" {
+		b.where(`t."projectId" = ` + b.arg(filter.ProjectID))
+	}
+	if filter.ViewerID != "" {
+		b.where(`(t."projectId" IS NULL OR EXISTS (SELECT 1 FROM "ProjectMember" pm WHERE pm."projectId" = t."projectId" AND pm."userId" = ` +
+			b.arg(filter.ViewerID) + `))`)
+	}
+	if filter.AssigneeID != "" {
+		b.where(`t."userID" = ` + b.arg(filter.AssigneeID))
+	}
+	if filter.PlannedFrom != nil {
+		b.where(`t."plannedAt" >= ` + b.arg(*filter.PlannedFrom))
+	}
+	if filter.PlannedTo != nil {
+		b.where(`t."plannedAt" <= ` + b.arg(*filter.PlannedTo))
+	}
+	if filter.OverdueAt != nil {
+		b.where(`t."status" <> 'COMPLETED' AND t."plannedAt" < ` + b.arg(*filter.OverdueAt))
+	}
+	if len(filter.LabelIDs) > 0 {
+		placeholders := make([]string, 0, len(filter.LabelIDs))
+		for _, labelID := range filter.LabelIDs {
+			placeholders = append(placeholders, b.arg(labelID))
+		}
+		b.where(`EXISTS (SELECT 1 FROM "TaskLabel" tl WHERE tl."taskId" = t."id" AND tl."labelId" IN (` +
+			strings.Join(placeholders, ", ") + `))`)
+	}
+	for fieldID, value := range filter.Fields {
+		b.where(`EXISTS (SELECT 1 FROM "TaskFieldValue" fv WHERE fv."taskId" = t."id" AND fv."fieldId" = ` +
+			b.arg(fieldID) + ` AND fv."value" = ` + b.arg(value) + `)`)
+	}
+	if filter.Query != "" {
+		pattern := b.arg("%" + escapeLike(filter.Query) + "%")
+		b.where(`(t."title" ILIKE ` + pattern + ` OR t."content" ILIKE ` + pattern + `)`)
+	}
+	return b
+}
+
+func taskOrderSQL(filter domain.TaskFilter) string {
+	column, ok := taskSortColumns[filter.SortBy]
+	if !ok {
+		column = taskSortColumns[domain.SortCreatedAt]
+	}
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+	// id добавлен, чтобы порядок был стабильным между страницами.
+	return fmt.Sprintf(" ORDER BY %s %s, t.\"id\" ASC", column, direction)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
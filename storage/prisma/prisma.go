@@ -2,7 +2,10 @@ package prisma
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
@@ -49,7 +52,7 @@ func (s *Storage) UserByLogin(ctx context.Context, login string) (*domain.User,
 }
 func (s *Storage) CreateUser(ctx context.Context, user *domain.User) error {
 	const op = "storage.user.create"
-	_, err := s.client.User.CreateOne(
+	created, err := s.client.User.CreateOne(
 		db.User.Login.Set(user.Login),
 		db.User.PasswordHash.Set(string(user.PassHash)),
 		db.User.FullName.Set(user.Name),
@@ -57,6 +60,7 @@ func (s *Storage) CreateUser(ctx context.Context, user *domain.User) error {
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	user.ID = created.ID
 	return nil
 }
 
@@ -102,6 +106,35 @@ func (s *Storage) UpdateUser(ctx context.Context, user *domain.User) error {
 	}
 	return nil
 }
+func (s *Storage) SetReminderOffsets(ctx context.Context, id string, offsets []int) error {
+	const op = "storage.user.reminders"
+	_, err := s.client.User.FindUnique(db.User.ID.Equals(id)).Update(
+		db.User.ReminderOffsets.Set(offsets),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) Admins(ctx context.Context) ([]*domain.User, error) {
+	const op = "storage.user.admins"
+	usersDB, err := s.client.User.FindMany(
+		db.User.Role.Equals(db.RoleAdmin),
+		db.User.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var users []*domain.User
+	for _, userDB := range usersDB {
+		user := ValidateUser(userDB)
+		users = append(users, &user)
+	}
+	return users, nil
+}
+
 func (s *Storage) DeleteUser(ctx context.Context, id string) error {
 	const op = "storage.user.delete"
 	_, err := s.client.User.FindUnique(db.User.ID.Equals(id)).Delete().Exec(ctx)
@@ -114,12 +147,22 @@ func (s *Storage) DeleteUser(ctx context.Context, id string) error {
 // ARTICLE
 func (s *Storage) CreateArticle(ctx context.Context, article *domain.Article) (string, error) {
 	const op = "storage.article.create"
+	params := []db.ArticleSetParam{
+		db.Article.Content.Set(article.Content),
+		db.Article.Language.Set(article.Language),
+	}
+	if article.SourceID != "" {
+		params = append(params,
+			db.Article.Source.Link(db.Article.ID.Equals(article.SourceID)),
+			db.Article.SourceRevision.Set(article.SourceRevision),
+		)
+	}
 	result, err := s.client.Article.CreateOne(
 		db.Article.Title.Set(article.Title),
 		db.Article.LastEditorName.Set(article.LastEditor),
 		db.Article.CreatorName.Set(article.LastEditor),
 		db.Article.Image.Set(article.Image),
-		db.Article.Content.Set(article.Content),
+		params...,
 	).Exec(ctx)
 
 	if err != nil {
@@ -139,7 +182,10 @@ func (s *Storage) Articles(ctx context.Context, page, limit int) ([]*domain.Arti
 	skip := (page - 1) * limit
 
 	// Добавляем пагинацию и сортировку
-	articlesDB, err := s.client.Article.FindMany().
+	// Переводы отдаются вместе с исходной статьёй, в списке только исходные.
+	articlesDB, err := s.client.Article.FindMany(
+		db.Article.SourceID.IsNull(),
+	).
 		Take(limit). // Количество элементов на странице
 		Skip(skip).
 		OrderBy(db.Article.CreatedAt.Order(db.ASC)). // Сколько элементов пропуститьA
@@ -168,15 +214,37 @@ func (s *Storage) Article(ctx context.Context, id string) (*domain.Article, erro
 	return &article, nil
 }
 
+func (s *Storage) Translations(ctx context.Context, sourceID string) ([]*domain.Article, error) {
+	const op = "storage.article.translations"
+	articlesDB, err := s.client.Article.FindMany(
+		db.Article.SourceID.Equals(sourceID),
+	).OrderBy(db.Article.Language.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var articles []*domain.Article
+	for _, articleDB := range articlesDB {
+		article := ValidateArticle(articleDB)
+		articles = append(articles, &article)
+	}
+	return articles, nil
+}
+
 func (s *Storage) UpdateArticle(ctx context.Context, article *domain.Article) (*domain.Article, error) {
 	const op = "storage.article.update"
-	articleDB, err := s.client.Article.FindUnique(db.Article.ID.Equals(article.ID)).Update(
+	params := []db.ArticleSetParam{
 		db.Article.Title.Set(article.Title),
 		db.Article.UpdatedAt.Set(time.Now()),
 		db.Article.LastEditorName.Set(article.LastEditor),
 		db.Article.Image.Set(article.Image),
 		db.Article.Content.Set(article.Content),
-	).Exec(ctx)
+		db.Article.Revision.Increment(1),
+	}
+	if article.SourceRevision > 0 {
+		params = append(params, db.Article.SourceRevision.Set(article.SourceRevision))
+	}
+	articleDB, err := s.client.Article.FindUnique(db.Article.ID.Equals(article.ID)).Update(params...).Exec(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -198,54 +266,84 @@ func (s *Storage) DeleteArticle(ctx context.Context, id string) error {
 
 func (s *Storage) InitHistory(ctx context.Context, history *domain.History) error {
 	const op = "storage.history.init"
-	_, err := s.client.ArticleHistory.CreateOne(
-		db.ArticleHistory.ArticleID.Set(history.ArticleId),
-		db.ArticleHistory.UserID.Set(history.UserId),
-		db.ArticleHistory.EventType.Set(db.EventTypeCreate),
-		db.ArticleHistory.ArticleTitle.Set(history.ArticleTitle),
-	).Exec(ctx)
-	if err != nil {
+	history.EventType = domain.Create
+	if err := s.appendHistory(ctx, history); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
 func (s *Storage) UpdateHistory(ctx context.Context, history *domain.History) error {
-	const op = "storage.history.init"
-	_, err := s.client.ArticleHistory.CreateOne(
-		db.ArticleHistory.ArticleID.Set(history.ArticleId),
-		db.ArticleHistory.UserID.Set(history.UserId),
-		db.ArticleHistory.EventType.Set(db.EventType(history.EventType)),
-		db.ArticleHistory.ArticleTitle.Set(history.ArticleTitle),
-	).Exec(ctx)
-	if err != nil {
+	const op = "storage.history.update"
+	if err := s.appendHistory(ctx, history); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
-func (s *Storage) Histories(ctx context.Context, page, limit int) ([]*domain.History, error) {
-	const op = "storage.history.all"
+// appendHistory добавляет событие в конец цепочки истории.
+func (s *Storage) appendHistory(ctx context.Context, history *domain.History) error {
+	history.ChangedAt = domain.ChainTime(time.Now())
+	return s.appendChain(ctx, domain.ChainHistory, func(link domain.ChainLink) (string, []any) {
+		history.ChainLink = link
+		history.Hash = domain.ChainHash(link.PrevHash, history.ChainContent())
+		return `INSERT INTO "ArticleHistory" ("id", "articleId", "userId", "changedAt", "eventType", "articleTitle", "seq", "prevHash", "hash")
+			SELECT gen_random_uuid()::text, $1, $2, $3, $4::"EventType", $5, $6, $7, $8`,
+			[]any{history.ArticleId, history.UserId, history.ChangedAt, string(history.EventType), history.ArticleTitle,
+				history.Seq, history.PrevHash, history.Hash}
+	})
+}
 
-	if page < 1 {
-		page = 1
+func (s *Storage) Histories(ctx context.Context, filter domain.HistoryFilter) ([]*domain.History, error) {
+	const op = "storage.history.all"
+	var where []db.ArticleHistoryWhereParam
+	if filter.ArticleID != "" {
+		where = append(where, db.ArticleHistory.ArticleID.Equals(filter.ArticleID))
 	}
-	if limit < 1 {
-		limit = 6 // значение по умолчанию
+	if filter.UserID != "" {
+		where = append(where, db.ArticleHistory.UserID.Equals(filter.UserID))
 	}
-	skip := (page - 1) * limit
-	historiesDB, err := s.client.ArticleHistory.FindMany().
-		Take(limit). // Количество элементов на странице
+	if filter.EventType != "" {
+		where = append(where, db.ArticleHistory.EventType.Equals(db.EventType(filter.EventType)))
+	}
+	if filter.From != nil {
+		where = append(where, db.ArticleHistory.ChangedAt.Gte(*filter.From))
+	}
+	if filter.To != nil {
+		where = append(where, db.ArticleHistory.ChangedAt.Lte(*filter.To))
+	}
+	order := db.DESC
+	if filter.Ascending {
+		order = db.ASC
+	}
+	skip, take := pagination(filter.Page, filter.Limit)
+	historiesDB, err := s.client.ArticleHistory.FindMany(where...).
+		OrderBy(db.ArticleHistory.ChangedAt.Order(order)).
 		Skip(skip).
-		OrderBy(db.ArticleHistory.ChangedAt.Order(db.ASC)). // Сколько элементов пропуститьA
+		Take(take).
 		Exec(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var histories []*domain.History
+	// у истории нет внешнего ключа на пользователя, имена подгружаем отдельно
+	userIDs := make([]string, 0, len(historiesDB))
+	for _, historyDB := range historiesDB {
+		userIDs = append(userIDs, historyDB.UserID)
+	}
+	usersDB, err := s.client.User.FindMany(db.User.ID.In(userIDs)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	names := make(map[string]string, len(usersDB))
+	for _, userDB := range usersDB {
+		names[userDB.ID] = userDB.FullName
+	}
+
+	histories := make([]*domain.History, 0, len(historiesDB))
 	for _, historyDB := range historiesDB {
 		history := ValidateArticleHistory(historyDB)
+		history.UserName = names[history.UserId]
 		histories = append(histories, &history)
 	}
 	return histories, nil
@@ -253,17 +351,28 @@ func (s *Storage) Histories(ctx context.Context, page, limit int) ([]*domain.His
 
 //TASK
 
+// pagination приводит параметры страницы к допустимым и возвращает skip и take.
+func pagination(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 6 // значение по умолчанию
+	}
+	return (page - 1) * limit, limit
+}
+
 func (s *Storage) CreateTask(ctx context.Context, task *domain.Task) (string, error) {
 	const op = "storage.task.create"
-	user := db.User.ID.Equals(task.UserID)
 	result, err := s.client.Task.CreateOne(
 		db.Task.Title.Set(task.Title),
 		db.Task.Content.Set(task.Content),
 		db.Task.Image.Set(task.Image),
-		db.Task.Responsibleuser.Link(user),
+		db.Task.Responsibleuser.Link(db.User.ID.Equals(task.UserID)),
 		db.Task.PlannedAt.Set(task.PlannedAt),
 		db.Task.Priority.Set(db.Priority(task.Priority)),
 		db.Task.Status.Set(db.Status(task.Status)),
+		taskCreateParams(task)...,
 	).Exec(ctx)
 
 	if err != nil {
@@ -272,25 +381,234 @@ func (s *Storage) CreateTask(ctx context.Context, task *domain.Task) (string, er
 	return result.ID, nil
 }
 
-func (s *Storage) Tasks(ctx context.Context, page, limit int) ([]*domain.Task, error) {
+// taskCreateParams — необязательные поля новой задачи.
+func taskCreateParams(task *domain.Task) []db.TaskSetParam {
+	params := []db.TaskSetParam{
+		db.Task.StartedAt.SetIfPresent(task.StartedAt),
+		db.Task.CompletedAt.SetIfPresent(task.CompletedAt),
+		db.Task.Rank.Set(task.Rank),
+	}
+	if task.CreatorID != "" {
+		params = append(params, db.Task.Creator.Link(db.User.ID.Equals(task.CreatorID)))
+	}
+	if task.ParentID != "" {
+		params = append(params, db.Task.Parent.Link(db.Task.ID.Equals(task.ParentID)))
+	}
+	if task.ProjectID != "" {
+		params = append(params, db.Task.Project.Link(db.Project.ID.Equals(task.ProjectID)))
+		if task.Number > 0 {
+			params = append(params, db.Task.Number.Set(task.Number))
+		}
+	}
+	return params
+}
+
+// CreateTasks создаёт задачи пакета одной транзакцией. Id генерируются
+// заранее, чтобы подзадачи могли сослаться на родителя из того же пакета.
+// Номер в проекте задача получает тем же запросом, что увеличивает счётчик,
+// поэтому при откате номера не пропадают.
+func (s *Storage) CreateTasks(ctx context.Context, drafts []*domain.TaskDraft) ([]string, error) {
+	const op = "storage.task.create_batch"
+	if len(drafts) == 0 {
+		return nil, nil
+	}
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`SELECT gen_random_uuid()::text AS "id" FROM generate_series(1, $1)`,
+		len(drafts),
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) != len(drafts) {
+		return nil, fmt.Errorf("%s: got %d ids for %d tasks", op, len(rows), len(drafts))
+	}
+
+	ids := make([]string, len(drafts))
+	byDraft := make(map[*domain.TaskDraft]string, len(drafts))
+	now := time.Now()
+	var txs []db.PrismaTransaction
+	for i, draft := range drafts {
+		id := string(rows[i].ID)
+		ids[i] = id
+		byDraft[draft] = id
+		task := draft.Task
+		if draft.Parent != nil {
+			parentID, ok := byDraft[draft.Parent]
+			if !ok {
+				return nil, fmt.Errorf("%s: task %d: parent must come earlier in the batch", op, i+1)
+			}
+			task.ParentID = parentID
+		}
+		txs = append(txs, s.client.Task.CreateOne(
+			db.Task.Title.Set(task.Title),
+			db.Task.Content.Set(task.Content),
+			db.Task.Image.Set(task.Image),
+			db.Task.Responsibleuser.Link(db.User.ID.Equals(task.UserID)),
+			db.Task.PlannedAt.Set(task.PlannedAt),
+			db.Task.Priority.Set(db.Priority(task.Priority)),
+			db.Task.Status.Set(db.Status(task.Status)),
+			append(taskCreateParams(&task), db.Task.ID.Set(id))...,
+		).Tx())
+		if task.ProjectID != "" {
+			txs = append(txs, s.client.Prisma.ExecuteRaw(
+				`WITH counter AS (
+					UPDATE "Project" SET "taskCounter" = "taskCounter" + 1 WHERE "id" = $2 RETURNING "taskCounter"
+				)
+				UPDATE "Task" SET "number" = counter."taskCounter" FROM counter WHERE "Task"."id" = $1`,
+				id, task.ProjectID,
+			).Tx())
+		}
+		for _, userID := range []string{task.CreatorID, task.UserID} {
+			if userID == "" {
+				continue
+			}
+			txs = append(txs, s.client.Prisma.ExecuteRaw(
+				`INSERT INTO "TaskWatcher" ("taskId", "userId", "createdAt") VALUES ($1, $2, $3)
+				ON CONFLICT ("taskId", "userId") DO NOTHING`,
+				id, userID, now,
+			).Tx())
+		}
+		for position, text := range draft.Checklist {
+			txs = append(txs, s.client.ChecklistItem.CreateOne(
+				db.ChecklistItem.Task.Link(db.Task.ID.Equals(id)),
+				db.ChecklistItem.Text.Set(text),
+				db.ChecklistItem.Position.Set(position),
+				db.ChecklistItem.Done.Set(false),
+			).Tx())
+		}
+		for _, labelID := range draft.LabelIDs {
+			txs = append(txs, s.client.TaskLabel.CreateOne(
+				db.TaskLabel.Task.Link(db.Task.ID.Equals(id)),
+				db.TaskLabel.Label.Link(db.Label.ID.Equals(labelID)),
+			).Tx())
+		}
+		for fieldID, value := range draft.Fields {
+			if value == "" {
+				continue
+			}
+			txs = append(txs, s.client.Prisma.ExecuteRaw(
+				`INSERT INTO "TaskFieldValue" ("taskId", "fieldId", "value") VALUES ($1, $2, $3)`,
+				id, fieldID, value,
+			).Tx())
+		}
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return ids, nil
+}
+
+// Tasks выбирает страницу задач по фильтру и возвращает общее количество.
+// Фильтрация и сортировка выполняются одним SQL-запросом по id,
+// сами задачи со связями загружаются через клиент.
+func (s *Storage) Tasks(ctx context.Context, filter domain.TaskFilter) ([]*domain.Task, int, error) {
 	const op = "storage.task.all"
+	b := taskFilterSQL(filter)
 
-	if page < 1 {
-		page = 1
+	var counts []struct {
+		Count db.RawBigInt `json:"count"`
 	}
-	if limit < 1 {
-		limit = 6 // значение по умолчанию
+	if err := s.client.Prisma.QueryRaw(`SELECT COUNT(*) AS "count" FROM "Task" t`+b.whereSQL(), b.args...).Exec(ctx, &counts); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
 	}
-	skip := (page - 1) * limit
-	tasksDB, err := s.client.Task.FindMany().
-		Take(limit). // Количество элементов на странице
-		Skip(skip).
-		OrderBy(db.Task.CreatedAt.Order(db.ASC)).
-		With(db.Task.Responsibleuser.Fetch()). // Сколько элементов пропуститьA
+	total := 0
+	if len(counts) > 0 {
+		total = int(counts[0].Count)
+	}
+
+	skip, take := pagination(filter.Page, filter.Limit)
+	query := `SELECT t."id" FROM "Task" t` + b.whereSQL() + taskOrderSQL(filter) +
+		` LIMIT ` + b.arg(take) + ` OFFSET ` + b.arg(skip)
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	if err := s.client.Prisma.QueryRaw(query, b.args...).Exec(ctx, &rows); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, string(row.ID))
+	}
+	tasks, err := s.tasksByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, total, nil
+}
+
+// taskRelations — связи, которые загружаются вместе с задачей.
+func taskRelations() []db.TaskRelationWith {
+	return []db.TaskRelationWith{
+		db.Task.Responsibleuser.Fetch(),
+		db.Task.Creator.Fetch(),
+		db.Task.Labels.Fetch().With(db.TaskLabel.Label.Fetch()),
+		db.Task.FieldValues.Fetch().With(db.TaskFieldValue.Field.Fetch()),
+		db.Task.Project.Fetch(),
+	}
+}
+
+// tasksByIDs загружает задачи со связями в порядке переданных id.
+func (s *Storage) tasksByIDs(ctx context.Context, ids []string) ([]*domain.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	tasksDB, err := s.client.Task.FindMany(db.Task.ID.In(ids)).
+		With(taskRelations()...).
 		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Task, len(tasksDB))
+	for _, taskDB := range tasksDB {
+		task := ValidateTask(taskDB)
+		byID[task.ID] = &task
+	}
+	tasks := make([]*domain.Task, 0, len(ids))
+	for _, id := range ids {
+		if task, ok := byID[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *Storage) TasksByAssignee(ctx context.Context, userID string, page, limit int) ([]*domain.Task, error) {
+	const op = "storage.task.by_assignee"
+	tasks, err := s.findTasks(ctx, page, limit, db.Task.PlannedAt.Order(db.ASC),
+		db.Task.UserID.Equals(userID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, nil
+}
+
+func (s *Storage) TasksByCreator(ctx context.Context, creatorID string, page, limit int) ([]*domain.Task, error) {
+	const op = "storage.task.by_creator"
+	tasks, err := s.findTasks(ctx, page, limit, db.Task.PlannedAt.Order(db.ASC),
+		db.Task.CreatorID.Equals(creatorID),
+		db.Task.Not(db.Task.UserID.Equals(creatorID)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	return tasks, nil
+}
+
+func (s *Storage) findTasks(ctx context.Context, page, limit int, order db.TaskOrderByParam, where ...db.TaskWhereParam) ([]*domain.Task, error) {
+	skip, take := pagination(page, limit)
+	tasksDB, err := s.client.Task.FindMany(where...).
+		Take(take).
+		Skip(skip).
+		OrderBy(order).
+		With(taskRelations()...).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	var tasks []*domain.Task
 	for _, taskDB := range tasksDB {
@@ -299,31 +617,327 @@ func (s *Storage) Tasks(ctx context.Context, page, limit int) ([]*domain.Task, e
 	}
 	return tasks, nil
 }
+
 func (s *Storage) Task(ctx context.Context, id string) (*domain.Task, error) {
-	taskDB, err := s.client.Task.FindUnique(db.Task.ID.Equals(id)).Exec(ctx)
+	const op = "storage.task.get"
+	taskDB, err := s.client.Task.FindUnique(db.Task.ID.Equals(id)).
+		With(taskRelations()...).
+		Exec(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("task not found")
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	task := ValidateTask(*taskDB)
 	return &task, nil
 
 }
+
+// UpdateTask не меняет исполнителя и статус: для этого есть ReassignTask
+// и TransitionTask с записью в журнал.
+// При переносе срока отметки напоминаний и просрочки сбрасываются,
+// чтобы планировщик отработал новый срок заново.
 func (s *Storage) UpdateTask(ctx context.Context, task *domain.Task) error {
 	const op = "storage.task.update"
-	_, err := s.client.Task.FindUnique(db.Task.ID.Equals(task.ID)).Update(
+	reset := s.client.Prisma.ExecuteRaw(
+		`UPDATE "Task" SET "remindedOffsets" = '{}', "overdueAt" = NULL, "escalatedAt" = NULL
+		WHERE "id" = $1 AND "plannedAt" <> $2`,
+		task.ID, task.PlannedAt,
+	).Tx()
+	update := s.client.Task.FindUnique(db.Task.ID.Equals(task.ID)).Update(
 		db.Task.Title.Set(task.Title),
 		db.Task.Content.Set(task.Content),
 		db.Task.Image.Set(task.Image),
-		db.Task.UserID.Set(task.UserID),
 		db.Task.PlannedAt.Set(task.PlannedAt),
 		db.Task.Priority.Set(db.Priority(task.Priority)),
+	).Tx()
+	if err := s.client.Prisma.Transaction(reset, update).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+
+}
+
+// ReassignTask меняет исполнителя и пишет запись в журнал в одной транзакции.
+func (s *Storage) ReassignTask(ctx context.Context, assignment *domain.TaskAssignment) error {
+	const op = "storage.task.reassign"
+	var params []db.TaskAssignmentSetParam
+	if assignment.FromUserID != "" {
+		params = append(params, db.TaskAssignment.FromUserID.Set(assignment.FromUserID))
+	}
+	update := s.client.Task.FindUnique(db.Task.ID.Equals(assignment.TaskID)).Update(
+		db.Task.Responsibleuser.Link(db.User.ID.Equals(assignment.ToUserID)),
+	).Tx()
+	record := s.client.TaskAssignment.CreateOne(
+		db.TaskAssignment.Task.Link(db.Task.ID.Equals(assignment.TaskID)),
+		db.TaskAssignment.ToUserID.Set(assignment.ToUserID),
+		db.TaskAssignment.ChangedByID.Set(assignment.ChangedByID),
+		params...,
+	).Tx()
+	if err := s.client.Prisma.Transaction(update, record).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) TaskAssignments(ctx context.Context, taskID string) ([]*domain.TaskAssignment, error) {
+	const op = "storage.task.assignments"
+	assignmentsDB, err := s.client.TaskAssignment.FindMany(
+		db.TaskAssignment.TaskID.Equals(taskID),
+	).OrderBy(db.TaskAssignment.ChangedAt.Order(db.DESC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var assignments []*domain.TaskAssignment
+	for _, assignmentDB := range assignmentsDB {
+		assignment := ValidateTaskAssignment(assignmentDB)
+		assignments = append(assignments, &assignment)
+	}
+	return assignments, nil
+}
+
+// TransitionTask сохраняет новый статус с отметками времени и запись о переходе в одной транзакции.
+func (s *Storage) TransitionTask(ctx context.Context, task *domain.Task, transition *domain.TaskTransition) error {
+	const op = "storage.task.transition"
+	update := s.client.Task.FindUnique(db.Task.ID.Equals(task.ID)).Update(
 		db.Task.Status.Set(db.Status(task.Status)),
-	).Exec(ctx)
+		db.Task.StartedAt.SetOptional(task.StartedAt),
+		db.Task.CompletedAt.SetOptional(task.CompletedAt),
+	).Tx()
+	record := s.client.TaskTransition.CreateOne(
+		db.TaskTransition.Task.Link(db.Task.ID.Equals(transition.TaskID)),
+		db.TaskTransition.FromStatus.Set(db.Status(transition.From)),
+		db.TaskTransition.ToStatus.Set(db.Status(transition.To)),
+		db.TaskTransition.UserID.Set(transition.UserID),
+	).Tx()
+	if err := s.client.Prisma.Transaction(update, record).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) TaskTransitions(ctx context.Context, taskID string) ([]*domain.TaskTransition, error) {
+	const op = "storage.task.transitions"
+	transitionsDB, err := s.client.TaskTransition.FindMany(
+		db.TaskTransition.TaskID.Equals(taskID),
+	).OrderBy(db.TaskTransition.ChangedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var transitions []*domain.TaskTransition
+	for _, transitionDB := range transitionsDB {
+		transition := ValidateTaskTransition(transitionDB)
+		transitions = append(transitions, &transition)
+	}
+	return transitions, nil
+}
+
+// LastRank возвращает наибольший ранг в колонке доски проекта или пустую строку для пустой колонки.
+func (s *Storage) LastRank(ctx context.Context, projectID string, status domain.Status) (string, error) {
+	const op = "storage.task.last_rank"
+	var rows []struct {
+		Rank db.RawString `json:"rank"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`SELECT "rank" FROM "Task" WHERE "status" = $1::"Status" AND "projectId" IS NOT DISTINCT FROM NULLIF($2, '')
+		ORDER BY "rank" COLLATE "C" DESC LIMIT 1`,
+		string(status), projectID,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return string(rows[0].Rank), nil
+}
+
+// MoveTask атомарно переносит задачу: обновление проходит, только если статус
+// не изменился с момента чтения и в целевой колонке доски проекта задачи есть место. При смене
+// колонки запись о переходе создаётся тем же запросом. Возвращает false,
+// если условие не выполнено.
+func (s *Storage) MoveTask(ctx context.Context, move *domain.TaskMove) (bool, error) {
+	const op = "storage.task.move"
+	const update = `UPDATE "Task" SET
+			"rank" = $2,
+			"status" = $3::"Status",
+			"updatedAt" = $6,
+			"startedAt" = CASE WHEN $3::"Status" IN ('CURRENT', 'COMPLETED') THEN COALESCE("startedAt", $6) ELSE "startedAt" END,
+			"completedAt" = CASE WHEN $3::"Status" = 'COMPLETED' THEN COALESCE("completedAt", $6) ELSE NULL END
+		WHERE "id" = $1 AND "status" = $4::"Status"
+			AND ($3::"Status" = $4::"Status" OR $5::int <= 0
+				OR (SELECT COUNT(*) FROM "Task"
+					WHERE "status" = $3::"Status" AND "projectId" IS NOT DISTINCT FROM NULLIF($7, '')) < $5::int)`
+	now := time.Now()
+
+	if move.From == move.To {
+		result, err := s.client.Prisma.ExecuteRaw(update,
+			move.TaskID, move.Rank, string(move.To), string(move.From), move.WIPLimit, now, move.ProjectID,
+		).Exec(ctx)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		return result.Count > 0, nil
+	}
+
+	// Блокировка колонки не даёт параллельным переносам одновременно пройти проверку WIP-лимита.
+	lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext($1))`, "board:"+move.ProjectID+":"+string(move.To)).Tx()
+	moved := s.client.Prisma.ExecuteRaw(
+		`WITH moved AS (`+update+` RETURNING "id")
+		INSERT INTO "TaskTransition" ("id", "taskId", "fromStatus", "toStatus", "userId", "changedAt")
+		SELECT gen_random_uuid()::text, "id", $4::"Status", $3::"Status", $8, $6 FROM moved`,
+		move.TaskID, move.Rank, string(move.To), string(move.From), move.WIPLimit, now, move.ProjectID, move.UserID,
+	).Tx()
+	if err := s.client.Prisma.Transaction(lock, moved).Exec(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return moved.Result().Count > 0, nil
+}
+
+// ColumnTaskIDs возвращает id задач колонки доски проекта в порядке доски.
+func (s *Storage) ColumnTaskIDs(ctx context.Context, projectID string, status domain.Status) ([]string, error) {
+	const op = "storage.task.column"
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`SELECT "id" FROM "Task" WHERE "status" = $1::"Status" AND "projectId" IS NOT DISTINCT FROM NULLIF($2, '')
+		ORDER BY "rank" COLLATE "C" ASC, "createdAt" ASC, "id" ASC`,
+		string(status), projectID,
+	).Exec(ctx, &rows)
 	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, string(row.ID))
+	}
+	return ids, nil
+}
+
+// RankTasks записывает новые ранги задач одной транзакцией.
+func (s *Storage) RankTasks(ctx context.Context, ranks map[string]string) error {
+	const op = "storage.task.rank"
+	if len(ranks) == 0 {
+		return nil
+	}
+	txs := make([]db.PrismaTransaction, 0, len(ranks))
+	for id, rank := range ranks {
+		txs = append(txs, s.client.Task.FindUnique(db.Task.ID.Equals(id)).Update(
+			db.Task.Rank.Set(rank),
+		).Tx())
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
+}
+
+// SetTaskParent переносит задачу под parentID, если parentID не является ею самой
+// или её потомком. Проверка и обновление выполняются под общей блокировкой дерева
+// задач, чтобы встречные переносы не образовали цикл. Возвращает false при цикле.
+func (s *Storage) SetTaskParent(ctx context.Context, id string, parentID string) (bool, error) {
+	const op = "storage.task.set_parent"
+	if parentID == "" {
+		_, err := s.client.Task.FindUnique(db.Task.ID.Equals(id)).Update(
+			db.Task.Parent.Unlink(),
+		).Exec(ctx)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", op, err)
+		}
+		return true, nil
+	}
+	lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext('task_tree'))`).Tx()
+	update := s.client.Prisma.ExecuteRaw(
+		`UPDATE "Task" SET "parentId" = $2, "updatedAt" = $3
+		WHERE "id" = $1 AND "id" <> $2 AND NOT EXISTS (
+			WITH RECURSIVE ancestors AS (
+				SELECT "id", "parentId" FROM "Task" WHERE "id" = $2
+				UNION
+				SELECT t."id", t."parentId" FROM "Task" t JOIN ancestors a ON t."id" = a."parentId"
+			)
+			SELECT 1 FROM ancestors WHERE "id" = $1
+		)`,
+		id, parentID, time.Now(),
+	).Tx()
+	if err := s.client.Prisma.Transaction(lock, update).Exec(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return update.Result().Count > 0, nil
+}
+
+func (s *Storage) Subtasks(ctx context.Context, id string) ([]*domain.Task, error) {
+	const op = "storage.task.subtasks"
+	tasksDB, err := s.client.Task.FindMany(db.Task.ParentID.Equals(id)).
+		OrderBy(db.Task.CreatedAt.Order(db.ASC)).
+		With(taskRelations()...).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var tasks []*domain.Task
+	for _, taskDB := range tasksDB {
+		task := ValidateTask(taskDB)
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
 
+// subtreeSQL — рекурсивный CTE "subtree" со всеми потомками задачи $1.
+const subtreeSQL = `WITH RECURSIVE subtree AS (
+		SELECT "id", "status" FROM "Task" WHERE "parentId" = $1
+		UNION
+		SELECT t."id", t."status" FROM "Task" t JOIN subtree st ON t."parentId" = st."id"
+	) `
+
+// OpenSubtasks считает незавершённые задачи на любой глубине вложенности.
+func (s *Storage) OpenSubtasks(ctx context.Context, id string) (int, error) {
+	const op = "storage.task.open_subtasks"
+	var rows []struct {
+		Count db.RawBigInt `json:"count"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		subtreeSQL+`SELECT COUNT(*) AS "count" FROM subtree WHERE "status" <> 'COMPLETED'`,
+		id,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return int(rows[0].Count), nil
+}
+
+// TaskProgress собирает счётчики подзадач всех уровней и пунктов чек-листа задачи.
+func (s *Storage) TaskProgress(ctx context.Context, id string) (*domain.TaskProgress, error) {
+	const op = "storage.task.progress"
+	var rows []struct {
+		Subtasks          db.RawBigInt `json:"subtasks"`
+		CompletedSubtasks db.RawBigInt `json:"completed_subtasks"`
+		ChecklistItems    db.RawBigInt `json:"checklist_items"`
+		DoneItems         db.RawBigInt `json:"done_items"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		subtreeSQL+`SELECT
+			(SELECT COUNT(*) FROM subtree) AS "subtasks",
+			(SELECT COUNT(*) FROM subtree WHERE "status" = 'COMPLETED') AS "completed_subtasks",
+			(SELECT COUNT(*) FROM "ChecklistItem" WHERE "taskId" = $1) AS "checklist_items",
+			(SELECT COUNT(*) FROM "ChecklistItem" WHERE "taskId" = $1 AND "done") AS "done_items"`,
+		id,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	progress := &domain.TaskProgress{}
+	if len(rows) > 0 {
+		progress.Subtasks = int(rows[0].Subtasks)
+		progress.CompletedSubtasks = int(rows[0].CompletedSubtasks)
+		progress.ChecklistItems = int(rows[0].ChecklistItems)
+		progress.DoneItems = int(rows[0].DoneItems)
+	}
+	return progress, nil
 }
 
 func (s *Storage) DeleteTask(ctx context.Context, id string) error {
@@ -334,3 +948,2167 @@ func (s *Storage) DeleteTask(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// ApplyBulk применяет проверенные изменения массовой операции одной транзакцией.
+// Смена статуса, как и MoveTask, проходит только если статус задачи всё ещё
+// равен исходному; задачи, изменённые параллельно, возвращаются в conflicts.
+func (s *Storage) ApplyBulk(ctx context.Context, operation domain.BulkOperation, changes []*domain.BulkChange) (conflicts []string, err error) {
+	const op = "storage.task.bulk"
+	var txs []db.PrismaTransaction
+	var moved []func() bool
+	now := time.Now()
+	for _, change := range changes {
+		task := s.client.Task.FindUnique(db.Task.ID.Equals(change.Task.ID))
+		switch operation {
+		case domain.BulkStatus:
+			tx := s.client.Prisma.ExecuteRaw(
+				`WITH moved AS (
+					UPDATE "Task" SET
+						"status" = $2::"Status",
+						"updatedAt" = $4,
+						"startedAt" = CASE WHEN $2::"Status" IN ('CURRENT', 'COMPLETED') THEN COALESCE("startedAt", $4) ELSE "startedAt" END,
+						"completedAt" = CASE WHEN $2::"Status" = 'COMPLETED' THEN COALESCE("completedAt", $4) ELSE NULL END
+					WHERE "id" = $1 AND "status" = $3::"Status"
+					RETURNING "id"
+				)
+				INSERT INTO "TaskTransition" ("id", "taskId", "fromStatus", "toStatus", "userId", "changedAt")
+				SELECT gen_random_uuid()::text, "id", $3::"Status", $2::"Status", $5, $4 FROM moved`,
+				change.Task.ID, string(change.Transition.To), string(change.Transition.From), now, change.Transition.UserID,
+			).Tx()
+			txs = append(txs, tx)
+			moved = append(moved, func() bool { return tx.Result().Count > 0 })
+		case domain.BulkPriority:
+			txs = append(txs, task.Update(
+				db.Task.Priority.Set(db.Priority(change.Task.Priority)),
+			).Tx())
+		case domain.BulkAssign:
+			var params []db.TaskAssignmentSetParam
+			if change.Assignment.FromUserID != "" {
+				params = append(params, db.TaskAssignment.FromUserID.Set(change.Assignment.FromUserID))
+			}
+			txs = append(txs,
+				task.Update(
+					db.Task.Responsibleuser.Link(db.User.ID.Equals(change.Assignment.ToUserID)),
+				).Tx(),
+				s.client.TaskAssignment.CreateOne(
+					db.TaskAssignment.Task.Link(db.Task.ID.Equals(change.Assignment.TaskID)),
+					db.TaskAssignment.ToUserID.Set(change.Assignment.ToUserID),
+					db.TaskAssignment.ChangedByID.Set(change.Assignment.ChangedByID),
+					params...,
+				).Tx(),
+				s.client.Prisma.ExecuteRaw(
+					`INSERT INTO "TaskWatcher" ("taskId", "userId", "createdAt") VALUES ($1, $2, $3)
+					ON CONFLICT ("taskId", "userId") DO NOTHING`,
+					change.Task.ID, change.Assignment.ToUserID, now,
+				).Tx(),
+			)
+		case domain.BulkAddLabel:
+			txs = append(txs, s.client.Prisma.ExecuteRaw(
+				`INSERT INTO "TaskLabel" ("taskId", "labelId") VALUES ($1, $2)
+				ON CONFLICT ("taskId", "labelId") DO NOTHING`,
+				change.Task.ID, change.LabelID,
+			).Tx())
+		case domain.BulkDelete:
+			txs = append(txs, task.Delete().Tx())
+		}
+	}
+	if len(txs) == 0 {
+		return nil, nil
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for i, ok := range moved {
+		if !ok() {
+			conflicts = append(conflicts, changes[i].Task.ID)
+		}
+	}
+	return conflicts, nil
+}
+
+// TASK DEPENDENCY
+
+func (s *Storage) TasksByIDs(ctx context.Context, ids []string) ([]*domain.Task, error) {
+	const op = "storage.task.by_ids"
+	tasks, err := s.tasksByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, nil
+}
+
+func (s *Storage) Dependency(ctx context.Context, blockerID string, blockedID string) (*domain.TaskDependency, error) {
+	const op = "storage.dependency.get"
+	dependencyDB, err := s.client.TaskDependency.FindFirst(
+		db.TaskDependency.BlockerID.Equals(blockerID),
+		db.TaskDependency.BlockedID.Equals(blockedID),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	dependency := ValidateTaskDependency(*dependencyDB)
+	return &dependency, nil
+}
+
+// AddDependency добавляет связь, если blocker недостижим из blocked по уже
+// существующим связям, иначе она замкнула бы цикл. Проверка и вставка идут
+// под общей блокировкой графа зависимостей. Возвращает false при цикле.
+func (s *Storage) AddDependency(ctx context.Context, dependency *domain.TaskDependency) (bool, error) {
+	const op = "storage.dependency.add"
+	lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext('task_dependencies'))`).Tx()
+	insert := s.client.Prisma.ExecuteRaw(
+		`INSERT INTO "TaskDependency" ("id", "blockerId", "blockedId", "createdById", "createdAt")
+		SELECT gen_random_uuid()::text, $1, $2, $3, $4
+		WHERE NOT EXISTS (
+			WITH RECURSIVE reachable AS (
+				SELECT "blockedId" AS "id" FROM "TaskDependency" WHERE "blockerId" = $2
+				UNION
+				SELECT d."blockedId" FROM "TaskDependency" d JOIN reachable r ON d."blockerId" = r."id"
+			)
+			SELECT 1 FROM reachable WHERE "id" = $1
+		)
+		ON CONFLICT ("blockerId", "blockedId") DO NOTHING`,
+		dependency.BlockerID, dependency.BlockedID, dependency.CreatedByID, time.Now(),
+	).Tx()
+	if err := s.client.Prisma.Transaction(lock, insert).Exec(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if insert.Result().Count == 0 {
+		return false, nil
+	}
+	created, err := s.Dependency(ctx, dependency.BlockerID, dependency.BlockedID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	*dependency = *created
+	return true, nil
+}
+
+func (s *Storage) DeleteDependency(ctx context.Context, id string) error {
+	const op = "storage.dependency.delete"
+	_, err := s.client.TaskDependency.FindUnique(db.TaskDependency.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// Dependencies возвращает связи, у которых обе задачи входят в ids.
+func (s *Storage) Dependencies(ctx context.Context, ids []string) ([]*domain.TaskDependency, error) {
+	const op = "storage.dependency.all"
+	dependenciesDB, err := s.client.TaskDependency.FindMany(
+		db.TaskDependency.BlockerID.In(ids),
+		db.TaskDependency.BlockedID.In(ids),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var dependencies []*domain.TaskDependency
+	for _, dependencyDB := range dependenciesDB {
+		dependency := ValidateTaskDependency(dependencyDB)
+		dependencies = append(dependencies, &dependency)
+	}
+	return dependencies, nil
+}
+
+// TaskBlockers возвращает задачи, которые блокируют задачу id.
+func (s *Storage) TaskBlockers(ctx context.Context, id string) ([]*domain.Task, error) {
+	const op = "storage.dependency.blockers"
+	dependenciesDB, err := s.client.TaskDependency.FindMany(
+		db.TaskDependency.BlockedID.Equals(id),
+	).OrderBy(db.TaskDependency.CreatedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ids := make([]string, 0, len(dependenciesDB))
+	for _, dependencyDB := range dependenciesDB {
+		ids = append(ids, dependencyDB.BlockerID)
+	}
+	tasks, err := s.tasksByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, nil
+}
+
+// TaskBlocking возвращает задачи, которые блокирует задача id.
+func (s *Storage) TaskBlocking(ctx context.Context, id string) ([]*domain.Task, error) {
+	const op = "storage.dependency.blocking"
+	dependenciesDB, err := s.client.TaskDependency.FindMany(
+		db.TaskDependency.BlockerID.Equals(id),
+	).OrderBy(db.TaskDependency.CreatedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ids := make([]string, 0, len(dependenciesDB))
+	for _, dependencyDB := range dependenciesDB {
+		ids = append(ids, dependencyDB.BlockedID)
+	}
+	tasks, err := s.tasksByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return tasks, nil
+}
+
+// OpenBlockers считает незавершённые задачи, блокирующие задачу id.
+func (s *Storage) OpenBlockers(ctx context.Context, id string) (int, error) {
+	const op = "storage.dependency.open_blockers"
+	var rows []struct {
+		Count db.RawBigInt `json:"count"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`SELECT COUNT(*) AS "count" FROM "TaskDependency" d
+		JOIN "Task" t ON t."id" = d."blockerId"
+		WHERE d."blockedId" = $1 AND t."status" <> 'COMPLETED'`,
+		id,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	return int(rows[0].Count), nil
+}
+
+// RECURRENCE
+
+func (s *Storage) CreateRecurrence(ctx context.Context, recurrence *domain.Recurrence) (string, error) {
+	const op = "storage.recurrence.create"
+	params := []db.TaskRecurrenceSetParam{
+		db.TaskRecurrence.Until.SetIfPresent(recurrence.Until),
+	}
+	if recurrence.Count > 0 {
+		params = append(params, db.TaskRecurrence.Count.Set(recurrence.Count))
+	}
+	if recurrence.ProjectID != "" {
+		params = append(params, db.TaskRecurrence.Project.Link(db.Project.ID.Equals(recurrence.ProjectID)))
+	}
+	result, err := s.client.TaskRecurrence.CreateOne(
+		db.TaskRecurrence.Frequency.Set(db.RecurrenceFrequency(recurrence.Frequency)),
+		db.TaskRecurrence.Interval.Set(recurrence.Interval),
+		db.TaskRecurrence.Mode.Set(db.RecurrenceMode(recurrence.Mode)),
+		db.TaskRecurrence.StartAt.Set(recurrence.StartAt),
+		db.TaskRecurrence.NextAt.Set(recurrence.NextAt),
+		db.TaskRecurrence.Title.Set(recurrence.Title),
+		db.TaskRecurrence.Content.Set(recurrence.Content),
+		db.TaskRecurrence.Image.Set(recurrence.Image),
+		db.TaskRecurrence.Priority.Set(db.Priority(recurrence.Priority)),
+		db.TaskRecurrence.UserID.Set(recurrence.UserID),
+		db.TaskRecurrence.CreatorID.Set(recurrence.CreatorID),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return result.ID, nil
+}
+
+func (s *Storage) Recurrence(ctx context.Context, id string) (*domain.Recurrence, error) {
+	const op = "storage.recurrence.get"
+	recurrenceDB, err := s.client.TaskRecurrence.FindUnique(db.TaskRecurrence.ID.Equals(id)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	recurrence := ValidateRecurrence(*recurrenceDB)
+	return &recurrence, nil
+}
+
+func (s *Storage) Recurrences(ctx context.Context) ([]*domain.Recurrence, error) {
+	const op = "storage.recurrence.all"
+	recurrencesDB, err := s.client.TaskRecurrence.FindMany().
+		OrderBy(db.TaskRecurrence.CreatedAt.Order(db.DESC)).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var recurrences []*domain.Recurrence
+	for _, recurrenceDB := range recurrencesDB {
+		recurrence := ValidateRecurrence(recurrenceDB)
+		recurrences = append(recurrences, &recurrence)
+	}
+	return recurrences, nil
+}
+
+func (s *Storage) DueRecurrences(ctx context.Context, before time.Time) ([]*domain.Recurrence, error) {
+	const op = "storage.recurrence.due"
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`SELECT r."id" FROM "TaskRecurrence" r
+		WHERE r."active" AND (
+			(r."mode" = 'SCHEDULE' AND r."nextAt" <= $1)
+			OR (r."mode" = 'COMPLETION' AND NOT EXISTS (
+				SELECT 1 FROM "Task" t WHERE t."recurrenceId" = r."id" AND t."status" <> 'COMPLETED'
+			))
+		)`,
+		before,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, string(row.ID))
+	}
+	recurrencesDB, err := s.client.TaskRecurrence.FindMany(db.TaskRecurrence.ID.In(ids)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var recurrences []*domain.Recurrence
+	for _, recurrenceDB := range recurrencesDB {
+		recurrence := ValidateRecurrence(recurrenceDB)
+		recurrences = append(recurrences, &recurrence)
+	}
+	return recurrences, nil
+}
+
+// MaterializeOccurrence одним запросом продвигает правило и создаёт вхождение.
+// Условие на число вхождений не даёт нескольким экземплярам планировщика
+// создать одно и то же вхождение дважды. Вхождение правила проекта получает
+// следующий номер задачи проекта.
+func (s *Storage) MaterializeOccurrence(ctx context.Context, recurrence *domain.Recurrence, prevOccurrences int, task *domain.Task) (bool, error) {
+	const op = "storage.recurrence.materialize"
+	result, err := s.client.Prisma.ExecuteRaw(
+		`WITH claimed AS (
+			UPDATE "TaskRecurrence" SET "occurrences" = $2, "slot" = $3, "nextAt" = $4
+			WHERE "id" = $1 AND "occurrences" = $5 AND "active"
+			RETURNING "id", "projectId"
+		), counter AS (
+			UPDATE "Project" SET "taskCounter" = "taskCounter" + 1
+			WHERE "id" = (SELECT "projectId" FROM claimed)
+			RETURNING "taskCounter"
+		)
+		INSERT INTO "Task" ("id", "title", "content", "image", "userID", "creatorId", "plannedAt",
+			"priority", "status", "rank", "recurrenceId", "occurrence", "createdAt", "projectId", "number")
+		SELECT gen_random_uuid()::text, $6, $7, $8, $9, NULLIF($10, ''), $11,
+			$12::"Priority", $13::"Status", $14, "id", $2, $15, "projectId", (SELECT "taskCounter" FROM counter)
+		FROM claimed`,
+		recurrence.ID, recurrence.Occurrences, recurrence.Slot, recurrence.NextAt, prevOccurrences,
+		task.Title, task.Content, task.Image, task.UserID, task.CreatorID, task.PlannedAt,
+		string(task.Priority), string(task.Status), task.Rank, time.Now(),
+	).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) StopRecurrence(ctx context.Context, id string) error {
+	const op = "storage.recurrence.stop"
+	_, err := s.client.TaskRecurrence.FindUnique(db.TaskRecurrence.ID.Equals(id)).Update(
+		db.TaskRecurrence.Active.Set(false),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// UpdateFutureOccurrences одной транзакцией сохраняет шаблон правила и переносит
+// его поля на открытые вхождения после fromOccurrence. Непустой reassignedBy —
+// вхождения получают исполнителя правила с записью в журнал назначений и подпиской.
+// С reschedule вхождение fromOccurrence+n получает дату слота n нового расписания.
+func (s *Storage) UpdateFutureOccurrences(ctx context.Context, recurrence *domain.Recurrence, fromOccurrence int, reassignedBy string, reschedule bool) error {
+	const op = "storage.recurrence.update_future"
+	future := []db.TaskWhereParam{
+		db.Task.RecurrenceID.Equals(recurrence.ID),
+		db.Task.Occurrence.Gt(fromOccurrence),
+		db.Task.Status.Not(db.StatusCompleted),
+	}
+	var txs []db.PrismaTransaction
+	txs = append(txs,
+		s.client.TaskRecurrence.FindUnique(db.TaskRecurrence.ID.Equals(recurrence.ID)).Update(
+			db.TaskRecurrence.Title.Set(recurrence.Title),
+			db.TaskRecurrence.Content.Set(recurrence.Content),
+			db.TaskRecurrence.Image.Set(recurrence.Image),
+			db.TaskRecurrence.Priority.Set(db.Priority(recurrence.Priority)),
+			db.TaskRecurrence.UserID.Set(recurrence.UserID),
+			db.TaskRecurrence.StartAt.Set(recurrence.StartAt),
+			db.TaskRecurrence.Slot.Set(recurrence.Slot),
+			db.TaskRecurrence.NextAt.Set(recurrence.NextAt),
+		).Tx(),
+		s.client.Task.FindMany(future...).Update(
+			db.Task.Title.Set(recurrence.Title),
+			db.Task.Content.Set(recurrence.Content),
+			db.Task.Image.Set(recurrence.Image),
+			db.Task.Priority.Set(db.Priority(recurrence.Priority)),
+		).Tx(),
+	)
+	if reassignedBy != "" {
+		now := time.Now()
+		txs = append(txs,
+			// журнал и подписка раньше смены исполнителя: им нужен прежний
+			s.client.Prisma.ExecuteRaw(
+				`INSERT INTO "TaskAssignment" ("id", "taskId", "fromUserId", "toUserId", "changedById", "changedAt")
+				SELECT gen_random_uuid()::text, "id", "userID", $2, $4, $5 FROM "Task"
+				WHERE "recurrenceId" = $1 AND "occurrence" > $3 AND "status" <> 'COMPLETED' AND "userID" <> $2`,
+				recurrence.ID, recurrence.UserID, fromOccurrence, reassignedBy, now,
+			).Tx(),
+			s.client.Prisma.ExecuteRaw(
+				`INSERT INTO "TaskWatcher" ("taskId", "userId", "createdAt")
+				SELECT "id", $2, $4 FROM "Task"
+				WHERE "recurrenceId" = $1 AND "occurrence" > $3 AND "status" <> 'COMPLETED' AND "userID" <> $2
+				ON CONFLICT ("taskId", "userId") DO NOTHING`,
+				recurrence.ID, recurrence.UserID, fromOccurrence, now,
+			).Tx(),
+			s.client.Prisma.ExecuteRaw(
+				`UPDATE "Task" SET "userID" = $2
+				WHERE "recurrenceId" = $1 AND "occurrence" > $3 AND "status" <> 'COMPLETED' AND "userID" <> $2`,
+				recurrence.ID, recurrence.UserID, fromOccurrence,
+			).Tx(),
+		)
+	}
+	if reschedule {
+		tasksDB, err := s.client.Task.FindMany(future...).Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		for _, taskDB := range tasksDB {
+			txs = append(txs, s.client.Task.FindUnique(db.Task.ID.Equals(taskDB.ID)).Update(
+				db.Task.PlannedAt.Set(recurrence.OccurrenceAt(taskDB.Occurrence-fromOccurrence)),
+			).Tx())
+		}
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// CALENDAR
+
+func (s *Storage) UserByFeedToken(ctx context.Context, tokenHash string) (*domain.User, error) {
+	const op = "storage.calendar.user"
+	userDB, err := s.client.User.FindFirst(
+		db.User.FeedTokenHash.Equals(tokenHash),
+		db.User.DeletedAt.IsNull(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	user := ValidateUser(*userDB)
+	return &user, nil
+}
+
+func (s *Storage) SetFeedToken(ctx context.Context, userID string, tokenHash string) error {
+	const op = "storage.calendar.set_token"
+	_, err := s.client.User.FindUnique(db.User.ID.Equals(userID)).Update(
+		db.User.FeedTokenHash.Set(tokenHash),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) CalendarTasks(ctx context.Context, userID string, since time.Time) ([]*domain.Task, error) {
+	const op = "storage.calendar.tasks"
+	tasksDB, err := s.client.Task.FindMany(
+		db.Task.UserID.Equals(userID),
+		db.Task.Or(
+			db.Task.Status.Not(db.StatusCompleted),
+			db.Task.CompletedAt.After(since),
+		),
+	).OrderBy(
+		db.Task.PlannedAt.Order(db.ASC),
+	).With(taskRelations()...).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var tasks []*domain.Task
+	for _, taskDB := range tasksDB {
+		task := ValidateTask(taskDB)
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+// NOTIFICATION
+
+func (s *Storage) CreateNotification(ctx context.Context, notification *domain.Notification) error {
+	const op = "storage.notification.create"
+	params := []db.NotificationSetParam{}
+	if notification.TaskID != "" {
+		params = append(params, db.Notification.TaskID.Set(notification.TaskID))
+	}
+	result, err := s.client.Notification.CreateOne(
+		db.Notification.UserID.Set(notification.UserID),
+		db.Notification.Kind.Set(string(notification.Kind)),
+		db.Notification.Message.Set(notification.Message),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	notification.ID = result.ID
+	notification.CreatedAt = result.CreatedAt
+	return nil
+}
+
+func (s *Storage) Notifications(ctx context.Context, userID string, unreadOnly bool, page, limit int) ([]*domain.Notification, error) {
+	const op = "storage.notification.all"
+	where := []db.NotificationWhereParam{db.Notification.UserID.Equals(userID)}
+	if unreadOnly {
+		where = append(where, db.Notification.ReadAt.IsNull())
+	}
+	skip, take := pagination(page, limit)
+	notificationsDB, err := s.client.Notification.FindMany(where...).
+		OrderBy(db.Notification.CreatedAt.Order(db.DESC)).
+		Skip(skip).
+		Take(take).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var notifications []*domain.Notification
+	for _, notificationDB := range notificationsDB {
+		notification := ValidateNotification(notificationDB)
+		notifications = append(notifications, &notification)
+	}
+	return notifications, nil
+}
+
+func (s *Storage) MarkNotificationRead(ctx context.Context, userID string, id string, at time.Time) (bool, error) {
+	const op = "storage.notification.read"
+	result, err := s.client.Notification.FindMany(
+		db.Notification.ID.Equals(id),
+		db.Notification.UserID.Equals(userID),
+	).Update(
+		db.Notification.ReadAt.Set(at),
+	).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) MarkAllNotificationsRead(ctx context.Context, userID string, at time.Time) error {
+	const op = "storage.notification.read_all"
+	_, err := s.client.Notification.FindMany(
+		db.Notification.UserID.Equals(userID),
+		db.Notification.ReadAt.IsNull(),
+	).Update(
+		db.Notification.ReadAt.Set(at),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// REMINDER
+
+type deadlineTaskRow struct {
+	ID              db.RawString   `json:"id"`
+	Title           db.RawString   `json:"title"`
+	UserID          db.RawString   `json:"userID"`
+	CreatorID       db.RawString   `json:"creatorId"`
+	Priority        db.RawString   `json:"priority"`
+	PlannedAt       db.RawDateTime `json:"plannedAt"`
+	ReminderOffsets db.RawString   `json:"reminderOffsets"`
+	RemindedOffsets db.RawString   `json:"remindedOffsets"`
+}
+
+// deadlineTaskColumns — столбцы deadlineTaskRow; массивы отдаются строкой через запятую.
+const deadlineTaskColumns = `t."id", t."title", t."userID", COALESCE(t."creatorId", '') AS "creatorId",
+	t."priority"::text AS "priority", t."plannedAt",
+	array_to_string(t."remindedOffsets", ',') AS "remindedOffsets"`
+
+func (s *Storage) UpcomingTasks(ctx context.Context, from time.Time, to time.Time) ([]*domain.DeadlineTask, error) {
+	const op = "storage.reminder.upcoming"
+	var rows []deadlineTaskRow
+	err := s.client.Prisma.QueryRaw(
+		`SELECT `+deadlineTaskColumns+`, array_to_string(u."reminderOffsets", ',') AS "reminderOffsets"
+		FROM "Task" t JOIN "User" u ON u."id" = t."userID"
+		WHERE t."status" <> 'COMPLETED' AND t."plannedAt" > $1 AND t."plannedAt" <= $2`,
+		from, to,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deadlineTasks(rows), nil
+}
+
+func (s *Storage) MarkReminded(ctx context.Context, taskID string, offset int) (bool, error) {
+	const op = "storage.reminder.mark"
+	result, err := s.client.Prisma.ExecuteRaw(
+		`UPDATE "Task" SET "remindedOffsets" = array_append("remindedOffsets", $2::int)
+		WHERE "id" = $1 AND NOT ($2::int = ANY("remindedOffsets"))`,
+		taskID, offset,
+	).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) MarkOverdue(ctx context.Context, now time.Time) ([]*domain.DeadlineTask, error) {
+	const op = "storage.reminder.overdue"
+	var rows []deadlineTaskRow
+	err := s.client.Prisma.QueryRaw(
+		`UPDATE "Task" t SET "overdueAt" = $1
+		WHERE t."status" <> 'COMPLETED' AND t."plannedAt" < $1 AND t."overdueAt" IS NULL
+		RETURNING `+deadlineTaskColumns,
+		now,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deadlineTasks(rows), nil
+}
+
+func (s *Storage) MarkEscalated(ctx context.Context, before time.Time, now time.Time) ([]*domain.DeadlineTask, error) {
+	const op = "storage.reminder.escalate"
+	var rows []deadlineTaskRow
+	err := s.client.Prisma.QueryRaw(
+		`UPDATE "Task" t SET "escalatedAt" = $2
+		WHERE t."priority" = 'HIGH' AND t."status" <> 'COMPLETED'
+			AND t."overdueAt" <= $1 AND t."escalatedAt" IS NULL
+		RETURNING `+deadlineTaskColumns,
+		before, now,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deadlineTasks(rows), nil
+}
+
+func deadlineTasks(rows []deadlineTaskRow) []*domain.DeadlineTask {
+	tasks := make([]*domain.DeadlineTask, 0, len(rows))
+	for _, row := range rows {
+		tasks = append(tasks, &domain.DeadlineTask{
+			ID:              string(row.ID),
+			Title:           string(row.Title),
+			UserID:          string(row.UserID),
+			CreatorID:       string(row.CreatorID),
+			Priority:        domain.Priority(row.Priority),
+			PlannedAt:       row.PlannedAt.Time,
+			ReminderOffsets: parseIntList(string(row.ReminderOffsets)),
+			RemindedOffsets: parseIntList(string(row.RemindedOffsets)),
+		})
+	}
+	return tasks
+}
+
+// parseIntList разбирает результат array_to_string для целочисленного массива.
+func parseIntList(s string) []int {
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		if value, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// CHECKLIST
+
+func (s *Storage) ChecklistItems(ctx context.Context, taskID string) ([]*domain.ChecklistItem, error) {
+	const op = "storage.checklist.all"
+	itemsDB, err := s.client.ChecklistItem.FindMany(
+		db.ChecklistItem.TaskID.Equals(taskID),
+	).OrderBy(
+		db.ChecklistItem.Position.Order(db.ASC),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var items []*domain.ChecklistItem
+	for _, itemDB := range itemsDB {
+		item := ValidateChecklistItem(itemDB)
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+func (s *Storage) CreateChecklistItem(ctx context.Context, item *domain.ChecklistItem) (*domain.ChecklistItem, error) {
+	const op = "storage.checklist.create"
+	itemDB, err := s.client.ChecklistItem.CreateOne(
+		db.ChecklistItem.Task.Link(db.Task.ID.Equals(item.TaskID)),
+		db.ChecklistItem.Text.Set(item.Text),
+		db.ChecklistItem.Position.Set(item.Position),
+		db.ChecklistItem.Done.Set(item.Done),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created := ValidateChecklistItem(*itemDB)
+	return &created, nil
+}
+
+func (s *Storage) UpdateChecklistItem(ctx context.Context, item *domain.ChecklistItem) error {
+	const op = "storage.checklist.update"
+	_, err := s.client.ChecklistItem.FindUnique(db.ChecklistItem.ID.Equals(item.ID)).Update(
+		db.ChecklistItem.Text.Set(item.Text),
+		db.ChecklistItem.Done.Set(item.Done),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// PositionChecklistItems записывает новый порядок пунктов одной транзакцией.
+func (s *Storage) PositionChecklistItems(ctx context.Context, positions map[string]int) error {
+	const op = "storage.checklist.position"
+	if len(positions) == 0 {
+		return nil
+	}
+	txs := make([]db.PrismaTransaction, 0, len(positions))
+	for id, position := range positions {
+		txs = append(txs, s.client.ChecklistItem.FindUnique(db.ChecklistItem.ID.Equals(id)).Update(
+			db.ChecklistItem.Position.Set(position),
+		).Tx())
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteChecklistItem(ctx context.Context, id string) error {
+	const op = "storage.checklist.delete"
+	_, err := s.client.ChecklistItem.FindUnique(db.ChecklistItem.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// WATCH
+
+func (s *Storage) AddWatchers(ctx context.Context, taskID string, userIDs []string) error {
+	const op = "storage.watch.add"
+	now := time.Now()
+	txs := make([]db.PrismaTransaction, 0, len(userIDs))
+	for _, userID := range userIDs {
+		txs = append(txs, s.client.Prisma.ExecuteRaw(
+			`INSERT INTO "TaskWatcher" ("taskId", "userId", "createdAt") VALUES ($1, $2, $3)
+			ON CONFLICT ("taskId", "userId") DO NOTHING`,
+			taskID, userID, now,
+		).Tx())
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) RemoveWatcher(ctx context.Context, taskID string, userID string) error {
+	const op = "storage.watch.remove"
+	_, err := s.client.TaskWatcher.FindMany(
+		db.TaskWatcher.TaskID.Equals(taskID),
+		db.TaskWatcher.UserID.Equals(userID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) Watchers(ctx context.Context, taskID string) ([]*domain.TaskWatcher, error) {
+	const op = "storage.watch.all"
+	watchersDB, err := s.client.TaskWatcher.FindMany(
+		db.TaskWatcher.TaskID.Equals(taskID),
+	).With(
+		db.TaskWatcher.User.Fetch(),
+	).OrderBy(db.TaskWatcher.CreatedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	watchers := make([]*domain.TaskWatcher, 0, len(watchersDB))
+	for _, watcherDB := range watchersDB {
+		watcher := ValidateTaskWatcher(watcherDB)
+		watchers = append(watchers, &watcher)
+	}
+	return watchers, nil
+}
+
+func (s *Storage) CreateComment(ctx context.Context, comment *domain.TaskComment) (*domain.TaskComment, error) {
+	const op = "storage.comment.create"
+	created, err := s.client.TaskComment.CreateOne(
+		db.TaskComment.Task.Link(db.Task.ID.Equals(comment.TaskID)),
+		db.TaskComment.Author.Link(db.User.ID.Equals(comment.AuthorID)),
+		db.TaskComment.Body.Set(comment.Body),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	commentDB, err := s.client.TaskComment.FindUnique(
+		db.TaskComment.ID.Equals(created.ID),
+	).With(
+		db.TaskComment.Author.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	result := ValidateTaskComment(*commentDB)
+	return &result, nil
+}
+
+func (s *Storage) Comments(ctx context.Context, taskID string) ([]*domain.TaskComment, error) {
+	const op = "storage.comment.all"
+	commentsDB, err := s.client.TaskComment.FindMany(
+		db.TaskComment.TaskID.Equals(taskID),
+	).With(
+		db.TaskComment.Author.Fetch(),
+	).OrderBy(db.TaskComment.CreatedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	comments := make([]*domain.TaskComment, 0, len(commentsDB))
+	for _, commentDB := range commentsDB {
+		comment := ValidateTaskComment(commentDB)
+		comments = append(comments, &comment)
+	}
+	return comments, nil
+}
+
+// ARTICLE LINK
+
+func (s *Storage) ArticlesByIDs(ctx context.Context, ids []string) ([]*domain.Article, error) {
+	const op = "storage.article.by_ids"
+	articlesDB, err := s.client.Article.FindMany(db.Article.ID.In(ids)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	articles := make([]*domain.Article, 0, len(articlesDB))
+	for _, articleDB := range articlesDB {
+		article := ValidateArticle(articleDB)
+		articles = append(articles, &article)
+	}
+	return articles, nil
+}
+
+// SetArticleLink создаёт связь или обновляет тип и сверенную ревизию существующей.
+func (s *Storage) SetArticleLink(ctx context.Context, link *domain.TaskArticleLink) error {
+	const op = "storage.article_link.set"
+	link.CreatedAt = time.Now()
+	_, err := s.client.Prisma.ExecuteRaw(
+		`INSERT INTO "TaskArticleLink" ("taskId", "articleId", "articleTitle", "kind", "linkedRevision", "createdById", "createdAt")
+		VALUES ($1, $2, $3, $4::"ArticleLinkKind", $5, $6, $7)
+		ON CONFLICT ("taskId", "articleId") DO UPDATE SET
+			"articleTitle" = EXCLUDED."articleTitle",
+			"kind" = EXCLUDED."kind",
+			"linkedRevision" = EXCLUDED."linkedRevision"`,
+		link.TaskID, link.ArticleID, link.ArticleTitle, string(link.Kind), link.LinkedRevision, link.CreatedByID, link.CreatedAt,
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteArticleLink(ctx context.Context, taskID string, articleID string) (bool, error) {
+	const op = "storage.article_link.delete"
+	result, err := s.client.TaskArticleLink.FindMany(
+		db.TaskArticleLink.TaskID.Equals(taskID),
+		db.TaskArticleLink.ArticleID.Equals(articleID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) TaskArticleLinks(ctx context.Context, taskID string) ([]*domain.TaskArticleLink, error) {
+	const op = "storage.article_link.task"
+	linksDB, err := s.client.TaskArticleLink.FindMany(
+		db.TaskArticleLink.TaskID.Equals(taskID),
+	).With(
+		db.TaskArticleLink.Task.Fetch(),
+	).OrderBy(db.TaskArticleLink.CreatedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	links, err := s.articleLinks(ctx, linksDB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return links, nil
+}
+
+func (s *Storage) ArticleTaskLinks(ctx context.Context, articleID string) ([]*domain.TaskArticleLink, error) {
+	const op = "storage.article_link.article"
+	linksDB, err := s.client.TaskArticleLink.FindMany(
+		db.TaskArticleLink.ArticleID.Equals(articleID),
+	).With(
+		db.TaskArticleLink.Task.Fetch(),
+	).OrderBy(db.TaskArticleLink.CreatedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	links, err := s.articleLinks(ctx, linksDB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return links, nil
+}
+
+// articleLinks дополняет связи текущими заголовком и ревизией статей;
+// у удалённых статей остаются сохранённый заголовок и ревизия 0.
+func (s *Storage) articleLinks(ctx context.Context, linksDB []db.TaskArticleLinkModel) ([]*domain.TaskArticleLink, error) {
+	ids := make([]string, 0, len(linksDB))
+	for _, linkDB := range linksDB {
+		ids = append(ids, linkDB.ArticleID)
+	}
+	articles, err := s.ArticlesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+	links := make([]*domain.TaskArticleLink, 0, len(linksDB))
+	for _, linkDB := range linksDB {
+		link := ValidateTaskArticleLink(linkDB)
+		if article, ok := byID[link.ArticleID]; ok {
+			link.ArticleTitle = article.Title
+			link.ArticleRevision = article.Revision
+		}
+		links = append(links, &link)
+	}
+	return links, nil
+}
+
+// TASK TEMPLATE
+
+// CreateTemplate сохраняет шаблон, затем его шаги одной транзакцией.
+// Если шаги сохранить не удалось, шаблон удаляется.
+func (s *Storage) CreateTemplate(ctx context.Context, template *domain.TaskTemplate) (*domain.TaskTemplate, error) {
+	const op = "storage.task_template.create"
+	params := append(templateParams(template), db.TaskTemplate.Name.Set(template.Name))
+	if template.ProjectID != "" {
+		params = append(params, db.TaskTemplate.Project.Link(db.Project.ID.Equals(template.ProjectID)))
+	}
+	templateDB, err := s.client.TaskTemplate.CreateOne(
+		db.TaskTemplate.TitlePattern.Set(template.TitlePattern),
+		db.TaskTemplate.CreatedByID.Set(template.CreatedByID),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	var txs []db.PrismaTransaction
+	for _, step := range template.Steps {
+		params := templateParams(step)
+		params = append(params,
+			db.TaskTemplate.Parent.Link(db.TaskTemplate.ID.Equals(templateDB.ID)),
+			db.TaskTemplate.Position.Set(step.Position),
+		)
+		if template.ProjectID != "" {
+			params = append(params, db.TaskTemplate.Project.Link(db.Project.ID.Equals(template.ProjectID)))
+		}
+		txs = append(txs, s.client.TaskTemplate.CreateOne(
+			db.TaskTemplate.TitlePattern.Set(step.TitlePattern),
+			db.TaskTemplate.CreatedByID.Set(template.CreatedByID),
+			params...,
+		).Tx())
+	}
+	if len(txs) > 0 {
+		if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+			_, _ = s.client.TaskTemplate.FindUnique(db.TaskTemplate.ID.Equals(templateDB.ID)).Delete().Exec(ctx)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	created, err := s.Template(ctx, templateDB.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+func templateParams(template *domain.TaskTemplate) []db.TaskTemplateSetParam {
+	return []db.TaskTemplateSetParam{
+		db.TaskTemplate.Content.Set(template.Content),
+		db.TaskTemplate.Priority.Set(db.Priority(template.Priority)),
+		db.TaskTemplate.Checklist.Set(template.Checklist),
+		db.TaskTemplate.DueOffsetHours.Set(template.DueOffsetHours),
+	}
+}
+
+func (s *Storage) Template(ctx context.Context, id string) (*domain.TaskTemplate, error) {
+	const op = "storage.task_template.get"
+	templateDB, err := s.client.TaskTemplate.FindUnique(
+		db.TaskTemplate.ID.Equals(id),
+	).With(
+		db.TaskTemplate.Steps.Fetch().OrderBy(db.TaskTemplate.Position.Order(db.ASC)),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	template := ValidateTaskTemplate(*templateDB)
+	return &template, nil
+}
+
+func (s *Storage) Templates(ctx context.Context, projectID string) ([]*domain.TaskTemplate, error) {
+	const op = "storage.task_template.all"
+	var scope db.TaskTemplateWhereParam = db.TaskTemplate.ProjectID.IsNull()
+	if projectID != "" {
+		scope = db.TaskTemplate.Or(db.TaskTemplate.ProjectID.IsNull(), db.TaskTemplate.ProjectID.Equals(projectID))
+	}
+	templatesDB, err := s.client.TaskTemplate.FindMany(
+		db.TaskTemplate.ParentID.IsNull(),
+		scope,
+	).With(
+		db.TaskTemplate.Steps.Fetch().OrderBy(db.TaskTemplate.Position.Order(db.ASC)),
+	).OrderBy(db.TaskTemplate.Name.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	templates := make([]*domain.TaskTemplate, 0, len(templatesDB))
+	for _, templateDB := range templatesDB {
+		template := ValidateTaskTemplate(templateDB)
+		templates = append(templates, &template)
+	}
+	return templates, nil
+}
+
+func (s *Storage) DeleteTemplate(ctx context.Context, id string) error {
+	const op = "storage.task_template.delete"
+	_, err := s.client.TaskTemplate.FindUnique(db.TaskTemplate.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// PROJECT
+
+// CreateProject создаёт проект и делает автора владельцем одним запросом.
+func (s *Storage) CreateProject(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	const op = "storage.project.create"
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`WITH created AS (
+			INSERT INTO "Project" ("id", "key", "name", "description", "createdById", "createdAt")
+			VALUES (gen_random_uuid()::text, $1, $2, $3, $4, $5)
+			RETURNING "id"
+		), owner AS (
+			INSERT INTO "ProjectMember" ("projectId", "userId", "role", "joinedAt")
+			SELECT "id", $4, 'OWNER', $5 FROM created
+		)
+		SELECT "id" FROM created`,
+		project.Key, project.Name, project.Description, project.CreatedByID, time.Now(),
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: project was not created", op)
+	}
+	created, err := s.Project(ctx, string(rows[0].ID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+func (s *Storage) Project(ctx context.Context, id string) (*domain.Project, error) {
+	const op = "storage.project.get"
+	projectDB, err := s.client.Project.FindUnique(db.Project.ID.Equals(id)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	project := ValidateProject(*projectDB)
+	return &project, nil
+}
+
+func (s *Storage) ProjectByKey(ctx context.Context, key string) (*domain.Project, error) {
+	const op = "storage.project.by_key"
+	projectDB, err := s.client.Project.FindUnique(db.Project.Key.Equals(key)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	project := ValidateProject(*projectDB)
+	return &project, nil
+}
+
+func (s *Storage) UserProjects(ctx context.Context, userID string) ([]*domain.Project, error) {
+	const op = "storage.project.user"
+	projectsDB, err := s.client.Project.FindMany(
+		db.Project.Members.Some(db.ProjectMember.UserID.Equals(userID)),
+	).OrderBy(db.Project.Key.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	projects := make([]*domain.Project, 0, len(projectsDB))
+	for _, projectDB := range projectsDB {
+		project := ValidateProject(projectDB)
+		projects = append(projects, &project)
+	}
+	return projects, nil
+}
+
+func (s *Storage) ProjectMember(ctx context.Context, projectID string, userID string) (*domain.ProjectMembership, error) {
+	const op = "storage.project.member"
+	memberDB, err := s.client.ProjectMember.FindFirst(
+		db.ProjectMember.ProjectID.Equals(projectID),
+		db.ProjectMember.UserID.Equals(userID),
+	).With(
+		db.ProjectMember.User.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	member := ValidateProjectMember(*memberDB)
+	return &member, nil
+}
+
+func (s *Storage) ProjectMembers(ctx context.Context, projectID string) ([]*domain.ProjectMembership, error) {
+	const op = "storage.project.members"
+	membersDB, err := s.client.ProjectMember.FindMany(
+		db.ProjectMember.ProjectID.Equals(projectID),
+	).With(
+		db.ProjectMember.User.Fetch(),
+	).OrderBy(db.ProjectMember.JoinedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	members := make([]*domain.ProjectMembership, 0, len(membersDB))
+	for _, memberDB := range membersDB {
+		member := ValidateProjectMember(memberDB)
+		members = append(members, &member)
+	}
+	return members, nil
+}
+
+func (s *Storage) SetProjectMember(ctx context.Context, member *domain.ProjectMembership) error {
+	const op = "storage.project.set_member"
+	_, err := s.client.Prisma.ExecuteRaw(
+		`INSERT INTO "ProjectMember" ("projectId", "userId", "role", "joinedAt")
+		VALUES ($1, $2, $3::"ProjectRole", $4)
+		ON CONFLICT ("projectId", "userId") DO UPDATE SET "role" = EXCLUDED."role"`,
+		member.ProjectID, member.UserID, string(member.Role), time.Now(),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// lastOwnerGuard — условие, что участник $2 проекта $1 не единственный владелец.
+// Проверка идёт под блокировкой проекта, чтобы встречные запросы не оставили его без владельца.
+const lastOwnerGuard = `("role" <> 'OWNER' OR (
+	SELECT COUNT(*) FROM "ProjectMember" WHERE "projectId" = $1 AND "role" = 'OWNER'
+) > 1)`
+
+func (s *Storage) RemoveProjectMember(ctx context.Context, projectID string, userID string) (bool, error) {
+	const op = "storage.project.remove_member"
+	lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext($1))`, "project:"+projectID).Tx()
+	remove := s.client.Prisma.ExecuteRaw(
+		`DELETE FROM "ProjectMember" WHERE "projectId" = $1 AND "userId" = $2 AND `+lastOwnerGuard,
+		projectID, userID,
+	).Tx()
+	if err := s.client.Prisma.Transaction(lock, remove).Exec(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return remove.Result().Count > 0, nil
+}
+
+func (s *Storage) DemoteProjectOwner(ctx context.Context, member *domain.ProjectMembership) (bool, error) {
+	const op = "storage.project.demote_owner"
+	lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext($1))`, "project:"+member.ProjectID).Tx()
+	update := s.client.Prisma.ExecuteRaw(
+		`UPDATE "ProjectMember" SET "role" = $3::"ProjectRole"
+		WHERE "projectId" = $1 AND "userId" = $2 AND `+lastOwnerGuard,
+		member.ProjectID, member.UserID, string(member.Role),
+	).Tx()
+	if err := s.client.Prisma.Transaction(lock, update).Exec(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return update.Result().Count > 0, nil
+}
+
+func (s *Storage) NextTaskNumber(ctx context.Context, projectID string) (int, error) {
+	const op = "storage.project.next_number"
+	var rows []struct {
+		TaskCounter db.RawInt `json:"taskCounter"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`UPDATE "Project" SET "taskCounter" = "taskCounter" + 1 WHERE "id" = $1 RETURNING "taskCounter"`,
+		projectID,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("%s: %w", op, db.ErrNotFound)
+	}
+	return int(rows[0].TaskCounter), nil
+}
+
+func (s *Storage) TaskByKey(ctx context.Context, projectKey string, number int) (*domain.Task, error) {
+	const op = "storage.project.task_by_key"
+	taskDB, err := s.client.Task.FindFirst(
+		db.Task.Number.Equals(number),
+		db.Task.Project.Where(db.Project.Key.Equals(projectKey)),
+	).With(taskRelations()...).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	task := ValidateTask(*taskDB)
+	return &task, nil
+}
+
+// LABEL
+
+func (s *Storage) CreateLabel(ctx context.Context, label *domain.Label) (*domain.Label, error) {
+	const op = "storage.label.create"
+	var params []db.LabelSetParam
+	if label.ProjectID != "" {
+		params = append(params, db.Label.Project.Link(db.Project.ID.Equals(label.ProjectID)))
+	}
+	labelDB, err := s.client.Label.CreateOne(
+		db.Label.Name.Set(label.Name),
+		db.Label.Color.Set(label.Color),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created := ValidateLabel(*labelDB)
+	return &created, nil
+}
+
+func (s *Storage) UpdateLabel(ctx context.Context, label *domain.Label) error {
+	const op = "storage.label.update"
+	_, err := s.client.Label.FindUnique(db.Label.ID.Equals(label.ID)).Update(
+		db.Label.Name.Set(label.Name),
+		db.Label.Color.Set(label.Color),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) Label(ctx context.Context, id string) (*domain.Label, error) {
+	const op = "storage.label.get"
+	labelDB, err := s.client.Label.FindUnique(db.Label.ID.Equals(id)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	label := ValidateLabel(*labelDB)
+	return &label, nil
+}
+
+func (s *Storage) LabelByName(ctx context.Context, projectID string, name string) (*domain.Label, error) {
+	const op = "storage.label.by_name"
+	var scope db.LabelWhereParam = db.Label.ProjectID.IsNull()
+	if projectID != "" {
+		scope = db.Label.ProjectID.Equals(projectID)
+	}
+	labelDB, err := s.client.Label.FindFirst(
+		db.Label.Name.Equals(name),
+		scope,
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	label := ValidateLabel(*labelDB)
+	return &label, nil
+}
+
+func (s *Storage) Labels(ctx context.Context, projectID string) ([]*domain.Label, error) {
+	const op = "storage.label.all"
+	var scope db.LabelWhereParam = db.Label.ProjectID.IsNull()
+	if projectID != "" {
+		scope = db.Label.Or(db.Label.ProjectID.IsNull(), db.Label.ProjectID.Equals(projectID))
+	}
+	labelsDB, err := s.client.Label.FindMany(scope).OrderBy(db.Label.Name.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return validateLabels(labelsDB), nil
+}
+
+func (s *Storage) LabelsByIDs(ctx context.Context, ids []string) ([]*domain.Label, error) {
+	const op = "storage.label.by_ids"
+	labelsDB, err := s.client.Label.FindMany(db.Label.ID.In(ids)).OrderBy(db.Label.Name.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return validateLabels(labelsDB), nil
+}
+
+func validateLabels(labelsDB []db.LabelModel) []*domain.Label {
+	labels := make([]*domain.Label, 0, len(labelsDB))
+	for _, labelDB := range labelsDB {
+		label := ValidateLabel(labelDB)
+		labels = append(labels, &label)
+	}
+	return labels
+}
+
+func (s *Storage) DeleteLabel(ctx context.Context, id string) error {
+	const op = "storage.label.delete"
+	_, err := s.client.Label.FindUnique(db.Label.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SetTaskLabels заменяет набор меток задачи в одной транзакции.
+func (s *Storage) SetTaskLabels(ctx context.Context, taskID string, labelIDs []string) error {
+	const op = "storage.label.set_task"
+	txs := []db.PrismaTransaction{
+		s.client.TaskLabel.FindMany(db.TaskLabel.TaskID.Equals(taskID)).Delete().Tx(),
+	}
+	for _, labelID := range labelIDs {
+		txs = append(txs, s.client.TaskLabel.CreateOne(
+			db.TaskLabel.Task.Link(db.Task.ID.Equals(taskID)),
+			db.TaskLabel.Label.Link(db.Label.ID.Equals(labelID)),
+		).Tx())
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// CUSTOM FIELD
+
+func (s *Storage) CreateField(ctx context.Context, field *domain.CustomField) (*domain.CustomField, error) {
+	const op = "storage.custom_field.create"
+	params := []db.CustomFieldSetParam{
+		db.CustomField.Options.Set(field.Options),
+	}
+	if field.ProjectID != "" {
+		params = append(params, db.CustomField.Project.Link(db.Project.ID.Equals(field.ProjectID)))
+	}
+	fieldDB, err := s.client.CustomField.CreateOne(
+		db.CustomField.Name.Set(field.Name),
+		db.CustomField.Type.Set(db.FieldType(field.Type)),
+		params...,
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created := ValidateCustomField(*fieldDB)
+	return &created, nil
+}
+
+func (s *Storage) Field(ctx context.Context, id string) (*domain.CustomField, error) {
+	const op = "storage.custom_field.get"
+	fieldDB, err := s.client.CustomField.FindUnique(db.CustomField.ID.Equals(id)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	field := ValidateCustomField(*fieldDB)
+	return &field, nil
+}
+
+func (s *Storage) FieldByName(ctx context.Context, projectID string, name string) (*domain.CustomField, error) {
+	const op = "storage.custom_field.by_name"
+	var scope db.CustomFieldWhereParam = db.CustomField.ProjectID.IsNull()
+	if projectID != "" {
+		scope = db.CustomField.ProjectID.Equals(projectID)
+	}
+	fieldDB, err := s.client.CustomField.FindFirst(
+		db.CustomField.Name.Equals(name),
+		scope,
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	field := ValidateCustomField(*fieldDB)
+	return &field, nil
+}
+
+func (s *Storage) Fields(ctx context.Context, projectID string) ([]*domain.CustomField, error) {
+	const op = "storage.custom_field.all"
+	var scope db.CustomFieldWhereParam = db.CustomField.ProjectID.IsNull()
+	if projectID != "" {
+		scope = db.CustomField.Or(db.CustomField.ProjectID.IsNull(), db.CustomField.ProjectID.Equals(projectID))
+	}
+	fieldsDB, err := s.client.CustomField.FindMany(scope).OrderBy(db.CustomField.Name.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	fields := make([]*domain.CustomField, 0, len(fieldsDB))
+	for _, fieldDB := range fieldsDB {
+		field := ValidateCustomField(fieldDB)
+		fields = append(fields, &field)
+	}
+	return fields, nil
+}
+
+func (s *Storage) DeleteField(ctx context.Context, id string) error {
+	const op = "storage.custom_field.delete"
+	_, err := s.client.CustomField.FindUnique(db.CustomField.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) SetTaskFields(ctx context.Context, taskID string, values map[string]string) error {
+	const op = "storage.custom_field.set_task"
+	var txs []db.PrismaTransaction
+	for fieldID, value := range values {
+		if value == "" {
+			txs = append(txs, s.client.TaskFieldValue.FindMany(
+				db.TaskFieldValue.TaskID.Equals(taskID),
+				db.TaskFieldValue.FieldID.Equals(fieldID),
+			).Delete().Tx())
+			continue
+		}
+		txs = append(txs, s.client.Prisma.ExecuteRaw(
+			`INSERT INTO "TaskFieldValue" ("taskId", "fieldId", "value") VALUES ($1, $2, $3)
+			ON CONFLICT ("taskId", "fieldId") DO UPDATE SET "value" = EXCLUDED."value"`,
+			taskID, fieldID, value,
+		).Tx())
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+	if err := s.client.Prisma.Transaction(txs...).Exec(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) TaskFields(ctx context.Context, taskID string) ([]*domain.FieldValue, error) {
+	const op = "storage.custom_field.task"
+	valuesDB, err := s.client.TaskFieldValue.FindMany(
+		db.TaskFieldValue.TaskID.Equals(taskID),
+	).With(
+		db.TaskFieldValue.Field.Fetch(),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	values := make([]*domain.FieldValue, 0, len(valuesDB))
+	for _, valueDB := range valuesDB {
+		value := ValidateFieldValue(valueDB)
+		values = append(values, &value)
+	}
+	return values, nil
+}
+
+// SPRINT
+
+func (s *Storage) CreateSprint(ctx context.Context, sprint *domain.Sprint) (*domain.Sprint, error) {
+	const op = "storage.sprint.create"
+	sprintDB, err := s.client.Sprint.CreateOne(
+		db.Sprint.Name.Set(sprint.Name),
+		db.Sprint.StartAt.Set(sprint.StartAt),
+		db.Sprint.EndAt.Set(sprint.EndAt),
+		db.Sprint.CreatedByID.Set(sprint.CreatedByID),
+		db.Sprint.Goal.Set(sprint.Goal),
+		db.Sprint.Unit.Set(db.EstimateUnit(sprint.Unit)),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created := ValidateSprint(*sprintDB)
+	return &created, nil
+}
+
+func (s *Storage) UpdateSprint(ctx context.Context, sprint *domain.Sprint) error {
+	const op = "storage.sprint.update"
+	_, err := s.client.Sprint.FindUnique(db.Sprint.ID.Equals(sprint.ID)).Update(
+		db.Sprint.Name.Set(sprint.Name),
+		db.Sprint.Goal.Set(sprint.Goal),
+		db.Sprint.StartAt.Set(sprint.StartAt),
+		db.Sprint.EndAt.Set(sprint.EndAt),
+		db.Sprint.Unit.Set(db.EstimateUnit(sprint.Unit)),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) Sprint(ctx context.Context, id string) (*domain.Sprint, error) {
+	const op = "storage.sprint.get"
+	sprintDB, err := s.client.Sprint.FindUnique(db.Sprint.ID.Equals(id)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	sprint := ValidateSprint(*sprintDB)
+	return &sprint, nil
+}
+
+func (s *Storage) Sprints(ctx context.Context) ([]*domain.Sprint, error) {
+	const op = "storage.sprint.all"
+	sprintsDB, err := s.client.Sprint.FindMany().
+		OrderBy(db.Sprint.StartAt.Order(db.DESC)).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return validateSprints(sprintsDB), nil
+}
+
+func (s *Storage) FinishedSprints(ctx context.Context, before time.Time, limit int) ([]*domain.Sprint, error) {
+	const op = "storage.sprint.finished"
+	sprintsDB, err := s.client.Sprint.FindMany(
+		db.Sprint.EndAt.Lte(before),
+	).OrderBy(db.Sprint.EndAt.Order(db.DESC)).Take(limit).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return validateSprints(sprintsDB), nil
+}
+
+func validateSprints(sprintsDB []db.SprintModel) []*domain.Sprint {
+	sprints := make([]*domain.Sprint, 0, len(sprintsDB))
+	for _, sprintDB := range sprintsDB {
+		sprint := ValidateSprint(sprintDB)
+		sprints = append(sprints, &sprint)
+	}
+	return sprints
+}
+
+func (s *Storage) DeleteSprint(ctx context.Context, id string) error {
+	const op = "storage.sprint.delete"
+	_, err := s.client.Sprint.FindUnique(db.Sprint.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) PlanTask(ctx context.Context, taskID string, sprintID string, estimate *float64) error {
+	const op = "storage.sprint.plan_task"
+	sprint := db.Task.Sprint.Unlink()
+	if sprintID != "" {
+		sprint = db.Task.Sprint.Link(db.Sprint.ID.Equals(sprintID))
+	}
+	_, err := s.client.Task.FindUnique(db.Task.ID.Equals(taskID)).Update(
+		sprint,
+		db.Task.Estimate.SetOptional(estimate),
+	).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) SprintTasks(ctx context.Context, sprintIDs []string) ([]*domain.Task, error) {
+	const op = "storage.sprint.tasks"
+	tasksDB, err := s.client.Task.FindMany(
+		db.Task.SprintID.In(sprintIDs),
+	).With(taskRelations()...).OrderBy(db.Task.Rank.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	tasks := make([]*domain.Task, 0, len(tasksDB))
+	for _, taskDB := range tasksDB {
+		task := ValidateTask(taskDB)
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+func (s *Storage) TasksTransitions(ctx context.Context, taskIDs []string) ([]*domain.TaskTransition, error) {
+	const op = "storage.sprint.transitions"
+	transitionsDB, err := s.client.TaskTransition.FindMany(
+		db.TaskTransition.TaskID.In(taskIDs),
+	).OrderBy(db.TaskTransition.ChangedAt.Order(db.ASC)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	transitions := make([]*domain.TaskTransition, 0, len(transitionsDB))
+	for _, transitionDB := range transitionsDB {
+		transition := ValidateTaskTransition(transitionDB)
+		transitions = append(transitions, &transition)
+	}
+	return transitions, nil
+}
+
+// REPORT
+
+// workloadSQL считает показатели по пользователям одним запросом:
+// $1, $2 — период, $3 — текущий момент, $4 — проект, $5 — пользователь (пустые — без ограничения).
+// Момент завершения — последний переход в COMPLETED (для задач, созданных
+// завершёнными, — completedAt), начало работы — первый переход в CURRENT.
+const workloadSQL = `WITH report_users AS (
+	SELECT u."id", u."fullName" FROM "User" u
+	WHERE u."deletedAt" IS NULL
+		AND ($5 = '' OR u."id" = $5)
+		AND ($4 = '' OR u."id" IN (
+			SELECT m."userId" FROM "ProjectMember" m WHERE m."projectId" = $4
+			UNION SELECT t."userID" FROM "Task" t WHERE t."projectId" = $4))
+), opened AS (
+	SELECT t."userID" AS "userId",
+		COUNT(*) FILTER (WHERE t."priority" = 'HIGH') AS "high",
+		COUNT(*) FILTER (WHERE t."priority" = 'MIDDLE') AS "middle",
+		COUNT(*) FILTER (WHERE t."priority" = 'LOW') AS "low",
+		COUNT(*) FILTER (WHERE t."plannedAt" < $3) AS "overdue"
+	FROM "Task" t
+	WHERE t."status" <> 'COMPLETED' AND ($4 = '' OR t."projectId" = $4)
+	GROUP BY t."userID"
+), done AS (
+	SELECT t."userID" AS "userId",
+		COUNT(*) AS "completed",
+		COUNT(*) FILTER (WHERE c."at" > t."plannedAt") AS "late",
+		SUM(EXTRACT(EPOCH FROM c."at" - t."createdAt")) AS "lead",
+		COUNT(s."at") AS "cycleTasks",
+		SUM(EXTRACT(EPOCH FROM c."at" - s."at")) AS "cycle"
+	FROM "Task" t
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(MAX(tr."changedAt"), t."completedAt") AS "at" FROM "TaskTransition" tr
+		WHERE tr."taskId" = t."id" AND tr."toStatus" = 'COMPLETED'
+	) c
+	LEFT JOIN LATERAL (
+		SELECT MIN(tr."changedAt") AS "at" FROM "TaskTransition" tr
+		WHERE tr."taskId" = t."id" AND tr."toStatus" = 'CURRENT' AND tr."changedAt" <= c."at"
+	) s ON TRUE
+	WHERE t."status" = 'COMPLETED' AND t."completedAt" >= $1 AND t."completedAt" < $2
+		AND ($4 = '' OR t."projectId" = $4)
+	GROUP BY t."userID"
+), articles AS (
+	SELECT h."userId",
+		COUNT(*) FILTER (WHERE h."eventType" = 'CREATE') AS "created",
+		COUNT(*) FILTER (WHERE h."eventType" = 'UPDATED') AS "edited"
+	FROM "ArticleHistory" h
+	WHERE h."changedAt" >= $1 AND h."changedAt" < $2
+	GROUP BY h."userId"
+)
+SELECT u."id" AS "userId", u."fullName" AS "userName",
+	COALESCE(o."high", 0) AS "openHigh",
+	COALESCE(o."middle", 0) AS "openMiddle",
+	COALESCE(o."low", 0) AS "openLow",
+	COALESCE(o."overdue", 0) AS "overdue",
+	COALESCE(d."completed", 0) AS "completed",
+	COALESCE(d."late", 0) AS "completedLate",
+	COALESCE(d."lead", 0)::float8 AS "leadSeconds",
+	COALESCE(d."cycleTasks", 0) AS "cycleTasks",
+	COALESCE(d."cycle", 0)::float8 AS "cycleSeconds",
+	COALESCE(a."created", 0) AS "articlesCreated",
+	COALESCE(a."edited", 0) AS "articlesEdited"
+FROM report_users u
+LEFT JOIN opened o ON o."userId" = u."id"
+LEFT JOIN done d ON d."userId" = u."id"
+LEFT JOIN articles a ON a."userId" = u."id"
+ORDER BY u."fullName", u."id"`
+
+func (s *Storage) WorkloadStats(ctx context.Context, filter domain.ReportFilter, now time.Time) ([]*domain.WorkloadStats, error) {
+	const op = "storage.report.workload"
+	var rows []struct {
+		UserID          db.RawString `json:"userId"`
+		UserName        db.RawString `json:"userName"`
+		OpenHigh        db.RawBigInt `json:"openHigh"`
+		OpenMiddle      db.RawBigInt `json:"openMiddle"`
+		OpenLow         db.RawBigInt `json:"openLow"`
+		Overdue         db.RawBigInt `json:"overdue"`
+		Completed       db.RawBigInt `json:"completed"`
+		CompletedLate   db.RawBigInt `json:"completedLate"`
+		LeadSeconds     db.RawFloat  `json:"leadSeconds"`
+		CycleTasks      db.RawBigInt `json:"cycleTasks"`
+		CycleSeconds    db.RawFloat  `json:"cycleSeconds"`
+		ArticlesCreated db.RawBigInt `json:"articlesCreated"`
+		ArticlesEdited  db.RawBigInt `json:"articlesEdited"`
+	}
+	err := s.client.Prisma.QueryRaw(workloadSQL,
+		filter.From, filter.To, now, filter.ProjectID, filter.UserID,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stats := make([]*domain.WorkloadStats, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, &domain.WorkloadStats{
+			UserID:          string(row.UserID),
+			UserName:        string(row.UserName),
+			OpenHigh:        int(row.OpenHigh),
+			OpenMiddle:      int(row.OpenMiddle),
+			OpenLow:         int(row.OpenLow),
+			Overdue:         int(row.Overdue),
+			Completed:       int(row.Completed),
+			CompletedLate:   int(row.CompletedLate),
+			LeadSeconds:     float64(row.LeadSeconds),
+			CycleTasks:      int(row.CycleTasks),
+			CycleSeconds:    float64(row.CycleSeconds),
+			ArticlesCreated: int(row.ArticlesCreated),
+			ArticlesEdited:  int(row.ArticlesEdited),
+		})
+	}
+	return stats, nil
+}
+
+// AUDIT
+
+// auditSnapshot сериализует снимок полей для базы и возвращает его в том
+// виде, в каком он будет прочитан обратно: по нему считается хеш записи.
+// Пустая строка — снимка нет.
+func auditSnapshot(fields map[string]any) (string, map[string]any, error) {
+	if fields == nil {
+		return "", nil, nil
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, err
+	}
+	var stored map[string]any
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return "", nil, err
+	}
+	return string(raw), stored, nil
+}
+
+// CreateAuditEntry добавляет запись в конец цепочки аудита.
+func (s *Storage) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	const op = "storage.audit.create"
+	before, storedBefore, err := auditSnapshot(entry.Before)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	after, storedAfter, err := auditSnapshot(entry.After)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	entry.Before, entry.After = storedBefore, storedAfter
+	entry.CreatedAt = domain.ChainTime(time.Now())
+	err = s.appendChain(ctx, domain.ChainAudit, func(link domain.ChainLink) (string, []any) {
+		entry.ChainLink = link
+		entry.Hash = domain.ChainHash(link.PrevHash, entry.ChainContent())
+		return `INSERT INTO "AuditLog" ("id", "actorId", "entityType", "entityId", "action", "before", "after",
+				"route", "requestId", "ip", "userAgent", "createdAt", "seq", "prevHash", "hash")
+			SELECT gen_random_uuid()::text, NULLIF($1, ''), $2::"AuditEntity", $3, $4::"AuditAction",
+				NULLIF($5, '')::jsonb, NULLIF($6, '')::jsonb, $7, $8, $9, $10, $11, $12, $13, $14`,
+			[]any{entry.ActorID, string(entry.EntityType), entry.EntityID, string(entry.Action), before, after,
+				entry.Route, entry.RequestID, entry.IP, entry.UserAgent, entry.CreatedAt, entry.Seq, entry.PrevHash, entry.Hash}
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) AuditEntries(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, error) {
+	const op = "storage.audit.all"
+	var where []db.AuditLogWhereParam
+	if filter.EntityType != "" {
+		where = append(where, db.AuditLog.EntityType.Equals(db.AuditEntity(filter.EntityType)))
+	}
+	if filter.EntityID != "" {
+		where = append(where, db.AuditLog.EntityID.Equals(filter.EntityID))
+	}
+	if filter.ActorID != "" {
+		where = append(where, db.AuditLog.ActorID.Equals(filter.ActorID))
+	}
+	if filter.Action != "" {
+		where = append(where, db.AuditLog.Action.Equals(db.AuditAction(filter.Action)))
+	}
+	if filter.From != nil {
+		where = append(where, db.AuditLog.CreatedAt.Gte(*filter.From))
+	}
+	if filter.To != nil {
+		where = append(where, db.AuditLog.CreatedAt.Lt(*filter.To))
+	}
+	skip, take := pagination(filter.Page, filter.Limit)
+	entriesDB, err := s.client.AuditLog.FindMany(where...).
+		OrderBy(db.AuditLog.CreatedAt.Order(db.DESC)).
+		Skip(skip).
+		Take(take).
+		Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries := make([]*domain.AuditEntry, 0, len(entriesDB))
+	for _, entryDB := range entriesDB {
+		entry := ValidateAuditEntry(entryDB)
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// CHAIN
+
+var chainTables = map[domain.Chain]string{
+	domain.ChainHistory: "ArticleHistory",
+	domain.ChainAudit:   "AuditLog",
+}
+
+type chainRow struct {
+	Seq      db.RawInt    `json:"seq"`
+	PrevHash db.RawString `json:"prevHash"`
+	Hash     db.RawString `json:"hash"`
+}
+
+// chainHead возвращает последнее звено цепочки; у пустой цепочки оно нулевое.
+func (s *Storage) chainHead(ctx context.Context, chain domain.Chain) (domain.ChainLink, error) {
+	var rows []chainRow
+	err := s.client.Prisma.QueryRaw(
+		`SELECT "seq", "prevHash", "hash" FROM "`+chainTables[chain]+`"
+		WHERE "seq" IS NOT NULL ORDER BY "seq" DESC LIMIT 1`,
+	).Exec(ctx, &rows)
+	if err != nil || len(rows) == 0 {
+		return domain.ChainLink{}, err
+	}
+	return domain.ChainLink{Seq: int(rows[0].Seq), PrevHash: string(rows[0].PrevHash), Hash: string(rows[0].Hash)}, nil
+}
+
+// appendChain добавляет запись следующим звеном цепочки. insert получает
+// звено (номер и хеш предыдущей записи) и возвращает INSERT ... SELECT без
+// WHERE. Вставка идёт под блокировкой цепочки и только если за звеном ещё
+// никого нет; иначе конец перечитывается и попытка повторяется, так что
+// параллельные записи выстраиваются друг за другом и цепочка не ветвится.
+// Каждая неудачная попытка значит, что цепочку продвинула другая запись,
+// поэтому попытки повторяются до отмены ctx, а запись не теряется.
+func (s *Storage) appendChain(ctx context.Context, chain domain.Chain, insert func(link domain.ChainLink) (string, []any)) error {
+	table := chainTables[chain]
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		head, err := s.chainHead(ctx, chain)
+		if err != nil {
+			return err
+		}
+		query, args := insert(domain.ChainLink{Seq: head.Seq + 1, PrevHash: head.Hash})
+		args = append(args, head.Seq)
+		lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext($1))`, "chain:"+string(chain)).Tx()
+		appended := s.client.Prisma.ExecuteRaw(
+			query+fmt.Sprintf(` WHERE NOT EXISTS (SELECT 1 FROM "%s" WHERE "seq" > $%d)`, table, len(args)),
+			args...,
+		).Tx()
+		if err := s.client.Prisma.Transaction(lock, appended).Exec(ctx); err != nil {
+			return err
+		}
+		if appended.Result().Count > 0 {
+			return nil
+		}
+	}
+}
+
+func (s *Storage) ChainRecords(ctx context.Context, chain domain.Chain, afterSeq int, limit int) ([]*domain.ChainRecord, error) {
+	const op = "storage.chain.records"
+	var records []*domain.ChainRecord
+	switch chain {
+	case domain.ChainHistory:
+		historiesDB, err := s.client.ArticleHistory.FindMany(db.ArticleHistory.Seq.Gt(afterSeq)).
+			OrderBy(db.ArticleHistory.Seq.Order(db.ASC)).
+			Take(limit).
+			Exec(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, historyDB := range historiesDB {
+			history := ValidateArticleHistory(historyDB)
+			records = append(records, &domain.ChainRecord{ID: history.ID, ChainLink: history.ChainLink, Content: history.ChainContent()})
+		}
+	case domain.ChainAudit:
+		entriesDB, err := s.client.AuditLog.FindMany(db.AuditLog.Seq.Gt(afterSeq)).
+			OrderBy(db.AuditLog.Seq.Order(db.ASC)).
+			Take(limit).
+			Exec(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		for _, entryDB := range entriesDB {
+			entry := ValidateAuditEntry(entryDB)
+			records = append(records, &domain.ChainRecord{ID: entry.ID, ChainLink: entry.ChainLink, Content: entry.ChainContent()})
+		}
+	default:
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrUnknownChain)
+	}
+	return records, nil
+}
+
+// UnchainedRecords ищет записи без номера, созданные не раньше первого звена.
+// В журнале аудита номер обязателен, поэтому там таких записей быть не может.
+func (s *Storage) UnchainedRecords(ctx context.Context, chain domain.Chain, limit int) ([]string, error) {
+	const op = "storage.chain.unchained"
+	if chain == domain.ChainAudit {
+		return nil, nil
+	}
+	if chain != domain.ChainHistory {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrUnknownChain)
+	}
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`SELECT "id" FROM "ArticleHistory"
+		WHERE "seq" IS NULL
+			AND "changedAt" >= (SELECT MIN("changedAt") FROM "ArticleHistory" WHERE "seq" IS NOT NULL)
+		ORDER BY "changedAt" LIMIT $1`,
+		limit,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, string(row.ID))
+	}
+	return ids, nil
+}
+
+// TIME ENTRY
+
+type timeEntryRow struct {
+	ID        db.RawString   `json:"id"`
+	TaskID    db.RawString   `json:"taskId"`
+	TaskTitle db.RawString   `json:"taskTitle"`
+	UserID    db.RawString   `json:"userId"`
+	UserName  db.RawString   `json:"userName"`
+	StartedAt db.RawDateTime `json:"startedAt"`
+	Seconds   db.RawInt      `json:"seconds"`
+	Note      db.RawString   `json:"note"`
+	Running   db.RawBoolean  `json:"running"`
+}
+
+// timeEntrySelect — выборка timeEntryRow; у запущенного таймера seconds = 0.
+const timeEntrySelect = `SELECT e."id", e."taskId", t."title" AS "taskTitle", e."userId", u."fullName" AS "userName",
+		e."startedAt", COALESCE(e."durationSeconds", 0) AS "seconds", e."note", e."endedAt" IS NULL AS "running"
+	FROM "TimeEntry" e
+	JOIN "Task" t ON t."id" = e."taskId"
+	JOIN "User" u ON u."id" = e."userId"`
+
+func (s *Storage) queryTimeEntries(ctx context.Context, query string, params ...any) ([]*domain.TimeEntry, error) {
+	var rows []timeEntryRow
+	if err := s.client.Prisma.QueryRaw(timeEntrySelect+" "+query, params...).Exec(ctx, &rows); err != nil {
+		return nil, err
+	}
+	entries := make([]*domain.TimeEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := &domain.TimeEntry{
+			ID:        string(row.ID),
+			TaskID:    string(row.TaskID),
+			TaskTitle: string(row.TaskTitle),
+			UserID:    string(row.UserID),
+			UserName:  string(row.UserName),
+			StartedAt: row.StartedAt.Time,
+			Seconds:   int(row.Seconds),
+			Note:      string(row.Note),
+			Running:   bool(row.Running),
+		}
+		if !entry.Running {
+			endedAt := entry.StartedAt.Add(time.Duration(entry.Seconds) * time.Second)
+			entry.EndedAt = &endedAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// StartTimer создаёт запущенную запись под блокировкой пользователя, чтобы два
+// параллельных запуска не создали два таймера. Возвращает false, если таймер уже идёт.
+func (s *Storage) StartTimer(ctx context.Context, entry *domain.TimeEntry) (bool, error) {
+	const op = "storage.time_entry.start"
+	lock := s.client.Prisma.ExecuteRaw(`SELECT pg_advisory_xact_lock(hashtext($1))`, "timer:"+entry.UserID).Tx()
+	insert := s.client.Prisma.ExecuteRaw(
+		`INSERT INTO "TimeEntry" ("id", "taskId", "userId", "startedAt", "note", "createdAt")
+		SELECT gen_random_uuid()::text, $1, $2, $3, $4, $3
+		WHERE NOT EXISTS (SELECT 1 FROM "TimeEntry" WHERE "userId" = $2 AND "endedAt" IS NULL)`,
+		entry.TaskID, entry.UserID, entry.StartedAt, entry.Note,
+	).Tx()
+	if err := s.client.Prisma.Transaction(lock, insert).Exec(ctx); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	if insert.Result().Count == 0 {
+		return false, nil
+	}
+	running, err := s.RunningTimer(ctx, entry.UserID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	entry.ID = running.ID
+	return true, nil
+}
+
+func (s *Storage) StopTimer(ctx context.Context, userID string, at time.Time) (*domain.TimeEntry, error) {
+	const op = "storage.time_entry.stop"
+	var rows []struct {
+		ID db.RawString `json:"id"`
+	}
+	err := s.client.Prisma.QueryRaw(
+		`UPDATE "TimeEntry" SET "endedAt" = $2,
+			"durationSeconds" = GREATEST(EXTRACT(EPOCH FROM ($2 - "startedAt"))::int, 0)
+		WHERE "userId" = $1 AND "endedAt" IS NULL
+		RETURNING "id"`,
+		userID, at,
+	).Exec(ctx, &rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	entry, err := s.TimeEntry(ctx, string(rows[0].ID))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entry, nil
+}
+
+func (s *Storage) RunningTimer(ctx context.Context, userID string) (*domain.TimeEntry, error) {
+	const op = "storage.time_entry.running"
+	entries, err := s.queryTimeEntries(ctx, `WHERE e."userId" = $1 AND e."endedAt" IS NULL`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, db.ErrNotFound)
+	}
+	return entries[0], nil
+}
+
+func (s *Storage) CreateTimeEntry(ctx context.Context, entry *domain.TimeEntry) (*domain.TimeEntry, error) {
+	const op = "storage.time_entry.create"
+	entryDB, err := s.client.TimeEntry.CreateOne(
+		db.TimeEntry.Task.Link(db.Task.ID.Equals(entry.TaskID)),
+		db.TimeEntry.User.Link(db.User.ID.Equals(entry.UserID)),
+		db.TimeEntry.StartedAt.Set(entry.StartedAt),
+		db.TimeEntry.EndedAt.SetIfPresent(entry.EndedAt),
+		db.TimeEntry.DurationSeconds.Set(entry.Seconds),
+		db.TimeEntry.Note.Set(entry.Note),
+	).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	created, err := s.TimeEntry(ctx, entryDB.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return created, nil
+}
+
+func (s *Storage) TimeEntry(ctx context.Context, id string) (*domain.TimeEntry, error) {
+	const op = "storage.time_entry.get"
+	entries, err := s.queryTimeEntries(ctx, `WHERE e."id" = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, db.ErrNotFound)
+	}
+	return entries[0], nil
+}
+
+func (s *Storage) DeleteTimeEntry(ctx context.Context, id string) error {
+	const op = "storage.time_entry.delete"
+	_, err := s.client.TimeEntry.FindUnique(db.TimeEntry.ID.Equals(id)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) TaskTimeEntries(ctx context.Context, taskID string) ([]*domain.TimeEntry, error) {
+	const op = "storage.time_entry.task"
+	entries, err := s.queryTimeEntries(ctx, `WHERE e."taskId" = $1 ORDER BY e."startedAt" DESC`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entries, nil
+}
+
+func (s *Storage) UserTimeEntries(ctx context.Context, userID string, from time.Time, to time.Time) ([]*domain.TimeEntry, error) {
+	const op = "storage.time_entry.user"
+	entries, err := s.queryTimeEntries(ctx,
+		`WHERE e."userId" = $1 AND e."startedAt" >= $2 AND e."startedAt" < $3 ORDER BY e."startedAt"`,
+		userID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return entries, nil
+}
+
+// ARTICLE LOCK
+
+// AcquireLock атомарно захватывает блокировку: вставляет новую или перезаписывает
+// существующую, если она истекла либо принадлежит тому же пользователю.
+func (s *Storage) AcquireLock(ctx context.Context, lock *domain.ArticleLock) (bool, error) {
+	const op = "storage.article_lock.acquire"
+	result, err := s.client.Prisma.ExecuteRaw(
+		`INSERT INTO "ArticleLock" ("articleId", "userId", "userName", "acquiredAt", "expiresAt", "leaseSeconds")
+		VALUES ($1, $2, $3, $4, $5, $6::int)
+		ON CONFLICT ("articleId") DO UPDATE SET
+			"userId" = EXCLUDED."userId",
+			"userName" = EXCLUDED."userName",
+			"acquiredAt" = CASE WHEN "ArticleLock"."userId" = EXCLUDED."userId" THEN "ArticleLock"."acquiredAt" ELSE EXCLUDED."acquiredAt" END,
+			"expiresAt" = EXCLUDED."expiresAt",
+			"leaseSeconds" = EXCLUDED."leaseSeconds"
+		WHERE "ArticleLock"."expiresAt" < $4 OR "ArticleLock"."userId" = EXCLUDED."userId"`,
+		lock.ArticleID, lock.UserID, lock.UserName, lock.AcquiredAt, lock.ExpiresAt, int(lock.Lease.Seconds()),
+	).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) ExtendLock(ctx context.Context, articleID string, userID string, expiresAt time.Time) (bool, error) {
+	const op = "storage.article_lock.extend"
+	result, err := s.client.ArticleLock.FindMany(
+		db.ArticleLock.ArticleID.Equals(articleID),
+		db.ArticleLock.UserID.Equals(userID),
+		db.ArticleLock.ExpiresAt.Gt(time.Now()),
+	).Update(
+		db.ArticleLock.ExpiresAt.Set(expiresAt),
+	).Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) ArticleLock(ctx context.Context, articleID string) (*domain.ArticleLock, error) {
+	const op = "storage.article_lock.get"
+	lockDB, err := s.client.ArticleLock.FindUnique(db.ArticleLock.ArticleID.Equals(articleID)).Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	lock := ValidateArticleLock(*lockDB)
+	return &lock, nil
+}
+
+func (s *Storage) DeleteLock(ctx context.Context, articleID string) error {
+	const op = "storage.article_lock.delete"
+	_, err := s.client.ArticleLock.FindUnique(db.ArticleLock.ArticleID.Equals(articleID)).Delete().Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (s *Storage) DeleteUserLock(ctx context.Context, articleID string, userID string) (bool, error) {
+	const op = "storage.article_lock.delete_user"
+	result, err := s.client.ArticleLock.FindMany(
+		db.ArticleLock.ArticleID.Equals(articleID),
+		db.ArticleLock.UserID.Equals(userID),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count > 0, nil
+}
+
+func (s *Storage) DeleteExpiredLocks(ctx context.Context, now time.Time) (int, error) {
+	const op = "storage.article_lock.delete_expired"
+	result, err := s.client.ArticleLock.FindMany(
+		db.ArticleLock.ExpiresAt.Lt(now),
+	).Delete().Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return result.Count, nil
+}
@@ -1,33 +1,43 @@
 package prisma
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/immxrtalbeast/TTK_backend/internal/domain"
 	"github.com/immxrtalbeast/TTK_backend/storage/prisma/db"
 )
 
 func ValidateUser(userDB db.UserModel) domain.User {
 	user := domain.User{
-		ID:        userDB.ID,
-		Name:      userDB.FullName,
-		Login:     userDB.Login,
-		PassHash:  []byte(userDB.PasswordHash),
-		CreatedAt: userDB.CreatedAt,
-		IsAdmin:   domain.Role(userDB.Role),
+		ID:              userDB.ID,
+		Name:            userDB.FullName,
+		Login:           userDB.Login,
+		PassHash:        []byte(userDB.PasswordHash),
+		CreatedAt:       userDB.CreatedAt,
+		IsAdmin:         domain.Role(userDB.Role),
+		ReminderOffsets: userDB.ReminderOffsets,
 	}
 	return user
 }
 
 func ValidateArticle(articleDB db.ArticleModel) domain.Article {
 	content, _ := articleDB.Content()
+	sourceID, _ := articleDB.SourceID()
+	sourceRevision, _ := articleDB.SourceRevision()
 	atricle := domain.Article{
-		ID:         articleDB.ID,
-		Title:      articleDB.Title,
-		UpdatedAt:  articleDB.UpdatedAt,
-		CreatedAt:  articleDB.CreatedAt,
-		Creator:    articleDB.CreatorName,
-		LastEditor: articleDB.LastEditorName,
-		Image:      articleDB.Image,
-		Content:    content,
+		ID:             articleDB.ID,
+		Title:          articleDB.Title,
+		UpdatedAt:      articleDB.UpdatedAt,
+		CreatedAt:      articleDB.CreatedAt,
+		Creator:        articleDB.CreatorName,
+		LastEditor:     articleDB.LastEditorName,
+		Image:          articleDB.Image,
+		Content:        content,
+		Language:       articleDB.Language,
+		SourceID:       sourceID,
+		Revision:       articleDB.Revision,
+		SourceRevision: sourceRevision,
 	}
 	return atricle
 
@@ -50,21 +60,346 @@ func ValidateArticleHistory(historyDB db.ArticleHistoryModel) domain.History {
 		EventType:    domain.EventType(historyDB.EventType),
 		ArticleTitle: historyDB.ArticleTitle,
 	}
+	history.Seq, _ = historyDB.Seq()
+	history.PrevHash, _ = historyDB.PrevHash()
+	history.Hash, _ = historyDB.Hash()
 	return history
 }
 
 func ValidateTask(taskDB db.TaskModel) domain.Task {
 	respUser := taskDB.Responsibleuser()
 	user := ValidateUser(*respUser)
+	creatorID, _ := taskDB.CreatorID()
+	parentID, _ := taskDB.ParentID()
+	recurrenceID, _ := taskDB.RecurrenceID()
+	sprintID, _ := taskDB.SprintID()
+	projectID, _ := taskDB.ProjectID()
+	number, _ := taskDB.Number()
 	task := domain.Task{
 		ID:               taskDB.ID,
 		Title:            taskDB.Title,
+		Content:          taskDB.Content,
+		Image:            taskDB.Image,
 		ReliableUserName: user.Name,
+		UserLogin:        user.Login,
 		UserID:           taskDB.UserID,
+		CreatorID:        creatorID,
 		PlannedAt:        taskDB.PlannedAt,
 		CreatedAt:        taskDB.CreatedAt,
+		UpdatedAt:        taskDB.UpdatedAt,
 		Priority:         domain.Priority(taskDB.Priority),
 		Status:           domain.Status(taskDB.Status),
+		Rank:             taskDB.Rank,
+		ParentID:         parentID,
+		RecurrenceID:     recurrenceID,
+		Occurrence:       taskDB.Occurrence,
+		SprintID:         sprintID,
+		ProjectID:        projectID,
+		Number:           number,
+	}
+	if creator, ok := taskDB.Creator(); ok {
+		task.CreatorName = creator.FullName
+	}
+	if startedAt, ok := taskDB.StartedAt(); ok {
+		task.StartedAt = &startedAt
+	}
+	if completedAt, ok := taskDB.CompletedAt(); ok {
+		task.CompletedAt = &completedAt
+	}
+	if overdueAt, ok := taskDB.OverdueAt(); ok {
+		task.OverdueAt = &overdueAt
+	}
+	if estimate, ok := taskDB.Estimate(); ok {
+		task.Estimate = &estimate
+	}
+	if project, ok := taskDB.Project(); ok {
+		task.ProjectKey = project.Key
+		task.Key = domain.TaskKey(project.Key, number)
+	}
+	// метки и поля есть, только если загружены (см. taskRelations)
+	for _, taskLabel := range taskDB.RelationsTask.Labels {
+		if taskLabel.RelationsTaskLabel.Label != nil {
+			label := ValidateLabel(*taskLabel.RelationsTaskLabel.Label)
+			task.Labels = append(task.Labels, &label)
+		}
+	}
+	for _, valueDB := range taskDB.RelationsTask.FieldValues {
+		if valueDB.RelationsTaskFieldValue.Field != nil {
+			value := ValidateFieldValue(valueDB)
+			task.Fields = append(task.Fields, &value)
+		}
 	}
 	return task
 }
+
+func ValidateTaskTransition(transitionDB db.TaskTransitionModel) domain.TaskTransition {
+	transition := domain.TaskTransition{
+		ID:        transitionDB.ID,
+		TaskID:    transitionDB.TaskID,
+		From:      domain.Status(transitionDB.FromStatus),
+		To:        domain.Status(transitionDB.ToStatus),
+		UserID:    transitionDB.UserID,
+		ChangedAt: transitionDB.ChangedAt,
+	}
+	return transition
+}
+
+func ValidateTaskAssignment(assignmentDB db.TaskAssignmentModel) domain.TaskAssignment {
+	fromUserID, _ := assignmentDB.FromUserID()
+	assignment := domain.TaskAssignment{
+		ID:          assignmentDB.ID,
+		TaskID:      assignmentDB.TaskID,
+		FromUserID:  fromUserID,
+		ToUserID:    assignmentDB.ToUserID,
+		ChangedByID: assignmentDB.ChangedByID,
+		ChangedAt:   assignmentDB.ChangedAt,
+	}
+	return assignment
+}
+
+func ValidateArticleLock(lockDB db.ArticleLockModel) domain.ArticleLock {
+	lock := domain.ArticleLock{
+		ArticleID:  lockDB.ArticleID,
+		UserID:     lockDB.UserID,
+		UserName:   lockDB.UserName,
+		AcquiredAt: lockDB.AcquiredAt,
+		ExpiresAt:  lockDB.ExpiresAt,
+		Lease:      time.Duration(lockDB.LeaseSeconds) * time.Second,
+	}
+	return lock
+}
+
+func ValidateChecklistItem(itemDB db.ChecklistItemModel) domain.ChecklistItem {
+	return domain.ChecklistItem{
+		ID:        itemDB.ID,
+		TaskID:    itemDB.TaskID,
+		Text:      itemDB.Text,
+		Done:      itemDB.Done,
+		Position:  itemDB.Position,
+		CreatedAt: itemDB.CreatedAt,
+	}
+}
+
+func ValidateTaskDependency(dependencyDB db.TaskDependencyModel) domain.TaskDependency {
+	return domain.TaskDependency{
+		ID:          dependencyDB.ID,
+		BlockerID:   dependencyDB.BlockerID,
+		BlockedID:   dependencyDB.BlockedID,
+		CreatedByID: dependencyDB.CreatedByID,
+		CreatedAt:   dependencyDB.CreatedAt,
+	}
+}
+
+func ValidateRecurrence(recurrenceDB db.TaskRecurrenceModel) domain.Recurrence {
+	projectID, _ := recurrenceDB.ProjectID()
+	recurrence := domain.Recurrence{
+		ID:          recurrenceDB.ID,
+		Frequency:   domain.RecurrenceFrequency(recurrenceDB.Frequency),
+		Interval:    recurrenceDB.Interval,
+		Mode:        domain.RecurrenceMode(recurrenceDB.Mode),
+		StartAt:     recurrenceDB.StartAt,
+		Slot:        recurrenceDB.Slot,
+		NextAt:      recurrenceDB.NextAt,
+		Occurrences: recurrenceDB.Occurrences,
+		Active:      recurrenceDB.Active,
+		Title:       recurrenceDB.Title,
+		Content:     recurrenceDB.Content,
+		Image:       recurrenceDB.Image,
+		Priority:    domain.Priority(recurrenceDB.Priority),
+		UserID:      recurrenceDB.UserID,
+		CreatorID:   recurrenceDB.CreatorID,
+		ProjectID:   projectID,
+		CreatedAt:   recurrenceDB.CreatedAt,
+	}
+	if until, ok := recurrenceDB.Until(); ok {
+		recurrence.Until = &until
+	}
+	if count, ok := recurrenceDB.Count(); ok {
+		recurrence.Count = count
+	}
+	return recurrence
+}
+
+func ValidateNotification(notificationDB db.NotificationModel) domain.Notification {
+	taskID, _ := notificationDB.TaskID()
+	notification := domain.Notification{
+		ID:        notificationDB.ID,
+		UserID:    notificationDB.UserID,
+		Kind:      domain.NotificationKind(notificationDB.Kind),
+		TaskID:    taskID,
+		Message:   notificationDB.Message,
+		CreatedAt: notificationDB.CreatedAt,
+	}
+	if readAt, ok := notificationDB.ReadAt(); ok {
+		notification.ReadAt = &readAt
+	}
+	return notification
+}
+
+func ValidateSprint(sprintDB db.SprintModel) domain.Sprint {
+	return domain.Sprint{
+		ID:          sprintDB.ID,
+		Name:        sprintDB.Name,
+		Goal:        sprintDB.Goal,
+		StartAt:     sprintDB.StartAt,
+		EndAt:       sprintDB.EndAt,
+		Unit:        domain.EstimateUnit(sprintDB.Unit),
+		CreatedByID: sprintDB.CreatedByID,
+		CreatedAt:   sprintDB.CreatedAt,
+	}
+}
+
+func ValidateLabel(labelDB db.LabelModel) domain.Label {
+	projectID, _ := labelDB.ProjectID()
+	return domain.Label{
+		ID:        labelDB.ID,
+		Name:      labelDB.Name,
+		Color:     labelDB.Color,
+		ProjectID: projectID,
+		CreatedAt: labelDB.CreatedAt,
+	}
+}
+
+func ValidateCustomField(fieldDB db.CustomFieldModel) domain.CustomField {
+	projectID, _ := fieldDB.ProjectID()
+	return domain.CustomField{
+		ID:        fieldDB.ID,
+		Name:      fieldDB.Name,
+		Type:      domain.FieldType(fieldDB.Type),
+		Options:   fieldDB.Options,
+		ProjectID: projectID,
+		CreatedAt: fieldDB.CreatedAt,
+	}
+}
+
+// ValidateFieldValue ожидает значение, загруженное вместе с полем.
+func ValidateFieldValue(valueDB db.TaskFieldValueModel) domain.FieldValue {
+	field := valueDB.Field()
+	return domain.FieldValue{
+		FieldID: valueDB.FieldID,
+		Name:    field.Name,
+		Type:    domain.FieldType(field.Type),
+		Value:   valueDB.Value,
+	}
+}
+
+func ValidateProject(projectDB db.ProjectModel) domain.Project {
+	return domain.Project{
+		ID:          projectDB.ID,
+		Key:         projectDB.Key,
+		Name:        projectDB.Name,
+		Description: projectDB.Description,
+		TaskCounter: projectDB.TaskCounter,
+		CreatedByID: projectDB.CreatedByID,
+		CreatedAt:   projectDB.CreatedAt,
+	}
+}
+
+// ValidateProjectMember ожидает участника, загруженного вместе с пользователем.
+func ValidateProjectMember(memberDB db.ProjectMemberModel) domain.ProjectMembership {
+	member := domain.ProjectMembership{
+		ProjectID: memberDB.ProjectID,
+		UserID:    memberDB.UserID,
+		Role:      domain.ProjectRole(memberDB.Role),
+		JoinedAt:  memberDB.JoinedAt,
+	}
+	if memberDB.RelationsProjectMember.User != nil {
+		member.UserName = memberDB.RelationsProjectMember.User.FullName
+	}
+	return member
+}
+
+func ValidateTaskWatcher(watcherDB db.TaskWatcherModel) domain.TaskWatcher {
+	watcher := domain.TaskWatcher{
+		TaskID:    watcherDB.TaskID,
+		UserID:    watcherDB.UserID,
+		CreatedAt: watcherDB.CreatedAt,
+	}
+	if watcherDB.RelationsTaskWatcher.User != nil {
+		watcher.UserName = watcherDB.RelationsTaskWatcher.User.FullName
+	}
+	return watcher
+}
+
+func ValidateTaskComment(commentDB db.TaskCommentModel) domain.TaskComment {
+	comment := domain.TaskComment{
+		ID:        commentDB.ID,
+		TaskID:    commentDB.TaskID,
+		AuthorID:  commentDB.AuthorID,
+		Body:      commentDB.Body,
+		CreatedAt: commentDB.CreatedAt,
+	}
+	if commentDB.RelationsTaskComment.Author != nil {
+		comment.AuthorName = commentDB.RelationsTaskComment.Author.FullName
+	}
+	return comment
+}
+
+func ValidateTaskArticleLink(linkDB db.TaskArticleLinkModel) domain.TaskArticleLink {
+	link := domain.TaskArticleLink{
+		TaskID:         linkDB.TaskID,
+		ArticleID:      linkDB.ArticleID,
+		ArticleTitle:   linkDB.ArticleTitle,
+		Kind:           domain.ArticleLinkKind(linkDB.Kind),
+		LinkedRevision: linkDB.LinkedRevision,
+		CreatedByID:    linkDB.CreatedByID,
+		CreatedAt:      linkDB.CreatedAt,
+	}
+	if linkDB.RelationsTaskArticleLink.Task != nil {
+		link.TaskTitle = linkDB.RelationsTaskArticleLink.Task.Title
+	}
+	return link
+}
+
+func ValidateTaskTemplate(templateDB db.TaskTemplateModel) domain.TaskTemplate {
+	projectID, _ := templateDB.ProjectID()
+	parentID, _ := templateDB.ParentID()
+	template := domain.TaskTemplate{
+		ID:             templateDB.ID,
+		Name:           templateDB.Name,
+		TitlePattern:   templateDB.TitlePattern,
+		Content:        templateDB.Content,
+		Priority:       domain.Priority(templateDB.Priority),
+		Checklist:      templateDB.Checklist,
+		DueOffsetHours: templateDB.DueOffsetHours,
+		ProjectID:      projectID,
+		ParentID:       parentID,
+		Position:       templateDB.Position,
+		CreatedByID:    templateDB.CreatedByID,
+		CreatedAt:      templateDB.CreatedAt,
+	}
+	for _, stepDB := range templateDB.RelationsTaskTemplate.Steps {
+		step := ValidateTaskTemplate(stepDB)
+		template.Steps = append(template.Steps, &step)
+	}
+	return template
+}
+
+func ValidateAuditEntry(entryDB db.AuditLogModel) domain.AuditEntry {
+	actorID, _ := entryDB.ActorID()
+	entry := domain.AuditEntry{
+		ID:         entryDB.ID,
+		ActorID:    actorID,
+		EntityType: domain.AuditEntity(entryDB.EntityType),
+		EntityID:   entryDB.EntityID,
+		Action:     domain.AuditAction(entryDB.Action),
+		Route:      entryDB.Route,
+		RequestID:  entryDB.RequestID,
+		IP:         entryDB.IP,
+		UserAgent:  entryDB.UserAgent,
+		CreatedAt:  entryDB.CreatedAt,
+		ChainLink: domain.ChainLink{
+			Seq:      entryDB.Seq,
+			PrevHash: entryDB.PrevHash,
+			Hash:     entryDB.Hash,
+		},
+	}
+	// снимки пишет CreateAuditEntry, так что ошибка разбора не ожидается
+	if before, ok := entryDB.Before(); ok {
+		_ = json.Unmarshal(before, &entry.Before)
+	}
+	if after, ok := entryDB.After(); ok {
+		_ = json.Unmarshal(after, &entry.After)
+	}
+	return entry
+}